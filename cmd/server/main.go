@@ -3,17 +3,41 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"mcp-typescript-assistant/internal/server"
+	"mcp-typescript-assistant/internal/tools"
 )
 
 func main() {
-	// Set up logging
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	// Load the configuration first since it decides the log level
+	mcpServer, err := server.NewTypeScriptMCPServer()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Set up logging; everything goes to stderr since stdout carries the MCP protocol
+	cfg := mcpServer.Config()
+	slog.SetDefault(cfg.NewLogger())
+
+	if cfg.Level() <= slog.LevelDebug {
+		printUsage()
+	}
+	if cfg.File != "" {
+		slog.Info("Loaded configuration file", "path", cfg.File)
+	}
+
+	// Snippets and inline content are written to scratch files; remove any left behind
+	// by servers that didn't shut down cleanly
+	if removed, err := tools.CleanStaleScratch(); err != nil {
+		slog.Warn("Failed to clean stale scratch files", "error", err)
+	} else if removed > 0 {
+		slog.Info("Removed stale scratch directories", "count", removed)
+	}
 	
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -25,29 +49,27 @@ func main() {
 	
 	go func() {
 		<-sigCh
-		log.Println("Received shutdown signal")
+		slog.Info("Received shutdown signal")
 		cancel()
 	}()
 
-	// Create and start the MCP server
-	mcpServer := server.NewTypeScriptMCPServer()
-	
-	log.Println("TypeScript MCP Server starting...")
+	slog.Info("TypeScript MCP Server starting")
 	
-	if err := mcpServer.Run(ctx); err != nil {
-		log.Fatalf("Server error: %v", err)
+	err = mcpServer.Run(ctx)
+	if removeErr := tools.RemoveScratch(); removeErr != nil {
+		slog.Warn("Failed to remove scratch files", "error", removeErr)
+	}
+	if err != nil {
+		slog.Error("Server error", "error", err)
+		os.Exit(1)
 	}
 	
-	log.Println("TypeScript MCP Server stopped")
+	slog.Info("TypeScript MCP Server stopped")
 }
 
-func init() {
-	// Check for required environment variables or configuration
-	if os.Getenv("DEBUG") == "true" {
-		log.Println("Debug mode enabled")
-	}
-	
-	// Print usage information to stderr (since stdout is used for MCP communication)
+// printUsage prints the available tools and prerequisites to stderr (since stdout is
+// used for MCP communication). It is only shown at the debug log level.
+func printUsage() {
 	fmt.Fprintln(os.Stderr, "TypeScript MCP Server - Model Context Protocol server for TypeScript development tools")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Available tools:")
@@ -56,6 +78,28 @@ func init() {
 	fmt.Fprintln(os.Stderr, "  - lint-check: Run ESLint checking")
 	fmt.Fprintln(os.Stderr, "  - suggest-improvements: Suggest code improvements")
 	fmt.Fprintln(os.Stderr, "  - load-guidelines: Load custom coding guidelines")
+	fmt.Fprintln(os.Stderr, "  - estimate-size: Estimate token size of code")
+	fmt.Fprintln(os.Stderr, "  - config-dump: Show effective server configuration")
+	fmt.Fprintln(os.Stderr, "  - server-info: Show server capabilities and tool status")
+	fmt.Fprintln(os.Stderr, "  - transpile: Compile TypeScript to JavaScript")
+	fmt.Fprintln(os.Stderr, "  - validate-guidelines: Validate a guideline file without loading it")
+	fmt.Fprintln(os.Stderr, "  - review: Type-check, lint and suggest improvements for a file")
+	fmt.Fprintln(os.Stderr, "  - run-tests: Run jest or vitest tests")
+	fmt.Fprintln(os.Stderr, "  - check-dependencies: Find outdated and vulnerable npm packages")
+	fmt.Fprintln(os.Stderr, "  - import-graph: Map module imports and detect cycles")
+	fmt.Fprintln(os.Stderr, "  - apply-improvement: Apply a suggested improvement to a file")
+	fmt.Fprintln(os.Stderr, "  - doctor: Diagnose the environment")
+	fmt.Fprintln(os.Stderr, "  - find-references: Find every reference to a symbol")
+	fmt.Fprintln(os.Stderr, "  - go-to-definition: Find where a symbol is defined")
+	fmt.Fprintln(os.Stderr, "  - rename-symbol: Rename a symbol across the project")
+	fmt.Fprintln(os.Stderr, "  - complete: List code completions at a position")
+	fmt.Fprintln(os.Stderr, "  - init-tsconfig: Generate a recommended tsconfig.json")
+	fmt.Fprintln(os.Stderr, "  - audit-tsconfig: Audit a tsconfig's strictness flags")
+	fmt.Fprintln(os.Stderr, "  - type-check-diff: Report only type errors missing from a baseline")
+	fmt.Fprintln(os.Stderr, "  - get-types-batch: Extract type information for several symbols")
+	fmt.Fprintln(os.Stderr, "  - resolve-module: Find where an import's types are declared")
+	fmt.Fprintln(os.Stderr, "  - list-eslint-rules: List the ESLint rules configured for a file")
+	fmt.Fprintln(os.Stderr, "  - stats: Show tool usage counters")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Prerequisites:")
 	fmt.Fprintln(os.Stderr, "  - TypeScript: npm install -g typescript")
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"mcp-typescript-assistant/internal/typescript"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// todoCheck is a custom analyzer check that flags TODO comments left in the code
+type todoCheck struct{}
+
+func (todoCheck) Name() string {
+	return "todo_comments"
+}
+
+func (todoCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	for i, line := range strings.Split(code, "\n") {
+		column := strings.Index(line, "// TODO")
+		if column < 0 {
+			continue
+		}
+		improvements = append(improvements, types.Improvement{
+			Type:        "maintainability",
+			Description: "Resolve the TODO or track it in an issue",
+			Before:      strings.TrimSpace(line[column:]),
+			Reasoning:   "TODO comments tend to outlive the context they were written in",
+			Priority:    "low",
+			Line:        i + 1,
+			Column:      column + 1,
+		})
+	}
+
+	return improvements
+}
+
+// Registers a custom check alongside the built-in ones and runs an analysis.
+// Run with: go run examples/custom-check.go
+func main() {
+	analyzer := typescript.NewAnalyzer()
+	if err := analyzer.RegisterCheck(todoCheck{}); err != nil {
+		log.Fatalf("Failed to register check: %v", err)
+	}
+
+	code := `
+export function total(prices: number[]): number {
+  // TODO: apply discounts
+  return prices.reduce((sum, price) => sum + price, 0);
+}
+`
+
+	// Custom checks can be selected and disabled by name like the built-in ones
+	result, err := analyzer.SuggestImprovements(types.SuggestImprovementsParams{
+		CodeSnippet:   code,
+		EnabledChecks: []string{"todo_comments", "unused"},
+	})
+	if err != nil {
+		log.Fatalf("Analysis failed: %v", err)
+	}
+
+	fmt.Printf("Checks run: %s\n", strings.Join(result.AppliedRules, ", "))
+	for _, improvement := range result.Improvements {
+		fmt.Printf("  line %d: [%s] %s: %s\n", improvement.Line, improvement.Priority, improvement.Description, improvement.Before)
+	}
+}
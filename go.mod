@@ -2,4 +2,8 @@ module mcp-typescript-assistant
 
 go 1.24.0
 
-require github.com/modelcontextprotocol/go-sdk v0.1.0
+require (
+	github.com/modelcontextprotocol/go-sdk v0.1.0
+	golang.org/x/sync v0.16.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMinNodeVersion is the oldest Node.js major version current TypeScript and ESLint releases support
+const DefaultMinNodeVersion = 18
+
+// Config holds server-wide settings shared by all tools. It doubles as the schema of
+// the tsassistant.config.json/.yaml file; environment variables override file values.
+type Config struct {
+	// IgnorePaths lists glob patterns for files that no tool should analyze
+	IgnorePaths []string `json:"ignore_paths,omitempty" yaml:"ignore_paths"`
+	// AllowedRoots restricts the paths tool calls may name to these directories, after
+	// resolving ".." and symlinks (no restriction when empty)
+	AllowedRoots []string `json:"allowed_roots,omitempty" yaml:"allowed_roots"`
+	// PackageManager overrides lockfile detection when running tsc and eslint ("npm", "pnpm" or "yarn")
+	PackageManager string `json:"package_manager,omitempty" yaml:"package_manager"`
+	// ProjectRoot is where local node_modules/.bin binaries are looked up when a call has no path
+	ProjectRoot string `json:"project_root,omitempty" yaml:"project_root"`
+	// CacheDir holds server-managed caches such as incremental .tsbuildinfo files
+	CacheDir string `json:"cache_dir,omitempty" yaml:"cache_dir"`
+	// LogLevel is the minimum level written to stderr ("debug", "info", "warn" or "error")
+	LogLevel string `json:"log_level,omitempty" yaml:"log_level"`
+	// MinNodeVersion is the Node.js major version below which the server warns
+	MinNodeVersion int `json:"min_node_version,omitempty" yaml:"min_node_version"`
+	// CommandTimeout bounds each external command such as tsc or eslint, e.g. "2m" (no limit when empty)
+	CommandTimeout string `json:"command_timeout,omitempty" yaml:"command_timeout"`
+	// CommandRetries is how often a tsc or eslint run is retried after a transient npx or
+	// network failure (no retries when zero)
+	CommandRetries int `json:"command_retries,omitempty" yaml:"command_retries"`
+	// NodeOptions is added to NODE_OPTIONS for every tsc, eslint and test run, e.g.
+	// "--max-old-space-size=8192" for large projects that run out of heap
+	NodeOptions string `json:"node_options,omitempty" yaml:"node_options"`
+	// Env sets extra environment variables for every external command
+	Env map[string]string `json:"env,omitempty" yaml:"env"`
+	// TSConfig is the tsconfig file used in project mode, relative to the project root
+	TSConfig string `json:"tsconfig,omitempty" yaml:"tsconfig"`
+	// EnabledChecks and DisabledChecks set the default analyzer checks for suggest-improvements
+	EnabledChecks  []string `json:"enabled_checks,omitempty" yaml:"enabled_checks"`
+	DisabledChecks []string `json:"disabled_checks,omitempty" yaml:"disabled_checks"`
+	// ComplexityThreshold is the default cyclomatic complexity above which suggest-improvements
+	// flags a function (10 when zero)
+	ComplexityThreshold int `json:"complexity_threshold,omitempty" yaml:"complexity_threshold"`
+	// NamingRules maps declaration kinds to the regex their names must match, on top of
+	// the built-in rules that interfaces start uppercase and variables don't
+	NamingRules map[string]string `json:"naming_rules,omitempty" yaml:"naming_rules"`
+	// BaseRef is the default git ref that changed_only requests compare against (HEAD when empty)
+	BaseRef string `json:"base_ref,omitempty" yaml:"base_ref"`
+	// File is the configuration file the settings were read from, if any
+	File string `json:"config_file,omitempty" yaml:"-"`
+}
+
+// LoadFromEnv builds the server configuration from environment variables alone
+func LoadFromEnv() *Config {
+	cfg := &Config{}
+	cfg.applyEnv()
+	cfg.applyDefaults()
+	return cfg
+}
+
+// applyEnv overrides settings with the environment variables that are set
+func (c *Config) applyEnv() {
+	if paths := splitList(os.Getenv("IGNORE_PATHS")); len(paths) > 0 {
+		c.IgnorePaths = paths
+	}
+	if roots := splitList(os.Getenv("ALLOWED_ROOTS")); len(roots) > 0 {
+		c.AllowedRoots = roots
+	}
+	setFromEnv(&c.PackageManager, "PACKAGE_MANAGER")
+	setFromEnv(&c.ProjectRoot, "PROJECT_ROOT")
+	setFromEnv(&c.CacheDir, "CACHE_DIR")
+	setFromEnv(&c.CommandTimeout, "COMMAND_TIMEOUT")
+	setFromEnv(&c.TSConfig, "TSCONFIG")
+	setFromEnv(&c.BaseRef, "BASE_REF")
+	setFromEnv(&c.NodeOptions, "TOOL_NODE_OPTIONS")
+	if env := splitList(os.Getenv("TOOL_ENV")); len(env) > 0 {
+		c.Env = make(map[string]string, len(env))
+		for _, entry := range env {
+			if key, value, ok := strings.Cut(entry, "="); ok && key != "" {
+				c.Env[key] = value
+			}
+		}
+	}
+	if checks := splitList(os.Getenv("ENABLED_CHECKS")); len(checks) > 0 {
+		c.EnabledChecks = checks
+	}
+	if checks := splitList(os.Getenv("DISABLED_CHECKS")); len(checks) > 0 {
+		c.DisabledChecks = checks
+	}
+	if level := logLevelFromEnv(); level != "" {
+		c.LogLevel = level
+	}
+	if version := minNodeVersionFromEnv(); version > 0 {
+		c.MinNodeVersion = version
+	}
+	if retries, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COMMAND_RETRIES"))); err == nil && retries >= 0 {
+		c.CommandRetries = retries
+	}
+	if threshold, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COMPLEXITY_THRESHOLD"))); err == nil && threshold > 0 {
+		c.ComplexityThreshold = threshold
+	}
+}
+
+// applyDefaults fills in settings that neither the file nor the environment set
+func (c *Config) applyDefaults() {
+	if c.LogLevel == "" {
+		c.LogLevel = "info"
+	}
+	c.LogLevel = strings.ToLower(c.LogLevel)
+	if c.MinNodeVersion <= 0 {
+		c.MinNodeVersion = DefaultMinNodeVersion
+	}
+}
+
+// setFromEnv sets *field to the trimmed value of the environment variable key, if non-empty
+func setFromEnv(field *string, key string) {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		*field = value
+	}
+}
+
+// Timeout returns CommandTimeout as a duration, or zero when it is unset or invalid
+func (c *Config) Timeout() time.Duration {
+	timeout, err := time.ParseDuration(c.CommandTimeout)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// logLevelFromEnv reads LOG_LEVEL, falling back to "debug" when DEBUG=true and "" otherwise
+func logLevelFromEnv() string {
+	if level := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_LEVEL"))); level != "" {
+		return level
+	}
+	if os.Getenv("DEBUG") == "true" {
+		return "debug"
+	}
+	return ""
+}
+
+// minNodeVersionFromEnv reads MIN_NODE_VERSION, returning 0 when it is unset or invalid
+func minNodeVersionFromEnv() int {
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(os.Getenv("MIN_NODE_VERSION")), "v"))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// Level returns the slog level for LogLevel, defaulting to info for unknown values
+func (c *Config) Level() slog.Level {
+	switch c.LogLevel {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// NewLogger returns a logger writing to stderr at the configured level; stdout is
+// reserved for the MCP protocol
+func (c *Config) NewLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: c.Level()}))
+}
+
+// Redacted returns a copy of the configuration that is safe to expose to clients.
+// Any setting holding credentials must be masked here.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.IgnorePaths = append([]string(nil), c.IgnorePaths...)
+	redacted.AllowedRoots = append([]string(nil), c.AllowedRoots...)
+	redacted.EnabledChecks = append([]string(nil), c.EnabledChecks...)
+	redacted.DisabledChecks = append([]string(nil), c.DisabledChecks...)
+	// Extra environment variables often carry tokens, so only their names are shown
+	if c.Env != nil {
+		redacted.Env = make(map[string]string, len(c.Env))
+		for key := range c.Env {
+			redacted.Env[key] = "***"
+		}
+	}
+	return &redacted
+}
+
+// splitList splits a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable that points at a configuration file
+const FileEnv = "TSASSISTANT_CONFIG"
+
+// fileNames are the configuration files looked for in the working directory, in order
+var fileNames = []string{"tsassistant.config.json", "tsassistant.config.yaml", "tsassistant.config.yml"}
+
+// Load builds the server configuration from the configuration file, if any, with
+// environment variables taking precedence over its values
+func Load() (*Config, error) {
+	cfg := &Config{}
+
+	path, err := findFile()
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+		cfg.File = path
+	}
+
+	cfg.applyEnv()
+	cfg.applyDefaults()
+	return cfg, nil
+}
+
+// findFile returns the file named by TSASSISTANT_CONFIG, or the first configuration
+// file in the working directory, or "" when there is none
+func findFile() (string, error) {
+	if path := strings.TrimSpace(os.Getenv(FileEnv)); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file from %s not found: %w", FileEnv, err)
+		}
+		return path, nil
+	}
+
+	for _, name := range fileNames {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// readFile decodes a JSON or YAML configuration file into c, rejecting unknown
+// settings so typos don't go unnoticed
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(c)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(c)
+	}
+	// An empty file leaves every setting at its default
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return nil
+}
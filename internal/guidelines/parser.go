@@ -1,8 +1,8 @@
 package guidelines
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -17,6 +17,9 @@ type Parser struct {
 	headerRegex   *regexp.Regexp
 	codeRegex     *regexp.Regexp
 	listRegex     *regexp.Regexp
+	labelRegex    *regexp.Regexp
+	badRegex      *regexp.Regexp
+	goodRegex     *regexp.Regexp
 }
 
 // NewParser creates a new guideline parser
@@ -25,6 +28,9 @@ func NewParser() *Parser {
 		headerRegex: regexp.MustCompile(`^#+\s+(.+)$`),
 		codeRegex:   regexp.MustCompile("```(typescript|ts|javascript|js)?([\\s\\S]*?)```"),
 		listRegex:   regexp.MustCompile(`^[\*\-\+]\s+(.+)$`),
+		labelRegex:  regexp.MustCompile(`^(?:\*\*|__)(.+?)(?:\*\*|__):?$|^([^:]{1,40}):$`),
+		badRegex:    regexp.MustCompile(`\b(bad|incorrect|don'?t|avoid|wrong|never)\b`),
+		goodRegex:   regexp.MustCompile(`\b(good|correct|do|prefer|preferred|recommended|better|right)\b`),
 	}
 }
 
@@ -40,12 +46,25 @@ func (p *Parser) ParseGuidelinesFromFile(filePath, guidelineType string) (*types
 	if err != nil {
 		return nil, fmt.Errorf("failed to read guideline file: %w", err)
 	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("guideline file %s is empty", filePath)
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return p.parseStructured(content, filepath.Base(filePath), "json")
+	case ".yaml", ".yml":
+		return p.parseStructured(content, filepath.Base(filePath), "yaml")
+	}
 
 	return p.ParseGuidelines(content, filepath.Base(filePath), guidelineType)
 }
 
 // ParseGuidelines parses guidelines from markdown content
 func (p *Parser) ParseGuidelines(content, name, guidelineType string) (*types.GuidelineSet, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("no guideline content provided")
+	}
 	if guidelineType == "" {
 		guidelineType = "general"
 	}
@@ -55,6 +74,7 @@ func (p *Parser) ParseGuidelines(content, name, guidelineType string) (*types.Gu
 		Version:     "1.0.0",
 		Description: fmt.Sprintf("%s coding guidelines", guidelineType),
 		LoadedAt:    time.Now().Format(time.RFC3339),
+		Format:      "markdown",
 	}
 
 	guidelines := p.parseContent(content)
@@ -63,21 +83,16 @@ func (p *Parser) ParseGuidelines(content, name, guidelineType string) (*types.Gu
 	return guidelineSet, nil
 }
 
-// readFileContent reads the entire content of a file
+// readFileContent reads the entire content of a file. The file is read in one go
+// rather than line by line so that very long lines (such as minified examples)
+// aren't limited by a scanner buffer.
 func (p *Parser) readFileContent(file *os.File) (string, error) {
-	var content strings.Builder
-	scanner := bufio.NewScanner(file)
-	
-	for scanner.Scan() {
-		content.WriteString(scanner.Text())
-		content.WriteString("\n")
-	}
-	
-	if err := scanner.Err(); err != nil {
+	data, err := io.ReadAll(file)
+	if err != nil {
 		return "", err
 	}
 	
-	return content.String(), nil
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
 }
 
 // parseContent parses markdown content into guidelines
@@ -101,11 +116,21 @@ func (p *Parser) splitIntoSections(content string) []string {
 	lines := strings.Split(content, "\n")
 	var sections []string
 	var currentSection strings.Builder
+	sectionLevel := 0
 	
 	for _, line := range lines {
-		if p.headerRegex.MatchString(line) && currentSection.Len() > 0 {
-			sections = append(sections, currentSection.String())
-			currentSection.Reset()
+		if matches := p.headerRegex.FindStringSubmatch(line); len(matches) > 1 {
+			level := len(line) - len(strings.TrimLeft(line, "#"))
+
+			// Deeper headers such as "### Good Example" label examples within the current section
+			isExampleHeader := sectionLevel > 0 && level > sectionLevel && p.isExampleHeader(matches[1])
+			if !isExampleHeader {
+				if currentSection.Len() > 0 {
+					sections = append(sections, currentSection.String())
+					currentSection.Reset()
+				}
+				sectionLevel = level
+			}
 		}
 		currentSection.WriteString(line)
 		currentSection.WriteString("\n")
@@ -133,6 +158,9 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 	var currentContent strings.Builder
 	var inCodeBlock bool
 	var currentExample *types.GuidelineExample
+	// pendingLabel is the good/bad label waiting for the next code block; blockLabel is
+	// the label of the code block currently being read
+	var pendingLabel, blockLabel string
 	
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -143,6 +171,10 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 				guideline.Title = matches[1]
 				guideline.Category = p.inferCategory(matches[1])
 				guideline.Priority = p.inferPriority(matches[1])
+			} else {
+				// Nested headers label the examples that follow them
+				pendingLabel = p.classifyExampleLabel(matches[1])
+				currentExample = p.exampleFor(guideline, currentExample, pendingLabel, matches[1])
 			}
 			continue
 		}
@@ -152,13 +184,14 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 			if inCodeBlock {
 				// End of code block
 				if currentExample != nil {
-					if strings.Contains(strings.ToLower(currentExample.Title), "good") ||
-					   strings.Contains(strings.ToLower(currentExample.Title), "correct") ||
-					   strings.Contains(strings.ToLower(currentExample.Title), "do") {
+					label := blockLabel
+					if label == "" {
+						label = p.classifyExampleLabel(currentExample.Title)
+					}
+					switch label {
+					case "good":
 						currentExample.Good = strings.TrimSpace(currentContent.String())
-					} else if strings.Contains(strings.ToLower(currentExample.Title), "bad") ||
-							  strings.Contains(strings.ToLower(currentExample.Title), "incorrect") ||
-							  strings.Contains(strings.ToLower(currentExample.Title), "don't") {
+					case "bad":
 						currentExample.Bad = strings.TrimSpace(currentContent.String())
 					}
 				}
@@ -167,11 +200,16 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 			} else {
 				// Start of code block
 				inCodeBlock = true
+				blockLabel = pendingLabel
+				pendingLabel = ""
 				if currentExample == nil {
 					currentExample = &types.GuidelineExample{
 						Title: "Code Example",
 					}
 				}
+				if language := strings.TrimSpace(strings.TrimPrefix(line, "```")); language != "" && currentExample.Language == "" {
+					currentExample.Language = language
+				}
 			}
 			continue
 		}
@@ -182,6 +220,16 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 			continue
 		}
 		
+		// Parse bold or colon-terminated labels such as "**Good:**" or "Bad:"
+		if matches := p.labelRegex.FindStringSubmatch(line); len(matches) > 2 {
+			labelText := matches[1] + matches[2]
+			if label := p.classifyExampleLabel(labelText); label != "" || strings.Contains(strings.ToLower(labelText), "example") {
+				pendingLabel = label
+				currentExample = p.exampleFor(guideline, currentExample, label, strings.TrimSuffix(labelText, ":"))
+				continue
+			}
+		}
+		
 		// Parse list items as rules
 		if matches := p.listRegex.FindStringSubmatch(line); len(matches) > 1 {
 			rule := strings.TrimSpace(matches[1])
@@ -193,12 +241,8 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 		if strings.Contains(strings.ToLower(line), "example") ||
 		   strings.Contains(strings.ToLower(line), "good") ||
 		   strings.Contains(strings.ToLower(line), "bad") {
-			if currentExample != nil && (currentExample.Good != "" || currentExample.Bad != "") {
-				guideline.Examples = append(guideline.Examples, *currentExample)
-			}
-			currentExample = &types.GuidelineExample{
-				Title: line,
-			}
+			pendingLabel = p.classifyExampleLabel(line)
+			currentExample = p.exampleFor(guideline, currentExample, pendingLabel, line)
 			continue
 		}
 		
@@ -223,6 +267,52 @@ func (p *Parser) parseSection(section string, id int) *types.Guideline {
 	return guideline
 }
 
+// exampleFor returns the example that a newly labeled code block belongs to. A "good"
+// block followed by a "bad" one (or vice versa) share an example; otherwise the
+// current example is stored and a new one titled title is started.
+func (p *Parser) exampleFor(guideline *types.Guideline, current *types.GuidelineExample, label, title string) *types.GuidelineExample {
+	if current != nil && current.Good == "" && current.Bad == "" {
+		if current.Title == "Code Example" || label == "" {
+			current.Title = title
+		}
+		return current
+	}
+	if current != nil && ((label == "good" && current.Good == "") || (label == "bad" && current.Bad == "")) {
+		return current
+	}
+
+	if current != nil {
+		guideline.Examples = append(guideline.Examples, *current)
+	}
+	return &types.GuidelineExample{
+		Title: title,
+	}
+}
+
+// classifyExampleLabel returns "good" or "bad" for example labels such as
+// "Good Example", "Don't" or "Incorrect", and an empty string otherwise
+func (p *Parser) classifyExampleLabel(text string) string {
+	textLower := strings.ToLower(text)
+
+	// Check bad first so that "incorrect" and "don't" aren't read as "correct" and "do"
+	if p.badRegex.MatchString(textLower) {
+		return "bad"
+	}
+	if p.goodRegex.MatchString(textLower) {
+		return "good"
+	}
+	return ""
+}
+
+// isExampleHeader reports whether a header only labels an example, e.g. "Good Example" or "Don't"
+func (p *Parser) isExampleHeader(title string) bool {
+	titleLower := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(title), ":"))
+	if strings.Contains(titleLower, "example") {
+		return true
+	}
+	return len(strings.Fields(titleLower)) <= 2 && p.classifyExampleLabel(titleLower) != ""
+}
+
 // inferCategory infers the category from the title
 func (p *Parser) inferCategory(title string) string {
 	titleLower := strings.ToLower(title)
@@ -279,6 +369,14 @@ func (p *Parser) ValidateGuidelines(guidelineSet *types.GuidelineSet) []string {
 	if len(guidelineSet.Guidelines) == 0 {
 		warnings = append(warnings, "No guidelines found in the set")
 	}
+
+	if guidelineSet.Format == "markdown" && !p.hasTitledGuideline(guidelineSet) {
+		warnings = append(warnings, "file contains no markdown headers; are you sure this is a guidelines file?")
+	}
+
+	if guidelineSet.Format == "json" || guidelineSet.Format == "yaml" {
+		warnings = append(warnings, p.validateStructured(guidelineSet)...)
+	}
 	
 	for i, guideline := range guidelineSet.Guidelines {
 		if guideline.Title == "" {
@@ -287,10 +385,20 @@ func (p *Parser) ValidateGuidelines(guidelineSet *types.GuidelineSet) []string {
 		if guideline.Description == "" {
 			warnings = append(warnings, fmt.Sprintf("Guideline %d (%s) has no description", i+1, guideline.Title))
 		}
-		if len(guideline.Rules) == 0 && len(guideline.Examples) == 0 {
+		if len(guideline.Rules) == 0 && len(guideline.Patterns) == 0 && len(guideline.Examples) == 0 {
 			warnings = append(warnings, fmt.Sprintf("Guideline %d (%s) has no rules or examples", i+1, guideline.Title))
 		}
 	}
 	
 	return warnings
-}
\ No newline at end of file
+}
+
+// hasTitledGuideline reports whether any guideline was parsed from a markdown header
+func (p *Parser) hasTitledGuideline(guidelineSet *types.GuidelineSet) bool {
+	for _, guideline := range guidelineSet.Guidelines {
+		if guideline.Title != "" {
+			return true
+		}
+	}
+	return false
+}
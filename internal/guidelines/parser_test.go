@@ -0,0 +1,195 @@
+package guidelines
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+const labeledGuidelines = "# TypeScript Style Guide\n" +
+	"\n" +
+	"Conventions for the frontend codebase.\n" +
+	"\n" +
+	"## Avoid `any` in public APIs\n" +
+	"\n" +
+	"Exported functions must describe their inputs precisely.\n" +
+	"\n" +
+	"**Bad:**\n" +
+	"\n" +
+	"```ts\n" +
+	"export function parse(input: any): any {\n" +
+	"  return JSON.parse(input);\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"**Good:**\n" +
+	"\n" +
+	"```ts\n" +
+	"export function parse(input: string): unknown {\n" +
+	"  return JSON.parse(input);\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"## Prefer async/await over promise chains\n" +
+	"\n" +
+	"Chained callbacks bury the error handling.\n" +
+	"\n" +
+	"#### Good\n" +
+	"\n" +
+	"```typescript\n" +
+	"const user = await fetchUser(id);\n" +
+	"```\n" +
+	"\n" +
+	"#### Bad\n" +
+	"\n" +
+	"```typescript\n" +
+	"fetchUser(id).then((user) => render(user));\n" +
+	"```\n" +
+	"\n" +
+	"## Error handling\n" +
+	"\n" +
+	"Never swallow errors; a good catch block always rethrows or reports.\n" +
+	"\n" +
+	"Don't:\n" +
+	"\n" +
+	"```ts\n" +
+	"try { save(); } catch {}\n" +
+	"```\n" +
+	"\n" +
+	"Do:\n" +
+	"\n" +
+	"```ts\n" +
+	"try { save(); } catch (err) { logger.error(err); throw err; }\n" +
+	"```\n"
+
+// parseGuideline parses content and returns the guideline titled title
+func parseGuideline(t *testing.T, content, title string) types.Guideline {
+	t.Helper()
+	set, err := NewParser().ParseGuidelines(content, "style.md", "typescript")
+	if err != nil {
+		t.Fatalf("ParseGuidelines: %v", err)
+	}
+	for _, guideline := range set.Guidelines {
+		if guideline.Title == title {
+			return guideline
+		}
+	}
+	t.Fatalf("no guideline titled %q in %+v", title, set.Guidelines)
+	return types.Guideline{}
+}
+
+// assertExample checks that guideline has a single example pairing the good and bad code
+func assertExample(t *testing.T, guideline types.Guideline, good, bad string) {
+	t.Helper()
+	if len(guideline.Examples) != 1 {
+		t.Fatalf("%s: Examples = %+v, want 1", guideline.Title, guideline.Examples)
+	}
+	example := guideline.Examples[0]
+	if !strings.Contains(example.Good, good) || strings.Contains(example.Good, bad) {
+		t.Errorf("%s: Good = %q, want the code containing %q", guideline.Title, example.Good, good)
+	}
+	if !strings.Contains(example.Bad, bad) || strings.Contains(example.Bad, good) {
+		t.Errorf("%s: Bad = %q, want the code containing %q", guideline.Title, example.Bad, bad)
+	}
+}
+
+func TestParseGuidelinesBoldLabels(t *testing.T) {
+	guideline := parseGuideline(t, labeledGuidelines, "Avoid `any` in public APIs")
+	assertExample(t, guideline, "input: string", "input: any")
+	if guideline.Examples[0].Language != "ts" {
+		t.Errorf("Language = %q, want ts", guideline.Examples[0].Language)
+	}
+	if guideline.Description != "Exported functions must describe their inputs precisely." {
+		t.Errorf("Description = %q", guideline.Description)
+	}
+}
+
+func TestParseGuidelinesHeaderLabels(t *testing.T) {
+	guideline := parseGuideline(t, labeledGuidelines, "Prefer async/await over promise chains")
+	assertExample(t, guideline, "await fetchUser", ".then(")
+}
+
+func TestParseGuidelinesColonLabels(t *testing.T) {
+	// The description mentions both "good" and "never"; only the labels decide
+	guideline := parseGuideline(t, labeledGuidelines, "Error handling")
+	assertExample(t, guideline, "logger.error", "catch {}")
+}
+
+func TestParseGuidelinesLabelsBeatCodeContent(t *testing.T) {
+	content := "## Naming\n" +
+		"\n" +
+		"Use descriptive names.\n" +
+		"\n" +
+		"**Incorrect:**\n" +
+		"\n" +
+		"```ts\n" +
+		"const goodValue = 1; // named good, still the bad example\n" +
+		"```\n" +
+		"\n" +
+		"**Correct:**\n" +
+		"\n" +
+		"```ts\n" +
+		"const retryLimit = 1; // avoid nothing here\n" +
+		"```\n"
+
+	guideline := parseGuideline(t, content, "Naming")
+	assertExample(t, guideline, "retryLimit", "goodValue")
+}
+
+func TestParseGuidelinesSeparateExamples(t *testing.T) {
+	content := "## Imports\n" +
+		"\n" +
+		"Keep imports explicit.\n" +
+		"\n" +
+		"**Good:**\n" +
+		"\n" +
+		"```ts\n" +
+		"import { a } from \"./a\";\n" +
+		"```\n" +
+		"\n" +
+		"**Good:**\n" +
+		"\n" +
+		"```ts\n" +
+		"import type { B } from \"./b\";\n" +
+		"```\n"
+
+	guideline := parseGuideline(t, content, "Imports")
+	if len(guideline.Examples) != 2 {
+		t.Fatalf("Examples = %+v, want 2 separate good examples", guideline.Examples)
+	}
+	for i, want := range []string{"import { a }", "import type { B }"} {
+		if example := guideline.Examples[i]; !strings.Contains(example.Good, want) || example.Bad != "" {
+			t.Errorf("Examples[%d] = %+v, want only good code containing %q", i, example, want)
+		}
+	}
+}
+
+func TestParseGuidelinesFromFileLongLine(t *testing.T) {
+	// A minified example on one line, well past bufio.Scanner's 64KB token limit
+	minified := "const table=[" + strings.Repeat("0x1f,", 20000) + "];"
+	if len(minified) <= 64*1024 {
+		t.Fatalf("fixture line is only %d bytes", len(minified))
+	}
+	content := "# Bundling\r\n\r\nShip readable sources.\r\n\r\n**Bad:**\r\n\r\n```js\r\n" + minified + "\r\n```\r\n"
+	path := filepath.Join(t.TempDir(), "bundling.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := NewParser().ParseGuidelinesFromFile(path, "")
+	if err != nil {
+		t.Fatalf("ParseGuidelinesFromFile: %v", err)
+	}
+	if len(set.Guidelines) != 1 || len(set.Guidelines[0].Examples) != 1 {
+		t.Fatalf("Guidelines = %+v, want one guideline with one example", set.Guidelines)
+	}
+	if bad := set.Guidelines[0].Examples[0].Bad; bad != minified {
+		t.Errorf("Bad has %d bytes, want the %d byte line intact", len(bad), len(minified))
+	}
+	if set.Name != "bundling.md" || set.Format != "markdown" {
+		t.Errorf("Name = %q, Format = %q, want bundling.md and markdown", set.Name, set.Format)
+	}
+}
@@ -0,0 +1,81 @@
+package guidelines
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// parseStructured parses a guideline set authored directly as JSON or YAML
+func (p *Parser) parseStructured(content, name, format string) (*types.GuidelineSet, error) {
+	guidelineSet := &types.GuidelineSet{}
+
+	var err error
+	if format == "json" {
+		err = json.Unmarshal([]byte(content), guidelineSet)
+	} else {
+		err = yaml.Unmarshal([]byte(content), guidelineSet)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s guidelines: %w", format, err)
+	}
+
+	if guidelineSet.Name == "" {
+		guidelineSet.Name = name
+	}
+	if guidelineSet.Version == "" {
+		guidelineSet.Version = "1.0.0"
+	}
+	guidelineSet.LoadedAt = time.Now().Format(time.RFC3339)
+	guidelineSet.Format = format
+
+	// Fill in optional fields; required ones are reported by ValidateGuidelines
+	for i := range guidelineSet.Guidelines {
+		guideline := &guidelineSet.Guidelines[i]
+		if guideline.Priority == "" {
+			guideline.Priority = "medium"
+		}
+		if guideline.Category == "" {
+			guideline.Category = "general"
+		}
+		if guideline.Description == "" && guideline.Title != "" {
+			guideline.Description = guideline.Title
+		}
+	}
+
+	return guidelineSet, nil
+}
+
+// validateStructured checks the fields a hand-authored guideline must set explicitly
+func (p *Parser) validateStructured(guidelineSet *types.GuidelineSet) []string {
+	var warnings []string
+	seenIDs := make(map[string]bool)
+
+	for i, guideline := range guidelineSet.Guidelines {
+		if guideline.ID == "" {
+			warnings = append(warnings, fmt.Sprintf("Guideline %d (%s) has no id", i+1, guideline.Title))
+		} else if seenIDs[guideline.ID] {
+			warnings = append(warnings, fmt.Sprintf("Guideline %d (%s) reuses id %q", i+1, guideline.Title, guideline.ID))
+		}
+		seenIDs[guideline.ID] = true
+
+		switch guideline.Priority {
+		case "low", "medium", "high":
+		default:
+			warnings = append(warnings, fmt.Sprintf("Guideline %d (%s) has invalid priority %q; expected low, medium or high", i+1, guideline.Title, guideline.Priority))
+		}
+
+		for _, pattern := range guideline.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				warnings = append(warnings, fmt.Sprintf("Guideline %d (%s) has invalid pattern %q: %v", i+1, guideline.Title, pattern, err))
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,71 @@
+package paths
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchGlob reports whether filePath matches a glob pattern. Patterns without a
+// slash match the base name, and a "**" segment matches any number of directories.
+func MatchGlob(pattern, filePath string) bool {
+	pattern = filepath.ToSlash(pattern)
+	filePath = filepath.ToSlash(filePath)
+
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(filePath))
+		return matched
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+// MatchAny reports whether filePath matches any of the glob patterns
+func MatchAny(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAnySuffix reports whether any trailing portion of filePath matches one of
+// the patterns, so "generated/**" matches both "generated/a.ts" and "/repo/src/generated/a.ts"
+func MatchAnySuffix(patterns []string, filePath string) bool {
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(filePath)), "/")
+	for i := range segments {
+		if MatchAny(patterns, strings.Join(segments[i:], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments, expanding "**"
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range segments {
+				if matchSegments(pattern, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+
+	return len(segments) == 0
+}
@@ -0,0 +1,57 @@
+package paths
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// languageExtensions maps language hints to source file extensions
+var languageExtensions = map[string]string{
+	"ts":  ".ts",
+	"tsx": ".tsx",
+	"mts": ".mts",
+	"cts": ".cts",
+	"js":  ".js",
+	"jsx": ".jsx",
+	"mjs": ".mjs",
+	"cjs": ".cjs",
+}
+
+// ExtensionFor returns the file extension for a language hint, defaulting to ".ts"
+func ExtensionFor(lang string) string {
+	if ext, ok := languageExtensions[strings.ToLower(strings.TrimPrefix(lang, "."))]; ok {
+		return ext
+	}
+	return ".ts"
+}
+
+// IsJavaScript reports whether a language hint names plain JavaScript rather than
+// TypeScript
+func IsJavaScript(lang string) bool {
+	switch strings.ToLower(strings.TrimPrefix(lang, ".")) {
+	case "js", "jsx", "mjs", "cjs":
+		return true
+	}
+	return false
+}
+
+// LanguageFor returns the language hint for a file path based on its extension
+func LanguageFor(filePath string) string {
+	return strings.TrimPrefix(filepath.Ext(filePath), ".")
+}
+
+// IsTypeScriptFile reports whether filePath is a TypeScript source file
+// (.ts, .tsx, .mts or .cts). Declaration files are excluded.
+func IsTypeScriptFile(filePath string) bool {
+	for _, suffix := range []string{".d.ts", ".d.mts", ".d.cts"} {
+		if strings.HasSuffix(filePath, suffix) {
+			return false
+		}
+	}
+
+	switch filepath.Ext(filePath) {
+	case ".ts", ".tsx", ".mts", ".cts":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// htmlPage is the data rendered by pageTemplate. Only the sections that are set appear.
+type htmlPage struct {
+	Title        string
+	Score        *int
+	Summary      string
+	TypeCheck    *types.TypeCheckResult
+	Diff         *types.TypeCheckDiffResult
+	Lint         *types.LintResult
+	Improvements *types.ImprovementResult
+	StepErrors   map[string]string
+	// Totals and Files are set for a multi-file review; each file is a nested page
+	Totals *types.ReviewTotals
+	Files  []htmlPage
+}
+
+// ReviewHTML renders a review as a self-contained HTML page. A multi-file review gets a
+// collapsible section per file.
+func ReviewHTML(result *types.ReviewResult) (string, error) {
+	page := reviewPage(result, "Review of "+result.FilePath)
+	if result.Totals != nil {
+		page.Title = fmt.Sprintf("Review of %d files", result.Totals.Files)
+		page.Totals = result.Totals
+		for i := range result.Files {
+			page.Files = append(page.Files, reviewPage(&result.Files[i], result.Files[i].FilePath))
+		}
+	}
+	return renderPage(page)
+}
+
+// reviewPage builds the page for the steps of a review
+func reviewPage(result *types.ReviewResult, title string) htmlPage {
+	return htmlPage{
+		Title:        title,
+		Score:        &result.Score,
+		Summary:      result.Summary,
+		TypeCheck:    result.TypeCheck,
+		Lint:         result.Lint,
+		Improvements: result.Improvements,
+		StepErrors:   result.StepErrors,
+	}
+}
+
+// TypeCheckHTML renders a type-check result as a self-contained HTML page
+func TypeCheckHTML(result *types.TypeCheckResult) (string, error) {
+	return renderPage(htmlPage{Title: "Type Check", TypeCheck: result})
+}
+
+// TypeCheckDiffHTML renders the errors introduced since a baseline as a self-contained HTML page
+func TypeCheckDiffHTML(result *types.TypeCheckDiffResult) (string, error) {
+	return renderPage(htmlPage{Title: "New Type Errors", Summary: result.Summary, Diff: result})
+}
+
+// LintHTML renders a lint result as a self-contained HTML page
+func LintHTML(result *types.LintResult) (string, error) {
+	return renderPage(htmlPage{Title: "Lint", Lint: result})
+}
+
+// ImprovementsHTML renders improvement suggestions as a self-contained HTML page
+func ImprovementsHTML(result *types.ImprovementResult) (string, error) {
+	return renderPage(htmlPage{Title: "Suggested Improvements", Improvements: result})
+}
+
+// renderPage executes pageTemplate for page
+func renderPage(page htmlPage) (string, error) {
+	var b bytes.Buffer
+	if err := pageTemplate.Execute(&b, page); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return b.String(), nil
+}
+
+// improvementGroup is the improvements of one priority
+type improvementGroup struct {
+	Priority     string
+	Improvements []types.Improvement
+}
+
+// groupByPriority groups improvements by priority, highest first, dropping empty groups
+func groupByPriority(improvements []types.Improvement) []improvementGroup {
+	var groups []improvementGroup
+	for _, priority := range []string{"high", "medium", "low"} {
+		group := improvementGroup{Priority: priority}
+		for _, improvement := range improvements {
+			if improvement.Priority == priority {
+				group.Improvements = append(group.Improvements, improvement)
+			}
+		}
+		if len(group.Improvements) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// tokenRegex splits TypeScript into comments, strings, numbers and words for highlighting
+var tokenRegex = regexp.MustCompile("(?s)//[^\n]*|/\\*.*?\\*/|\"(?:[^\"\\\\\n]|\\\\.)*\"|'(?:[^'\\\\\n]|\\\\.)*'|`(?:[^`\\\\]|\\\\.)*`|\\b\\d[\\d_]*(?:\\.\\d+)?\\b|[A-Za-z_$][\\w$]*")
+
+// tsKeywords are highlighted as keywords
+var tsKeywords = map[string]bool{
+	"abstract": true, "as": true, "async": true, "await": true, "break": true, "case": true,
+	"catch": true, "class": true, "const": true, "continue": true, "declare": true,
+	"default": true, "delete": true, "do": true, "else": true, "enum": true, "export": true,
+	"extends": true, "false": true, "finally": true, "for": true, "from": true,
+	"function": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "interface": true, "keyof": true, "let": true, "new": true,
+	"null": true, "private": true, "protected": true, "public": true, "readonly": true,
+	"return": true, "static": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "type": true, "typeof": true, "undefined": true, "var": true, "void": true,
+	"while": true, "yield": true,
+}
+
+// tsTypes are built-in type names highlighted as types
+var tsTypes = map[string]bool{
+	"any": true, "boolean": true, "never": true, "number": true, "object": true,
+	"string": true, "symbol": true, "unknown": true, "bigint": true,
+}
+
+// highlight escapes TypeScript code and wraps its tokens in classed spans
+func highlight(code string) template.HTML {
+	var b strings.Builder
+	last := 0
+	for _, match := range tokenRegex.FindAllStringIndex(code, -1) {
+		b.WriteString(template.HTMLEscapeString(code[last:match[0]]))
+		token := code[match[0]:match[1]]
+		class := ""
+		switch {
+		case strings.HasPrefix(token, "//") || strings.HasPrefix(token, "/*"):
+			class = "comment"
+		case strings.ContainsAny(token[:1], "\"'`"):
+			class = "string"
+		case token[0] >= '0' && token[0] <= '9':
+			class = "number"
+		case tsKeywords[token]:
+			class = "keyword"
+		case tsTypes[token]:
+			class = "type"
+		}
+		if class == "" {
+			b.WriteString(template.HTMLEscapeString(token))
+		} else {
+			fmt.Fprintf(&b, `<span class="%s">%s</span>`, class, template.HTMLEscapeString(token))
+		}
+		last = match[1]
+	}
+	b.WriteString(template.HTMLEscapeString(code[last:]))
+	return template.HTML(b.String())
+}
+
+// pageTemplate is a self-contained HTML report with collapsible sections
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"highlight":       highlight,
+	"groupByPriority": groupByPriority,
+	"title":           func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+	"position":        position,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2rem auto; max-width: 1100px; padding: 0 1rem; color: #1f2328; }
+h1 { margin-bottom: .25rem; }
+.summary { color: #57606a; }
+.score { font-size: 1.5rem; font-weight: 600; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: 1rem 0; padding: .5rem 1rem; }
+summary { cursor: pointer; font-weight: 600; font-size: 1.1rem; }
+table { border-collapse: collapse; width: 100%; margin: .75rem 0; font-size: .9rem; }
+th, td { border: 1px solid #d0d7de; padding: .35rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+pre { background: #f6f8fa; border-radius: 6px; padding: .75rem; overflow-x: auto; }
+.ok { color: #1a7f37; } .fail { color: #cf222e; }
+.error { color: #cf222e; font-weight: 600; } .warning { color: #9a6700; font-weight: 600; }
+.improvement { border-top: 1px solid #d0d7de; padding: .5rem 0; }
+.tag { background: #ddf4ff; border-radius: 1em; padding: 0 .5em; font-size: .8rem; }
+.keyword { color: #cf222e; } .type { color: #8250df; } .string { color: #0a3069; }
+.number { color: #0550ae; } .comment { color: #6e7781; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Score}}<p class="score">Score: {{.}}/100</p>{{end}}
+{{with .Summary}}<p class="summary">{{.}}</p>{{end}}
+{{with .Totals}}
+<table>
+<tr><th>Files</th><th>Errors</th><th>Warnings</th><th>Improvements</th></tr>
+<tr><td>{{.Files}}</td><td>{{.Errors}}</td><td>{{.Warnings}}</td><td>{{.Improvements}}</td></tr>
+</table>
+{{end}}
+{{template "sections" .}}
+{{range .Files}}
+<details>
+<summary><code>{{.Title}}</code>: score {{.Score}}/100</summary>
+<p class="summary">{{.Summary}}</p>
+{{template "sections" .}}
+</details>
+{{end}}
+</body>
+</html>
+{{define "sections"}}
+{{with .TypeCheck}}
+<details open>
+<summary>Type Check: <span class="{{if .Success}}ok{{else}}fail{{end}}">{{len .Errors}} error(s), {{len .Warnings}} warning(s)</span></summary>
+{{if .RawOutput}}<p>tsc failed without reporting diagnostics:</p><pre>{{.RawOutput}}</pre>{{end}}
+{{template "diagnostics" .Errors}}{{template "diagnostics" .Warnings}}
+{{if .Truncated}}<p><em>Showing the first diagnostics of {{.Total}}.</em></p>{{end}}
+</details>
+{{end}}
+
+{{with .Diff}}
+<details open>
+<summary>New Errors: <span class="{{if .Success}}ok{{else}}fail{{end}}">{{len .NewErrors}}</span></summary>
+{{with .BaselineRef}}<p>Baseline: <code>{{.}}</code></p>{{end}}
+{{template "diagnostics" .NewErrors}}
+</details>
+{{end}}
+
+{{with .Lint}}
+<details open>
+<summary>Lint: <span class="{{if .Success}}ok{{else}}fail{{end}}">{{.Summary}}</span></summary>
+{{if .Issues}}
+<table>
+<tr><th>Severity</th><th>Location</th><th>Rule</th><th>Message</th><th>Fixable</th></tr>
+{{range .Issues}}<tr><td class="{{.Severity}}">{{.Severity}}</td><td><code>{{position .File .Line .Column}}</code></td><td>{{if .RuleURL}}<a href="{{.RuleURL}}">{{.Rule}}</a>{{else}}{{.Rule}}{{end}}</td><td>{{.Message}}</td><td>{{if .Fixable}}yes{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Truncated}}<p><em>Showing {{len .Issues}} of {{.TotalIssues}} issues.</em></p>{{end}}
+</details>
+{{end}}
+
+{{with .Improvements}}
+<details open>
+<summary>Suggested Improvements: score {{.Score}}/100</summary>
+<p>{{.Summary}}</p>
+{{with .Note}}<p><em>{{.}}</em></p>{{end}}
+{{if .Files}}{{range .Files}}
+<details open>
+<summary><code>{{.FilePath}}</code></summary>
+{{template "improvements" .Improvements}}
+</details>
+{{end}}{{else}}{{template "improvements" .Improvements}}{{end}}
+</details>
+{{end}}
+
+{{if .StepErrors}}
+<details open>
+<summary>Skipped Steps</summary>
+<ul>{{range $step, $reason := .StepErrors}}<li><strong>{{$step}}</strong>: {{$reason}}</li>{{end}}</ul>
+</details>
+{{end}}
+{{end}}
+{{define "diagnostics"}}{{if .}}
+<table>
+<tr><th>Severity</th><th>Location</th><th>Code</th><th>Message</th></tr>
+{{range .}}<tr><td class="{{.Severity}}">{{.Severity}}</td><td><code>{{position .File .Line .Column}}</code></td><td>{{.Code}}</td><td>{{.Message}}{{range .Details}}<br>{{.}}{{end}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+{{define "improvements"}}{{range groupByPriority .}}
+<h3>{{title .Priority}} priority ({{len .Improvements}})</h3>
+{{range .Improvements}}<div class="improvement">
+<p><strong>{{.Description}}</strong>{{if .Line}} (line {{.Line}}){{end}} <span class="tag">{{.Type}}</span></p>
+{{with .Reasoning}}<p>{{.}}</p>{{end}}
+{{with .Before}}<p>Before:</p><pre><code>{{highlight .}}</code></pre>{{end}}
+{{with .After}}<p>After:</p><pre><code>{{highlight .}}</code></pre>{{end}}
+</div>
+{{end}}{{end}}{{end}}
+`))
@@ -0,0 +1,327 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// TypeCheckMarkdown renders a type-check result as a table of diagnostics
+func TypeCheckMarkdown(result *types.TypeCheckResult) string {
+	return typeCheckSection(result, "##")
+}
+
+// TypeCheckDiffMarkdown renders the errors introduced since a baseline
+func TypeCheckDiffMarkdown(result *types.TypeCheckDiffResult) string {
+	var b strings.Builder
+	b.WriteString("## New Type Errors\n\n")
+	fmt.Fprintf(&b, "%s %s\n\n", statusIcon(result.Success), result.Summary)
+	if result.BaselineRef != "" {
+		fmt.Fprintf(&b, "Baseline: `%s`\n\n", result.BaselineRef)
+	}
+	writeDiagnostics(&b, result.NewErrors)
+	if result.Truncated {
+		b.WriteString("\n_Only the first new errors are shown._\n")
+	}
+	return finish(&b)
+}
+
+// LintMarkdown renders a lint result as a table of issues
+func LintMarkdown(result *types.LintResult) string {
+	return lintSection(result, "##")
+}
+
+// ImprovementsMarkdown renders improvements grouped by file and priority, with
+// before/after code for each
+func ImprovementsMarkdown(result *types.ImprovementResult) string {
+	return improvementsSection(result, "##")
+}
+
+// ReviewMarkdown renders a review with a section per step. A multi-file review gets an
+// overview table followed by a section per file.
+func ReviewMarkdown(result *types.ReviewResult) string {
+	if result.Totals != nil {
+		return reviewFilesMarkdown(result)
+	}
+	sections := []string{
+		fmt.Sprintf("# Review of `%s`\n\n**Score: %d/100** - %s", result.FilePath, result.Score, result.Summary),
+	}
+	sections = append(sections, reviewSections(result, "##")...)
+	return joinSections(sections)
+}
+
+// reviewFilesMarkdown renders a multi-file review
+func reviewFilesMarkdown(result *types.ReviewResult) string {
+	var b strings.Builder
+	totals := result.Totals
+	fmt.Fprintf(&b, "# Review of %d files\n\n**Average score: %d/100** - %s\n\n", totals.Files, result.Score, result.Summary)
+	b.WriteString("| Errors | Warnings | Improvements |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d |\n\n", totals.Errors, totals.Warnings, totals.Improvements)
+
+	if len(result.Files) > 0 {
+		b.WriteString("| File | Score | Summary |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, file := range result.Files {
+			fmt.Fprintf(&b, "| `%s` | %d | %s |\n", cell(file.FilePath), file.Score, cell(file.Summary))
+		}
+	}
+
+	sections := []string{b.String()}
+	if len(result.StepErrors) > 0 {
+		sections = append(sections, stepErrorsSection(result.StepErrors, "## Files Not Reviewed"))
+	}
+	for _, file := range result.Files {
+		sections = append(sections, fmt.Sprintf("## `%s`\n\n**Score: %d/100** - %s", file.FilePath, file.Score, file.Summary))
+		sections = append(sections, reviewSections(&file, "###")...)
+	}
+	return joinSections(sections)
+}
+
+// reviewSections renders the steps of a single-file review under headings of the given level
+func reviewSections(result *types.ReviewResult, heading string) []string {
+	var sections []string
+	if result.TypeCheck != nil {
+		sections = append(sections, typeCheckSection(result.TypeCheck, heading))
+	}
+	if result.Lint != nil {
+		sections = append(sections, lintSection(result.Lint, heading))
+	}
+	if result.Improvements != nil {
+		sections = append(sections, improvementsSection(result.Improvements, heading))
+	}
+	if len(result.StepErrors) > 0 {
+		sections = append(sections, stepErrorsSection(result.StepErrors, heading+" Skipped Steps"))
+	}
+	return sections
+}
+
+// typeCheckSection renders a type-check result under a heading of the given level
+func typeCheckSection(result *types.TypeCheckResult, heading string) string {
+	var b strings.Builder
+	b.WriteString(heading + " Type Check\n\n")
+	writeTypeCheck(&b, result)
+	return finish(&b)
+}
+
+// lintSection renders a lint result under a heading of the given level
+func lintSection(result *types.LintResult, heading string) string {
+	var b strings.Builder
+	b.WriteString(heading + " Lint\n\n")
+	writeLint(&b, result)
+	return finish(&b)
+}
+
+// improvementsSection renders improvements under a heading of the given level
+func improvementsSection(result *types.ImprovementResult, heading string) string {
+	var b strings.Builder
+	b.WriteString(heading + " Suggested Improvements\n\n")
+	writeImprovements(&b, result, heading+"#")
+	return finish(&b)
+}
+
+// stepErrorsSection lists the reasons in stepErrors, sorted by key, under title
+func stepErrorsSection(stepErrors map[string]string, title string) string {
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	keys := make([]string, 0, len(stepErrors))
+	for key := range stepErrors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "- **%s**: %s\n", key, stepErrors[key])
+	}
+	return b.String()
+}
+
+// joinSections joins report sections with a blank line between each
+func joinSections(sections []string) string {
+	for i := range sections {
+		sections[i] = strings.TrimRight(sections[i], "\n")
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+// finish returns the report in b ending with a single newline
+func finish(b *strings.Builder) string {
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeTypeCheck writes the status line and diagnostics of a type-check result
+func writeTypeCheck(b *strings.Builder, result *types.TypeCheckResult) {
+	fmt.Fprintf(b, "%s %d error(s), %d warning(s)", statusIcon(result.Success), len(result.Errors), len(result.Warnings))
+	if result.CompileTime != "" {
+		fmt.Fprintf(b, " in %s", result.CompileTime)
+	}
+	b.WriteString("\n\n")
+
+	if result.RawOutput != "" {
+		b.WriteString("tsc failed without reporting diagnostics:\n\n")
+		writeFence(b, "", result.RawOutput)
+		return
+	}
+
+	diagnostics := append(append([]types.TypeScriptError{}, result.Errors...), result.Warnings...)
+	writeDiagnostics(b, diagnostics)
+	if result.Truncated {
+		fmt.Fprintf(b, "\n_Showing %d of %d diagnostics._\n", len(diagnostics), result.Total)
+	}
+}
+
+// writeDiagnostics writes tsc diagnostics as a table
+func writeDiagnostics(b *strings.Builder, diagnostics []types.TypeScriptError) {
+	if len(diagnostics) == 0 {
+		b.WriteString("No diagnostics.\n")
+		return
+	}
+
+	b.WriteString("| Severity | Location | Code | Message |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, diagnostic := range diagnostics {
+		message := diagnostic.Message
+		if len(diagnostic.Details) > 0 {
+			message += " " + strings.Join(diagnostic.Details, " ")
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n",
+			diagnostic.Severity,
+			location(diagnostic.File, diagnostic.Line, diagnostic.Column),
+			cell(diagnostic.Code),
+			cell(message))
+	}
+}
+
+// writeLint writes the summary and issues of a lint result
+func writeLint(b *strings.Builder, result *types.LintResult) {
+	fmt.Fprintf(b, "%s %s\n\n", statusIcon(result.Success), result.Summary)
+	if len(result.Issues) == 0 {
+		return
+	}
+
+	b.WriteString("| Severity | Location | Rule | Message | Fixable |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, issue := range result.Issues {
+		rule := cell(issue.Rule)
+		if issue.RuleURL != "" {
+			rule = fmt.Sprintf("[%s](%s)", rule, issue.RuleURL)
+		}
+		fixable := ""
+		if issue.Fixable {
+			fixable = "yes"
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+			issue.Severity,
+			location(issue.File, issue.Line, issue.Column),
+			rule,
+			cell(issue.Message),
+			fixable)
+	}
+	if result.Truncated {
+		fmt.Fprintf(b, "\n_Showing %d of %d issues._\n", len(result.Issues), result.TotalIssues)
+	}
+}
+
+// writeImprovements writes improvements under a heading per file (for project results)
+// and per priority
+func writeImprovements(b *strings.Builder, result *types.ImprovementResult, heading string) {
+	fmt.Fprintf(b, "**Score: %d/100** - %s\n\n", result.Score, result.Summary)
+	if result.Note != "" {
+		fmt.Fprintf(b, "_%s_\n\n", result.Note)
+	}
+
+	if len(result.Files) > 0 {
+		for _, file := range result.Files {
+			fmt.Fprintf(b, "%s `%s`\n\n", heading, file.FilePath)
+			writeImprovementGroups(b, file.Improvements, heading+"#")
+		}
+		return
+	}
+	writeImprovementGroups(b, result.Improvements, heading)
+}
+
+// writeImprovementGroups writes improvements grouped by priority, highest first
+func writeImprovementGroups(b *strings.Builder, improvements []types.Improvement, heading string) {
+	for _, priority := range []string{"high", "medium", "low"} {
+		var group []types.Improvement
+		for _, improvement := range improvements {
+			if improvement.Priority == priority {
+				group = append(group, improvement)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(b, "%s %s priority (%d)\n\n", heading, strings.ToUpper(priority[:1])+priority[1:], len(group))
+		for _, improvement := range group {
+			fmt.Fprintf(b, "- **%s**", improvement.Description)
+			if improvement.Line > 0 {
+				fmt.Fprintf(b, " (line %d)", improvement.Line)
+			}
+			fmt.Fprintf(b, " `%s`\n", improvement.Type)
+			if improvement.Reasoning != "" {
+				fmt.Fprintf(b, "\n  %s\n", improvement.Reasoning)
+			}
+			if improvement.Before != "" {
+				b.WriteString("\n  Before:\n\n")
+				writeIndentedFence(b, "ts", improvement.Before)
+			}
+			if improvement.After != "" {
+				b.WriteString("\n  After:\n\n")
+				writeIndentedFence(b, "ts", improvement.After)
+			}
+			b.WriteString("\n")
+		}
+	}
+}
+
+// writeIndentedFence writes a code fence indented to sit inside a list item
+func writeIndentedFence(b *strings.Builder, lang, code string) {
+	var fence strings.Builder
+	writeFence(&fence, lang, code)
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(fence.String(), "\n"), "\n") {
+		b.WriteString("  " + line)
+	}
+	b.WriteString("\n")
+}
+
+// writeFence writes code in a fence long enough not to be closed by backticks inside it
+func writeFence(b *strings.Builder, lang, code string) {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(code, "\n"), fence)
+}
+
+// location formats a file position for a table cell
+func location(file string, line, column int) string {
+	if file == "" {
+		return "-"
+	}
+	return "`" + cell(position(file, line, column)) + "`"
+}
+
+// position formats a file position as file:line:column
+func position(file string, line, column int) string {
+	if line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d:%d", file, line, column)
+}
+
+// cell escapes text for use inside a markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// statusIcon marks a passing or failing result
+func statusIcon(success bool) string {
+	if success {
+		return "✅"
+	}
+	return "❌"
+}
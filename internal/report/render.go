@@ -0,0 +1,60 @@
+// Package report renders tool results as human-readable markdown and HTML reports
+package report
+
+import (
+	"fmt"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// Format names accepted by the format parameter of result-producing tools
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// ValidateFormat checks that format is empty or one of the supported formats
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatJSON, FormatMarkdown, FormatHTML:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q: use %q, %q or %q", format, FormatJSON, FormatMarkdown, FormatHTML)
+	}
+}
+
+// Render renders result as a markdown or HTML report
+func Render(format string, result interface{}) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		switch r := result.(type) {
+		case *types.TypeCheckResult:
+			return TypeCheckMarkdown(r), nil
+		case *types.TypeCheckDiffResult:
+			return TypeCheckDiffMarkdown(r), nil
+		case *types.LintResult:
+			return LintMarkdown(r), nil
+		case *types.ImprovementResult:
+			return ImprovementsMarkdown(r), nil
+		case *types.ReviewResult:
+			return ReviewMarkdown(r), nil
+		}
+	case FormatHTML:
+		switch r := result.(type) {
+		case *types.TypeCheckResult:
+			return TypeCheckHTML(r)
+		case *types.TypeCheckDiffResult:
+			return TypeCheckDiffHTML(r)
+		case *types.LintResult:
+			return LintHTML(r)
+		case *types.ImprovementResult:
+			return ImprovementsHTML(r)
+		case *types.ReviewResult:
+			return ReviewHTML(r)
+		}
+	default:
+		return "", ValidateFormat(format)
+	}
+	return "", fmt.Errorf("no %s report for %T", format, result)
+}
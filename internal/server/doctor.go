@@ -0,0 +1,157 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"mcp-typescript-assistant/internal/tools"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// Statuses of a doctor check
+const (
+	checkOK      = "ok"
+	checkWarning = "warning"
+	checkFailed  = "failed"
+)
+
+// doctor runs every environment check and collects the results into a report
+func (h *Handlers) doctor(params types.DoctorParams) *types.DoctorReport {
+	projectRoot := params.ProjectRoot
+	if projectRoot == "" {
+		projectRoot = h.config.ProjectRoot
+	}
+	if projectRoot == "" {
+		projectRoot = "."
+	}
+
+	report := &types.DoctorReport{
+		Checks: []types.DoctorCheck{
+			h.checkNode(),
+			h.checkTSC(projectRoot),
+			h.checkESLint(projectRoot),
+			h.checkTSConfig(projectRoot),
+			checkWritable("temp_dir", os.TempDir()),
+		},
+	}
+	if h.config.CacheDir != "" {
+		report.Checks = append(report.Checks, checkWritable("cache_dir", h.config.CacheDir))
+	}
+
+	failed, warnings := 0, 0
+	for _, check := range report.Checks {
+		switch check.Status {
+		case checkFailed:
+			failed++
+		case checkWarning:
+			warnings++
+		}
+	}
+	report.Healthy = failed == 0
+	report.Summary = fmt.Sprintf("%d of %d checks passed, %d warning(s), %d failure(s)",
+		len(report.Checks)-failed-warnings, len(report.Checks), warnings, failed)
+
+	return report
+}
+
+// checkNode reports the installed Node.js version, warning when it is below the configured minimum
+func (h *Handlers) checkNode() types.DoctorCheck {
+	version, err := tools.GetNodeVersion()
+	if err != nil {
+		return unavailableCheck("node", err)
+	}
+	if tools.NodeBelowMinimum(version, h.config.MinNodeVersion) {
+		return types.DoctorCheck{
+			Name:        "node",
+			Status:      checkWarning,
+			Detail:      fmt.Sprintf("%s is older than the supported minimum v%d", version, h.config.MinNodeVersion),
+			Remediation: fmt.Sprintf("upgrade Node.js to v%d or later, e.g. `nvm install %d`", h.config.MinNodeVersion, h.config.MinNodeVersion),
+		}
+	}
+	return types.DoctorCheck{Name: "node", Status: checkOK, Detail: version}
+}
+
+// checkTSC reports whether the TypeScript compiler that checks projectRoot runs and its version
+func (h *Handlers) checkTSC(projectRoot string) types.DoctorCheck {
+	version, err := h.tscTool.VersionIn(projectRoot)
+	if err != nil {
+		return unavailableCheck("typescript", err)
+	}
+	return types.DoctorCheck{Name: "typescript", Status: checkOK, Detail: fmt.Sprintf("%s (%s)", version, h.tscTool.PathIn(projectRoot))}
+}
+
+// checkESLint reports whether the ESLint that lints projectRoot runs and its version
+func (h *Handlers) checkESLint(projectRoot string) types.DoctorCheck {
+	version, err := h.eslintTool.VersionIn(projectRoot)
+	if err != nil {
+		return unavailableCheck("eslint", err)
+	}
+	return types.DoctorCheck{Name: "eslint", Status: checkOK, Detail: fmt.Sprintf("%s (%s)", version, h.eslintTool.PathIn(projectRoot))}
+}
+
+// checkTSConfig verifies that tsc can parse the project's tsconfig
+func (h *Handlers) checkTSConfig(projectRoot string) types.DoctorCheck {
+	check := types.DoctorCheck{Name: "tsconfig"}
+	configPath := h.tscTool.ConfigPath(projectRoot)
+
+	if _, err := os.Stat(configPath); err != nil {
+		check.Status = checkWarning
+		check.Detail = fmt.Sprintf("%s not found", configPath)
+		check.Remediation = "run `npx tsc --init` or pass the project_root that contains the tsconfig"
+		return check
+	}
+
+	if err := h.tscTool.ShowConfig(projectRoot); err != nil {
+		var unavailable *types.ToolUnavailableError
+		if errors.As(err, &unavailable) {
+			check.Status = checkWarning
+			check.Detail = fmt.Sprintf("%s can't be verified without tsc", configPath)
+			check.Remediation = unavailable.Install
+			return check
+		}
+		check.Status = checkFailed
+		check.Detail = err.Error()
+		check.Remediation = fmt.Sprintf("fix the errors reported for %s", configPath)
+		return check
+	}
+
+	check.Status = checkOK
+	check.Detail = configPath
+	return check
+}
+
+// checkWritable verifies that files can be created in dir
+func checkWritable(name, dir string) types.DoctorCheck {
+	check := types.DoctorCheck{Name: name, Detail: dir}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		check.Status = checkFailed
+		check.Detail = err.Error()
+		check.Remediation = fmt.Sprintf("create %s or point the server at a writable directory", dir)
+		return check
+	}
+	file, err := os.CreateTemp(dir, "doctor-*")
+	if err != nil {
+		check.Status = checkFailed
+		check.Detail = err.Error()
+		check.Remediation = fmt.Sprintf("grant the server write access to %s", dir)
+		return check
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	check.Status = checkOK
+	return check
+}
+
+// unavailableCheck builds a failed check for a tool that can't be run
+func unavailableCheck(name string, err error) types.DoctorCheck {
+	check := types.DoctorCheck{Name: name, Status: checkFailed, Detail: err.Error()}
+
+	var unavailable *types.ToolUnavailableError
+	if errors.As(err, &unavailable) {
+		check.Remediation = unavailable.Install
+	}
+	return check
+}
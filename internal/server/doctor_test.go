@@ -0,0 +1,40 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-typescript-assistant/internal/config"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+func TestDoctorProbesProjectRootBinaries(t *testing.T) {
+	root := t.TempDir()
+	binDir := filepath.Join(root, "node_modules", ".bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, version := range map[string]string{"tsc": "Version 5.6.2", "eslint": "v9.4.0"} {
+		script := "#!/bin/sh\necho '" + version + "'\n"
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The server default differs from the requested project, whose binaries must be used
+	h := NewHandlers(&config.Config{ProjectRoot: t.TempDir()})
+	report := h.doctor(types.DoctorParams{ProjectRoot: root})
+
+	checks := make(map[string]types.DoctorCheck)
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	for name, version := range map[string]string{"typescript": "Version 5.6.2", "eslint": "v9.4.0"} {
+		check := checks[name]
+		if check.Status != checkOK || !strings.Contains(check.Detail, version) || !strings.Contains(check.Detail, binDir) {
+			t.Errorf("%s check = %+v, want %s from %s", name, check, version, binDir)
+		}
+	}
+}
@@ -3,176 +3,587 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"mcp-typescript-assistant/internal/config"
 	"mcp-typescript-assistant/internal/guidelines"
+	"mcp-typescript-assistant/internal/paths"
+	"mcp-typescript-assistant/internal/report"
 	"mcp-typescript-assistant/internal/tools"
 	"mcp-typescript-assistant/internal/typescript"
 	"mcp-typescript-assistant/pkg/types"
 )
 
+// toolNames lists the MCP tools registered by the server
+var toolNames = []string{
+	"type-check",
+	"get-types",
+	"get-types-batch",
+	"resolve-module",
+	"list-eslint-rules",
+	"stats",
+	"lint-check",
+	"suggest-improvements",
+	"load-guidelines",
+	"estimate-size",
+	"config-dump",
+	"server-info",
+	"transpile",
+	"validate-guidelines",
+	"review",
+	"run-tests",
+	"check-dependencies",
+	"import-graph",
+	"apply-improvement",
+	"doctor",
+	"find-references",
+	"go-to-definition",
+	"rename-symbol",
+	"complete",
+	"init-tsconfig",
+	"audit-tsconfig",
+	"type-check-diff",
+}
+
 // Handlers contains all the tool handlers for the MCP server
 type Handlers struct {
 	tscTool     *tools.TypeScriptCompiler
 	eslintTool  *tools.ESLintTool
+	testRunner  *tools.TestRunner
+	npmTool     *tools.NPMTool
 	analyzer    *typescript.Analyzer
 	parser      *guidelines.Parser
+	config      *config.Config
+	startTime   time.Time
+	stats       *toolStats
+	// allowedRoots are the resolved directories tool calls are restricted to, if any
+	allowedRoots []string
 }
 
 // NewHandlers creates a new handlers instance
-func NewHandlers() *Handlers {
+func NewHandlers(cfg *config.Config) *Handlers {
+	toolOptions := []tools.Option{
+		tools.WithPackageManager(cfg.PackageManager),
+		tools.WithProjectRoot(cfg.ProjectRoot),
+		tools.WithCacheDir(cfg.CacheDir),
+		tools.WithTimeout(cfg.Timeout()),
+		tools.WithRetries(cfg.CommandRetries),
+		tools.WithNodeOptions(cfg.NodeOptions),
+		tools.WithEnv(cfg.Env),
+		tools.WithTSConfig(cfg.TSConfig),
+	}
+
 	return &Handlers{
-		tscTool:     tools.NewTypeScriptCompiler(),
-		eslintTool:  tools.NewESLintTool(),
+		tscTool:     tools.NewTypeScriptCompiler(toolOptions...),
+		eslintTool:  tools.NewESLintTool(toolOptions...),
+		testRunner:  tools.NewTestRunner(toolOptions...),
+		npmTool:     tools.NewNPMTool(toolOptions...),
 		analyzer:    typescript.NewAnalyzer(),
 		parser:      guidelines.NewParser(),
+		config:      cfg,
+		startTime:   time.Now(),
+		stats:       newToolStats(),
 	}
 }
 
-// TypeCheckHandler handles TypeScript type checking requests
-func (h *Handlers) TypeCheckHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.TypeCheckParams]) (*mcp.CallToolResultFor[any], error) {
-	result, err := h.tscTool.TypeCheck(params.Arguments)
-	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error performing type check: %v", err),
-				},
-			},
-		}, nil
+// ignoredResult returns an "ignored" tool result when filePath matches the
+// configured ignore list, or nil when the tool should run normally
+func (h *Handlers) ignoredResult(filePath string) *mcp.CallToolResultFor[any] {
+	if filePath == "" || !paths.MatchAnySuffix(h.config.IgnorePaths, filePath) {
+		return nil
 	}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error marshaling result: %v", err),
-				},
+	result := types.IgnoredResult{
+		FilePath: filePath,
+		Ignored:  true,
+		Reason:   "Path matches the server's IGNORE_PATHS configuration",
+	}
+
+	return formattedResult("", result)
+}
+
+// withoutIgnored drops the files that match the server's IGNORE_PATHS configuration
+func (h *Handlers) withoutIgnored(files []string) []string {
+	var kept []string
+	for _, file := range files {
+		if !paths.MatchAnySuffix(h.config.IgnorePaths, file) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// toolErrorResult builds an error result for a failed tool run. Missing external tools
+// get an additional machine-readable block so clients can suggest the install command.
+func toolErrorResult(message string, err error) *mcp.CallToolResultFor[any] {
+	result := &mcp.CallToolResultFor[any]{
+		IsError: true,
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("%s: %v", message, err),
 			},
-		}, nil
+		},
+	}
+
+	var unavailable *types.ToolUnavailableError
+	if errors.As(err, &unavailable) {
+		if detailJSON, marshalErr := json.Marshal(unavailable); marshalErr == nil {
+			result.Content = append(result.Content, &mcp.TextContent{
+				Text: string(detailJSON),
+			})
+		}
+	}
+
+	return result
+}
+
+// formattedResult returns result as indented JSON, or as a markdown or HTML report
+// when format asks for one
+func formattedResult(format string, result interface{}) *mcp.CallToolResultFor[any] {
+	var text string
+	if format == "" || format == report.FormatJSON {
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return toolErrorResult("Error marshaling result", err)
+		}
+		text = string(resultJSON)
+	} else if rendered, err := report.Render(format, result); err != nil {
+		return toolErrorResult("Error rendering report", err)
+	} else {
+		text = rendered
 	}
 
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
 			&mcp.TextContent{
-				Text: string(resultJSON),
+				Text: text,
 			},
 		},
-	}, nil
+	}
+}
+
+// TypeCheckHandler handles TypeScript type checking requests
+func (h *Handlers) TypeCheckHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.TypeCheckParams]) (*mcp.CallToolResultFor[any], error) {
+	if err := report.ValidateFormat(params.Arguments.Format); err != nil {
+		return toolErrorResult("Invalid arguments", err), nil
+	}
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+	if files := params.Arguments.FilePaths; len(files) > 0 {
+		params.Arguments.FilePaths = h.withoutIgnored(files)
+		if len(params.Arguments.FilePaths) == 0 {
+			return h.ignoredResult(files[0]), nil
+		}
+	}
+
+	// Snippets containing JSX must be written as .tsx for tsc to parse them
+	if params.Arguments.CodeSnippet != "" && params.Arguments.Language == "" && typescript.ContainsJSX(params.Arguments.CodeSnippet) {
+		params.Arguments.Language = "tsx"
+	}
+
+	if params.Arguments.BaseRef == "" {
+		params.Arguments.BaseRef = h.config.BaseRef
+	}
+
+	params.Arguments.Progress = progressReporter(ctx, cc, params.GetProgressToken(), "Checked")
+	params.Arguments.Context = ctx
+	result, err := h.tscTool.TypeCheck(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error performing type check", err), nil
+	}
+	h.stats.recordIssues("type-check", result.Total)
+	if params.Arguments.Incremental {
+		h.stats.recordCache(result.IncrementalCacheUsed)
+	}
+
+	return formattedResult(params.Arguments.Format, result), nil
+}
+
+// TypeCheckDiffHandler handles requests for the type errors introduced since a baseline
+func (h *Handlers) TypeCheckDiffHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.TypeCheckDiffParams]) (*mcp.CallToolResultFor[any], error) {
+	if err := report.ValidateFormat(params.Arguments.Format); err != nil {
+		return toolErrorResult("Invalid arguments", err), nil
+	}
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.TypeCheckDiff(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error performing type check diff", err), nil
+	}
+
+	return formattedResult(params.Arguments.Format, result), nil
+}
+
+// TranspileHandler handles TypeScript transpilation requests
+func (h *Handlers) TranspileHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.TranspileParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.Transpile(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error transpiling", err), nil
+	}
+
+	return formattedResult("", result), nil
 }
 
 // GetTypesHandler handles type information extraction requests
 func (h *Handlers) GetTypesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.GetTypesParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
 	result, err := h.tscTool.GetTypes(params.Arguments)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error extracting type information: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error extracting type information", err), nil
 	}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	return formattedResult("", result), nil
+}
+
+// GetTypesBatchHandler handles requests for the types of several symbols in one file
+func (h *Handlers) GetTypesBatchHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.GetTypesBatchParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.GetTypesBatch(params.Arguments)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error marshaling result: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error extracting type information", err), nil
 	}
 
-	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return formattedResult("", result), nil
+}
+
+// ResolveModuleHandler handles requests to resolve an import specifier
+func (h *Handlers) ResolveModuleHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.ResolveModuleParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.ResolveModule(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error resolving module", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// ListESLintRulesHandler handles requests for the ESLint rules configured for a file
+func (h *Handlers) ListESLintRulesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.ListESLintRulesParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.eslintTool.ListRules(params.Arguments.FilePath)
+	if err != nil {
+		return toolErrorResult("Error listing ESLint rules", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// FindReferencesHandler handles requests for the references to a symbol
+func (h *Handlers) FindReferencesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.SymbolPositionParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.FindReferences(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error finding references", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// GoToDefinitionHandler handles requests for the definition of a symbol
+func (h *Handlers) GoToDefinitionHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.SymbolPositionParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.GoToDefinition(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error finding definition", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// RenameSymbolHandler handles requests to rename a symbol across a project
+func (h *Handlers) RenameSymbolHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.RenameSymbolParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	params.Arguments.AllowedRoots = h.allowedRoots
+	result, err := typescript.RenameSymbol(h.tscTool, params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error renaming symbol", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// CompleteHandler handles code completion requests
+func (h *Handlers) CompleteHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.CompleteParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.tscTool.Complete(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error listing completions", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// InitTSConfigHandler handles requests to generate a recommended tsconfig.json
+func (h *Handlers) InitTSConfigHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.InitTSConfigParams]) (*mcp.CallToolResultFor[any], error) {
+	result, err := h.tscTool.InitTSConfig(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error generating tsconfig", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// AuditTSConfigHandler handles tsconfig strictness audit requests
+func (h *Handlers) AuditTSConfigHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.AuditTSConfigParams]) (*mcp.CallToolResultFor[any], error) {
+	result, err := h.tscTool.AuditTSConfig(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error auditing tsconfig", err), nil
+	}
+
+	return formattedResult("", result), nil
 }
 
 // LintCheckHandler handles ESLint checking requests
 func (h *Handlers) LintCheckHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.LintCheckParams]) (*mcp.CallToolResultFor[any], error) {
+	if err := report.ValidateFormat(params.Arguments.Format); err != nil {
+		return toolErrorResult("Invalid arguments", err), nil
+	}
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+	if files := params.Arguments.FilePaths; len(files) > 0 {
+		params.Arguments.FilePaths = h.withoutIgnored(files)
+		if len(params.Arguments.FilePaths) == 0 {
+			return h.ignoredResult(files[0]), nil
+		}
+	}
+
+	if params.Arguments.BaseRef == "" {
+		params.Arguments.BaseRef = h.config.BaseRef
+	}
+
+	params.Arguments.Progress = progressReporter(ctx, cc, params.GetProgressToken(), "Linted")
+	params.Arguments.Context = ctx
 	result, err := h.eslintTool.LintCheck(params.Arguments)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error performing lint check: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error performing lint check", err), nil
 	}
+	h.stats.recordIssues("lint-check", result.TotalIssues)
+
+	return formattedResult(params.Arguments.Format, result), nil
+}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+// RunTestsHandler handles jest/vitest test run requests
+func (h *Handlers) RunTestsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.RunTestsParams]) (*mcp.CallToolResultFor[any], error) {
+	result, err := h.testRunner.RunTests(params.Arguments)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error marshaling result: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error running tests", err), nil
 	}
 
-	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return formattedResult("", result), nil
+}
+
+// CheckDependenciesHandler handles npm outdated/audit requests
+func (h *Handlers) CheckDependenciesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.CheckDependenciesParams]) (*mcp.CallToolResultFor[any], error) {
+	result, err := h.npmTool.CheckDependencies(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error checking dependencies", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// ImportGraphHandler handles module dependency graph requests
+func (h *Handlers) ImportGraphHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.ImportGraphParams]) (*mcp.CallToolResultFor[any], error) {
+	params.Arguments.Exclude = append(params.Arguments.Exclude, h.config.IgnorePaths...)
+
+	result, err := h.analyzer.BuildImportGraph(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error building import graph", err), nil
+	}
+
+	return formattedResult("", result), nil
 }
 
 // SuggestImprovementsHandler handles code improvement suggestion requests
 func (h *Handlers) SuggestImprovementsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.SuggestImprovementsParams]) (*mcp.CallToolResultFor[any], error) {
+	if err := report.ValidateFormat(params.Arguments.Format); err != nil {
+		return toolErrorResult("Invalid arguments", err), nil
+	}
+	// Project-wide analysis skips ignored files rather than rejecting the whole request
+	params.Arguments.Exclude = append(params.Arguments.Exclude, h.config.IgnorePaths...)
+
+	// Snippets have no project of their own, so use the server's project ignore file
+	if params.Arguments.ProjectRoot == "" {
+		params.Arguments.IgnoreRules = append(params.Arguments.IgnoreRules, typescript.ReadIgnoreFile(h.config.ProjectRoot)...)
+	}
+
+	if params.Arguments.ChangedOnly {
+		if params.Arguments.ProjectRoot == "" {
+			return toolErrorResult("Error suggesting improvements", fmt.Errorf("changed_only requires a project_root")), nil
+		}
+		baseRef := params.Arguments.BaseRef
+		if baseRef == "" {
+			baseRef = h.config.BaseRef
+		}
+		changed, err := tools.ChangedFiles(params.Arguments.ProjectRoot, baseRef)
+		if err != nil {
+			return toolErrorResult("Error suggesting improvements", err), nil
+		}
+		params.Arguments.ChangedFiles = changed
+	}
+
+	params.Arguments.Progress = progressReporter(ctx, cc, params.GetProgressToken(), "Analyzed")
 	result, err := h.analyzer.SuggestImprovements(params.Arguments)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error suggesting improvements: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error suggesting improvements", err), nil
+	}
+	h.stats.recordIssues("suggest-improvements", result.TotalIssues)
+
+	return formattedResult(params.Arguments.Format, result), nil
+}
+
+// ReviewHandler runs type checking, linting and improvement analysis on a file in one call
+func (h *Handlers) ReviewHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.ReviewParams]) (*mcp.CallToolResultFor[any], error) {
+	if err := report.ValidateFormat(params.Arguments.Format); err != nil {
+		return toolErrorResult("Invalid arguments", err), nil
+	}
+	if params.Arguments.Glob != "" || len(params.Arguments.FilePaths) > 0 {
+		files := params.Arguments.FilePaths
+		if params.Arguments.Glob != "" {
+			root := params.Arguments.ProjectRoot
+			if root == "" {
+				root = h.config.ProjectRoot
+			}
+			if root == "" {
+				root = "."
+			}
+			matched, _, err := h.analyzer.MatchProjectFiles(root, []string{params.Arguments.Glob})
+			if err != nil {
+				return toolErrorResult("Error reviewing files", err), nil
+			}
+			files = append(files, matched...)
+		}
+		files = h.withoutIgnored(files)
+		if len(files) == 0 {
+			return toolErrorResult("Error reviewing files", fmt.Errorf("no files to review")), nil
+		}
+
+		progress := progressReporter(ctx, cc, params.GetProgressToken(), "Reviewed")
+		result, err := h.reviewFiles(ctx, files, params.Arguments.Concurrency, progress)
+		if err != nil {
+			return toolErrorResult("Error reviewing files", err), nil
+		}
+		return formattedResult(params.Arguments.Format, result), nil
 	}
 
-	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.review(ctx, params.Arguments.FilePath)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error marshaling result: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error reviewing file", err), nil
 	}
 
-	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return formattedResult(params.Arguments.Format, result), nil
+}
+
+// ApplyImprovementHandler applies a suggested before/after replacement to a file
+func (h *Handlers) ApplyImprovementHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.ApplyImprovementParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	response := map[string]interface{}{
+		"file_path": params.Arguments.FilePath,
+	}
+
+	improvement := types.Improvement{
+		Before: params.Arguments.Before,
+		After:  params.Arguments.After,
+		Line:   params.Arguments.Line,
+	}
+	if params.Arguments.ImprovementID != "" {
+		found, err := h.findImprovement(params.Arguments.FilePath, params.Arguments.ImprovementID)
+		if err != nil {
+			return toolErrorResult("Error applying improvement", err), nil
+		}
+		improvement = *found
+	}
+	backupPath, err := h.analyzer.ApplyImprovement(params.Arguments.FilePath, improvement, params.Arguments.Backup)
+	if err != nil {
+		return toolErrorResult("Error applying improvement", err), nil
+	}
+	response["applied"] = true
+	if backupPath != "" {
+		response["backup_path"] = backupPath
+	}
+
+	return formattedResult("", response), nil
+}
+
+// findImprovement re-analyzes filePath and returns the improvement with the given ID
+func (h *Handlers) findImprovement(filePath, id string) (*types.Improvement, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	result, err := h.analyzer.SuggestImprovements(types.SuggestImprovementsParams{
+		CodeSnippet: string(content),
+		Language:    paths.LanguageFor(filePath),
+		MaxIssues:   math.MaxInt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, improvement := range result.Improvements {
+		if improvement.ID != id {
+			continue
+		}
+		if improvement.After == "" {
+			return nil, fmt.Errorf("improvement %s has no automatic replacement", id)
+		}
+		return &improvement, nil
+	}
+
+	return nil, fmt.Errorf("improvement %s not found in %s; the file may have changed since it was analyzed", id, filePath)
 }
 
 // LoadGuidelinesHandler handles guideline loading requests
 func (h *Handlers) LoadGuidelinesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.LoadGuidelinesParams]) (*mcp.CallToolResultFor[any], error) {
 	guidelineSet, err := h.parser.ParseGuidelinesFromFile(params.Arguments.GuidelinePath, params.Arguments.GuidelineType)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error loading guidelines: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error loading guidelines", err), nil
 	}
 
 	// Validate guidelines
@@ -188,45 +599,112 @@ func (h *Handlers) LoadGuidelinesHandler(ctx context.Context, cc *mcp.ServerSess
 		"message":        fmt.Sprintf("Successfully loaded %d guidelines from %s", len(guidelineSet.Guidelines), guidelineSet.Name),
 	}
 
-	resultJSON, err := json.MarshalIndent(response, "", "  ")
+	return formattedResult("", response), nil
+}
+
+// ValidateGuidelinesHandler parses and validates a guideline file without loading it into the analyzer
+func (h *Handlers) ValidateGuidelinesHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.ValidateGuidelinesParams]) (*mcp.CallToolResultFor[any], error) {
+	guidelineSet, err := h.parser.ParseGuidelinesFromFile(params.Arguments.GuidelinePath, params.Arguments.GuidelineType)
 	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error marshaling result: %v", err),
-				},
-			},
-		}, nil
+		return toolErrorResult("Error parsing guidelines", err), nil
 	}
 
-	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(resultJSON),
-			},
+	warnings := h.parser.ValidateGuidelines(guidelineSet)
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	response := map[string]interface{}{
+		"valid":         len(warnings) == 0,
+		"guideline_set": guidelineSet,
+		"warnings":      warnings,
+		"message":       fmt.Sprintf("Parsed %d guidelines from %s with %d warnings", len(guidelineSet.Guidelines), guidelineSet.Name, len(warnings)),
+	}
+
+	return formattedResult("", response), nil
+}
+
+// EstimateSizeHandler handles code size estimation requests
+func (h *Handlers) EstimateSizeHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.EstimateSizeParams]) (*mcp.CallToolResultFor[any], error) {
+	if ignored := h.ignoredResult(params.Arguments.FilePath); ignored != nil {
+		return ignored, nil
+	}
+
+	result, err := h.analyzer.EstimateSize(params.Arguments)
+	if err != nil {
+		return toolErrorResult("Error estimating size", err), nil
+	}
+
+	return formattedResult("", result), nil
+}
+
+// StatsHandler reports how the server's tools have been used since startup or the last reset
+func (h *Handlers) StatsHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.StatsParams]) (*mcp.CallToolResultFor[any], error) {
+	result := h.stats.snapshot(params.Arguments.Reset)
+
+	return formattedResult("", result), nil
+}
+
+// ConfigDumpHandler returns the effective runtime configuration of the server with secrets redacted
+func (h *Handlers) ConfigDumpHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[any], error) {
+	loadedGuidelines := h.analyzer.GetLoadedGuidelines()
+	guidelineNames := make([]string, 0, len(loadedGuidelines))
+	for name := range loadedGuidelines {
+		guidelineNames = append(guidelineNames, name)
+	}
+	sort.Strings(guidelineNames)
+
+	dump := map[string]interface{}{
+		"config": h.config.Redacted(),
+		"tools":  toolNames,
+		"external_tools": map[string]string{
+			"tsc":    h.tscTool.Path(),
+			"eslint": h.eslintTool.Path(),
 		},
-	}, nil
+		"limits": map[string]int{
+			"max_project_files": typescript.MaxProjectFiles,
+			"max_issues":        types.DefaultMaxIssues,
+		},
+		"loaded_guidelines": guidelineNames,
+	}
+
+	return formattedResult("", dump), nil
+}
+
+// DoctorHandler runs an environment diagnostic and reports how to fix any problems
+func (h *Handlers) DoctorHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[types.DoctorParams]) (*mcp.CallToolResultFor[any], error) {
+	report := h.doctor(params.Arguments)
+
+	return formattedResult("", report), nil
 }
 
 // GetServerInfoHandler provides information about the server capabilities
-func (h *Handlers) GetServerInfoHandler(ctx context.Context, cc *mcp.ServerSession) (*mcp.CallToolResultFor[any], error) {
+func (h *Handlers) GetServerInfoHandler(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[any], error) {
 	info := map[string]interface{}{
 		"name":        "typescript-analyzer",
 		"version":     "1.0.0",
 		"description": "TypeScript development tools and best practices analyzer",
-		"tools": []string{
-			"type-check",
-			"get-types", 
-			"lint-check",
-			"suggest-improvements",
-			"load-guidelines",
-		},
+		"tools":       toolNames,
 		"capabilities": map[string]bool{
 			"typescript_compilation": true,
 			"eslint_integration":     true,
 			"code_analysis":          true,
 			"custom_guidelines":      true,
 			"type_extraction":        true,
+			"size_estimation":        true,
+			"transpilation":          true,
+			"code_review":            true,
+			"test_running":           true,
+			"dependency_checks":      true,
+			"import_graph":           true,
+			"apply_improvements":     true,
+			"language_service":       true,
+			"tsconfig_management":    true,
+			"type_check_diff":        true,
+			"module_resolution":      true,
+			"eslint_rules":           true,
+			"usage_stats":            true,
+			"environment_diagnosis":  true,
 		},
 	}
 
@@ -234,6 +712,7 @@ func (h *Handlers) GetServerInfoHandler(ctx context.Context, cc *mcp.ServerSessi
 	toolStatus := make(map[string]bool)
 	toolStatus["typescript"] = h.tscTool.CheckTSCAvailable() == nil
 	toolStatus["eslint"] = h.eslintTool.CheckESLintAvailable() == nil
+	toolStatus["node"] = tools.CheckNodeAvailable() == nil
 
 	info["tool_status"] = toolStatus
 
@@ -245,32 +724,36 @@ func (h *Handlers) GetServerInfoHandler(ctx context.Context, cc *mcp.ServerSessi
 	if eslintVersion, err := h.eslintTool.GetVersion(); err == nil {
 		versions["eslint"] = eslintVersion
 	}
+	if nodeVersion, err := tools.GetNodeVersion(); err == nil {
+		versions["node"] = nodeVersion
+		if tools.NodeBelowMinimum(nodeVersion, h.config.MinNodeVersion) {
+			info["warnings"] = []string{fmt.Sprintf("Node.js %s is older than the supported minimum v%d", nodeVersion, h.config.MinNodeVersion)}
+		}
+	}
 	info["versions"] = versions
 
+	info["binaries"] = map[string]string{
+		"typescript": h.tscTool.Path(),
+		"eslint":     h.eslintTool.Path(),
+	}
+	info["uptime"] = time.Since(h.startTime).Round(time.Second).String()
+
+	// Report what the caches hold so clients can tell cold results from warm ones
+	stats := h.stats.snapshot(false)
+	info["caches"] = map[string]int{
+		"eslint_configs":     h.eslintTool.ConfigCacheSize(),
+		"tsbuildinfo_hits":   stats.CacheHits,
+		"tsbuildinfo_misses": stats.CacheMisses,
+	}
+
 	// Get loaded guidelines
 	loadedGuidelines := h.analyzer.GetLoadedGuidelines()
 	guidelineNames := make([]string, 0, len(loadedGuidelines))
 	for name := range loadedGuidelines {
 		guidelineNames = append(guidelineNames, name)
 	}
+	sort.Strings(guidelineNames)
 	info["loaded_guidelines"] = guidelineNames
 
-	resultJSON, err := json.MarshalIndent(info, "", "  ")
-	if err != nil {
-		return &mcp.CallToolResultFor[any]{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Error marshaling server info: %v", err),
-				},
-			},
-		}, nil
-	}
-
-	return &mcp.CallToolResultFor[any]{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: string(resultJSON),
-			},
-		},
-	}, nil
+	return formattedResult("", info), nil
 }
\ No newline at end of file
@@ -0,0 +1,253 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"mcp-typescript-assistant/internal/config"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// writeFakeTool installs a project-local tsc or eslint that appends its arguments to
+// the returned log file and prints nothing
+func writeFakeTool(t *testing.T, root, name string) string {
+	t.Helper()
+	binDir := filepath.Join(root, "node_modules", ".bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(root, name+".log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + logFile + "'\n"
+	if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return logFile
+}
+
+// resultText returns the text of a tool result's first content block
+func resultText(t *testing.T, result *mcp.CallToolResultFor[any]) string {
+	t.Helper()
+	if result == nil || len(result.Content) == 0 {
+		t.Fatal("tool returned no content")
+	}
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("content is %T, want text", result.Content[0])
+	}
+	return text.Text
+}
+
+// assertIgnored checks that result reports filePath as ignored
+func assertIgnored(t *testing.T, result *mcp.CallToolResultFor[any], filePath string) {
+	t.Helper()
+	var ignored types.IgnoredResult
+	if err := json.Unmarshal([]byte(resultText(t, result)), &ignored); err != nil {
+		t.Fatalf("result is not an ignored result: %v", err)
+	}
+	if !ignored.Ignored || ignored.FilePath != filePath || ignored.Reason == "" {
+		t.Errorf("result = %+v, want %s ignored with a reason", ignored, filePath)
+	}
+}
+
+// ignoreFixture creates a project with a generated and a hand-written file, fake tsc and
+// eslint binaries and handlers that ignore generated code
+func ignoreFixture(t *testing.T) (h *Handlers, generated, source, tscLog, eslintLog string) {
+	t.Helper()
+	root := t.TempDir()
+	generated = filepath.Join(root, "src", "generated", "api.ts")
+	source = filepath.Join(root, "src", "index.ts")
+	for _, file := range []string{generated, source} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("export const value = 1;\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tscLog = writeFakeTool(t, root, "tsc")
+	eslintLog = writeFakeTool(t, root, "eslint")
+
+	h = NewHandlers(&config.Config{ProjectRoot: root, IgnorePaths: []string{"generated/**"}})
+	return h, generated, source, tscLog, eslintLog
+}
+
+// assertNotRun checks that a fake tool never ran
+func assertNotRun(t *testing.T, logFile string) {
+	t.Helper()
+	if data, err := os.ReadFile(logFile); err == nil {
+		t.Errorf("%s ran with %q", filepath.Base(logFile), data)
+	}
+}
+
+func TestTypeCheckSkipsIgnoredPaths(t *testing.T) {
+	h, generated, _, tscLog, _ := ignoreFixture(t)
+
+	result, err := h.TypeCheckHandler(context.Background(), nil, &mcp.CallToolParamsFor[types.TypeCheckParams]{
+		Arguments: types.TypeCheckParams{FilePath: generated},
+	})
+	if err != nil {
+		t.Fatalf("TypeCheckHandler: %v", err)
+	}
+	assertIgnored(t, result, generated)
+
+	result, err = h.TypeCheckHandler(context.Background(), nil, &mcp.CallToolParamsFor[types.TypeCheckParams]{
+		Arguments: types.TypeCheckParams{FilePaths: []string{generated}},
+	})
+	if err != nil {
+		t.Fatalf("TypeCheckHandler: %v", err)
+	}
+	assertIgnored(t, result, generated)
+	assertNotRun(t, tscLog)
+}
+
+func TestTypeCheckDropsIgnoredFilePaths(t *testing.T) {
+	h, generated, source, tscLog, _ := ignoreFixture(t)
+
+	result, err := h.TypeCheckHandler(context.Background(), nil, &mcp.CallToolParamsFor[types.TypeCheckParams]{
+		Arguments: types.TypeCheckParams{FilePaths: []string{generated, source}},
+	})
+	if err != nil {
+		t.Fatalf("TypeCheckHandler: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("TypeCheckHandler failed: %s", resultText(t, result))
+	}
+
+	data, err := os.ReadFile(tscLog)
+	if err != nil {
+		t.Fatalf("tsc did not run: %v", err)
+	}
+	if log := string(data); !strings.Contains(log, source) || strings.Contains(log, generated) {
+		t.Errorf("tsc ran with %q, want only %s", log, source)
+	}
+}
+
+func TestLintCheckSkipsIgnoredPaths(t *testing.T) {
+	h, generated, _, _, eslintLog := ignoreFixture(t)
+
+	result, err := h.LintCheckHandler(context.Background(), nil, &mcp.CallToolParamsFor[types.LintCheckParams]{
+		Arguments: types.LintCheckParams{FilePath: generated},
+	})
+	if err != nil {
+		t.Fatalf("LintCheckHandler: %v", err)
+	}
+	assertIgnored(t, result, generated)
+
+	result, err = h.LintCheckHandler(context.Background(), nil, &mcp.CallToolParamsFor[types.LintCheckParams]{
+		Arguments: types.LintCheckParams{FilePaths: []string{generated}},
+	})
+	if err != nil {
+		t.Fatalf("LintCheckHandler: %v", err)
+	}
+	assertIgnored(t, result, generated)
+	assertNotRun(t, eslintLog)
+}
+
+func TestIgnoredResultWithoutIgnorePaths(t *testing.T) {
+	h := NewHandlers(&config.Config{})
+	if result := h.ignoredResult("src/generated/api.ts"); result != nil {
+		t.Errorf("ignoredResult = %s, want nil without IGNORE_PATHS", resultText(t, result))
+	}
+}
+
+func TestConfigDumpReflectsOverridesAndRedactsSecrets(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "tsassistant.config.yaml")
+	content := "command_retries: 1\ncomplexity_threshold: 15\nenv:\n  NPM_TOKEN: npm_s3cr3t\n"
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.FileEnv, configFile)
+	t.Setenv("COMMAND_RETRIES", "3")
+	t.Setenv("COMPLEXITY_THRESHOLD", "")
+	t.Setenv("TOOL_ENV", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("DEBUG", "")
+	t.Setenv("MIN_NODE_VERSION", "")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	h := NewHandlers(cfg)
+
+	result, err := h.ConfigDumpHandler(context.Background(), nil, &mcp.CallToolParamsFor[struct{}]{})
+	if err != nil {
+		t.Fatalf("ConfigDumpHandler: %v", err)
+	}
+	text := resultText(t, result)
+
+	var dump struct {
+		Config config.Config  `json:"config"`
+		Tools  []string       `json:"tools"`
+		Limits map[string]int `json:"limits"`
+	}
+	if err := json.Unmarshal([]byte(text), &dump); err != nil {
+		t.Fatalf("config dump is not JSON: %v", err)
+	}
+
+	if dump.Config.CommandRetries != 3 {
+		t.Errorf("CommandRetries = %d, want 3 from the environment over the file", dump.Config.CommandRetries)
+	}
+	if dump.Config.ComplexityThreshold != 15 {
+		t.Errorf("ComplexityThreshold = %d, want 15 from the file", dump.Config.ComplexityThreshold)
+	}
+	if dump.Config.MinNodeVersion != config.DefaultMinNodeVersion || dump.Config.LogLevel != "info" {
+		t.Errorf("MinNodeVersion = %d, LogLevel = %q, want the defaults", dump.Config.MinNodeVersion, dump.Config.LogLevel)
+	}
+	if dump.Config.File != configFile {
+		t.Errorf("File = %q, want %q", dump.Config.File, configFile)
+	}
+	if dump.Config.Env["NPM_TOKEN"] != "***" || strings.Contains(text, "npm_s3cr3t") {
+		t.Errorf("config dump exposes the token: %s", text)
+	}
+	if cfg.Env["NPM_TOKEN"] != "npm_s3cr3t" {
+		t.Errorf("redaction changed the server configuration: Env = %v", cfg.Env)
+	}
+	if len(dump.Tools) != len(toolNames) || dump.Limits["max_issues"] != types.DefaultMaxIssues {
+		t.Errorf("tools = %v, limits = %v, want the registered tools and limits", dump.Tools, dump.Limits)
+	}
+}
+
+func TestServerInfoReportsCachesAndSortedGuidelines(t *testing.T) {
+	root := t.TempDir()
+	writeFakeTool(t, root, "tsc")
+	writeFakeTool(t, root, "eslint")
+
+	h := NewHandlers(&config.Config{ProjectRoot: root})
+	for _, name := range []string{"security", "api", "react"} {
+		h.analyzer.LoadGuidelines(&types.GuidelineSet{Name: name})
+	}
+	h.stats.recordCache(true)
+	h.stats.recordCache(false)
+	h.stats.recordCache(true)
+
+	result, err := h.GetServerInfoHandler(context.Background(), nil, &mcp.CallToolParamsFor[struct{}]{})
+	if err != nil {
+		t.Fatalf("GetServerInfoHandler: %v", err)
+	}
+	var info struct {
+		Capabilities     map[string]bool `json:"capabilities"`
+		Caches           map[string]int  `json:"caches"`
+		LoadedGuidelines []string        `json:"loaded_guidelines"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &info); err != nil {
+		t.Fatalf("server-info is not JSON: %v", err)
+	}
+
+	if want := []string{"api", "react", "security"}; strings.Join(info.LoadedGuidelines, ",") != strings.Join(want, ",") {
+		t.Errorf("loaded_guidelines = %q, want %q", info.LoadedGuidelines, want)
+	}
+	if info.Caches["eslint_configs"] != 0 || info.Caches["tsbuildinfo_hits"] != 2 || info.Caches["tsbuildinfo_misses"] != 1 {
+		t.Errorf("caches = %v, want no ESLint configs, 2 hits and 1 miss", info.Caches)
+	}
+	for _, capability := range []string{"code_review", "language_service", "tsconfig_management"} {
+		if !info.Capabilities[capability] {
+			t.Errorf("capabilities = %v, want %s", info.Capabilities, capability)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxLoggedArgs caps how much of a call's arguments are logged, since snippets can be large
+const maxLoggedArgs = 200
+
+// instrument wraps a tool handler so that every call is logged to stderr with a
+// correlation ID, an argument summary, its duration and its outcome, and counted in the
+// handlers' stats. Calls naming paths outside the allowed roots are rejected unrun.
+func instrument[In any](h *Handlers, name string, handler mcp.ToolHandlerFor[In, any]) mcp.ToolHandlerFor[In, any] {
+	return func(ctx context.Context, cc *mcp.ServerSession, params *mcp.CallToolParamsFor[In]) (*mcp.CallToolResultFor[any], error) {
+		logger := slog.With("tool", name, "call_id", newCallID())
+		logger.Info("tool call started", "args", summarizeArgs(params.Arguments))
+
+		startTime := time.Now()
+		var result *mcp.CallToolResultFor[any]
+		var err error
+		if pathErr := h.validateArgumentPaths(params.Arguments); pathErr != nil {
+			result = toolErrorResult("Error checking paths", pathErr)
+		} else {
+			result, err = handler(ctx, cc, params)
+		}
+		duration := time.Since(startTime)
+		h.stats.recordCall(name, duration, err != nil || isErrorResult(result))
+
+		switch {
+		case err != nil:
+			logger.Error("tool call failed", "duration", duration, "error", err)
+		case isErrorResult(result):
+			logger.Warn("tool call returned an error", "duration", duration, "error", firstText(result))
+		default:
+			logger.Info("tool call finished", "duration", duration)
+		}
+
+		return result, err
+	}
+}
+
+// newCallID returns a short random identifier used to correlate the log lines of one call
+func newCallID() string {
+	var id [6]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// summarizeArgs renders tool arguments as JSON, truncated to maxLoggedArgs bytes.
+// Per-call env values often carry tokens, so like config-dump only their names are shown.
+func summarizeArgs(args any) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "<unprintable>"
+	}
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) == nil {
+		if env, ok := fields["env"].(map[string]any); ok && len(env) > 0 {
+			for key := range env {
+				env[key] = "***"
+			}
+			if redacted, err := json.Marshal(fields); err == nil {
+				data = redacted
+			}
+		}
+	}
+	if len(data) > maxLoggedArgs {
+		return string(data[:maxLoggedArgs]) + "..."
+	}
+	return string(data)
+}
+
+// isErrorResult reports whether a handler reported an error in its result. Handlers
+// return errors as toolErrorResult results rather than as Go errors.
+func isErrorResult(result *mcp.CallToolResultFor[any]) bool {
+	return result != nil && result.IsError
+}
+
+// firstText returns the text of the first content item of a result, if any
+func firstText(result *mcp.CallToolResultFor[any]) string {
+	if len(result.Content) == 0 {
+		return ""
+	}
+	if text, ok := result.Content[0].(*mcp.TextContent); ok {
+		return text.Text
+	}
+	return ""
+}
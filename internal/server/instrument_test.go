@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"mcp-typescript-assistant/internal/config"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+func TestInstrumentCountsInvalidArgumentsAsFailures(t *testing.T) {
+	h := NewHandlers(&config.Config{})
+	handler := instrument(h, "type-check", h.TypeCheckHandler)
+
+	params := &mcp.CallToolParamsFor[types.TypeCheckParams]{
+		Arguments: types.TypeCheckParams{FilePath: "index.ts", Format: "yaml"},
+	}
+	result, err := handler(context.Background(), nil, params)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("result = %q, want an error result", resultText(t, result))
+	}
+
+	stats := h.stats.snapshot(false)
+	if stats.TotalCalls != 1 || stats.TotalFailures != 1 {
+		t.Errorf("stats = %d calls and %d failures, want 1 of each", stats.TotalCalls, stats.TotalFailures)
+	}
+}
+
+func TestIsErrorResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResultFor[any]
+		want   bool
+	}{
+		{"nil", nil, false},
+		{"tool error", toolErrorResult("Invalid arguments", context.Canceled), true},
+		{"success", formattedResult("", map[string]string{"message": "Error counts are zero"}), false},
+	}
+	for _, tt := range tests {
+		if got := isErrorResult(tt.result); got != tt.want {
+			t.Errorf("%s: isErrorResult = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeArgsRedactsEnv(t *testing.T) {
+	summary := summarizeArgs(types.TypeCheckParams{FilePath: "index.ts", Env: map[string]string{"NPM_TOKEN": "npm_s3cr3t"}})
+	if strings.Contains(summary, "npm_s3cr3t") || !strings.Contains(summary, `"NPM_TOKEN":"***"`) {
+		t.Errorf("summarizeArgs = %s, want the env value masked", summary)
+	}
+	if !strings.Contains(summary, "index.ts") {
+		t.Errorf("summarizeArgs = %s, want the other arguments kept", summary)
+	}
+}
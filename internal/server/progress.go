@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// progressReporter returns a ProgressFunc that sends MCP progress notifications for
+// the request identified by token, or nil when the client didn't ask for progress
+func progressReporter(ctx context.Context, session *mcp.ServerSession, token any, verb string) types.ProgressFunc {
+	if session == nil || token == nil {
+		return nil
+	}
+
+	return func(done, total int) {
+		err := session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(done),
+			Total:         float64(total),
+			Message:       fmt.Sprintf("%s %d/%d files", verb, done, total),
+		})
+		if err != nil {
+			slog.Debug("failed to send progress notification", "error", err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"mcp-typescript-assistant/internal/paths"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// Points deducted from a review score for each type error and lint issue; improvements
+// cost what they cost in the analyzer's own score
+const (
+	typeErrorPenalty   = 10
+	lintErrorPenalty   = 5
+	lintWarningPenalty = 2
+)
+
+// review runs type checking, ESLint and the analyzer on a file concurrently. A step
+// that fails is reported in StepErrors rather than failing the whole review.
+func (h *Handlers) review(ctx context.Context, filePath string) (*types.ReviewResult, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	result := &types.ReviewResult{
+		FilePath: filePath,
+	}
+
+	var mu sync.Mutex
+	stepErrors := make(map[string]string)
+	recordError := func(step string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		stepErrors[step] = err.Error()
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+
+	group.Go(func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		typeCheck, err := h.tscTool.TypeCheck(types.TypeCheckParams{FilePath: filePath, Context: ctx})
+		if err != nil {
+			recordError("type_check", err)
+			return nil
+		}
+		result.TypeCheck = typeCheck
+		return nil
+	})
+
+	group.Go(func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lint, err := h.eslintTool.LintCheck(types.LintCheckParams{FilePath: filePath, Context: ctx})
+		if err != nil {
+			recordError("lint", err)
+			return nil
+		}
+		result.Lint = lint
+		return nil
+	})
+
+	group.Go(func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		improvements, err := h.analyzer.SuggestImprovements(types.SuggestImprovementsParams{
+			CodeSnippet: string(content),
+			Language:    paths.LanguageFor(filePath),
+		})
+		if err != nil {
+			recordError("improvements", err)
+			return nil
+		}
+		result.Improvements = improvements
+		return nil
+	})
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	if len(stepErrors) > 0 {
+		result.StepErrors = stepErrors
+	}
+	result.Score = reviewScore(result)
+	result.Summary = reviewSummary(result)
+
+	return result, nil
+}
+
+// reviewFiles reviews files on a bounded pool of workers and aggregates the per-file
+// results, sorted by path so the output doesn't depend on scheduling. A file that can't
+// be read is reported in StepErrors under its path. Duplicate paths are reviewed once.
+func (h *Handlers) reviewFiles(ctx context.Context, files []string, concurrency int, progress types.ProgressFunc) (*types.ReviewResult, error) {
+	files = slices.Compact(slices.Sorted(slices.Values(files)))
+
+	workers := runtime.NumCPU()
+	if concurrency > 0 && concurrency < workers {
+		workers = concurrency
+	}
+
+	reviews := make([]*types.ReviewResult, len(files))
+	failures := make([]error, len(files))
+
+	var mu sync.Mutex
+	done := 0
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(workers)
+	for i, file := range files {
+		group.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			reviews[i], failures[i] = h.review(ctx, file)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if progress != nil {
+				mu.Lock()
+				done++
+				progress(done, len(files))
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	result := &types.ReviewResult{Totals: &types.ReviewTotals{}}
+	stepErrors := make(map[string]string)
+	scoreSum := 0
+	for i, review := range reviews {
+		if failures[i] != nil {
+			stepErrors[files[i]] = failures[i].Error()
+			continue
+		}
+		result.Files = append(result.Files, *review)
+		scoreSum += review.Score
+		addReviewTotals(result.Totals, review)
+	}
+
+	if len(stepErrors) > 0 {
+		result.StepErrors = stepErrors
+	}
+	if len(result.Files) > 0 {
+		result.Score = int(math.Round(float64(scoreSum) / float64(len(result.Files))))
+	}
+	result.Summary = reviewFilesSummary(result)
+
+	return result, nil
+}
+
+// addReviewTotals adds the findings of one file's review to totals
+func addReviewTotals(totals *types.ReviewTotals, review *types.ReviewResult) {
+	totals.Files++
+	if review.TypeCheck != nil {
+		totals.Errors += review.TypeCheck.ErrorCount
+		totals.Warnings += review.TypeCheck.WarningCount
+	}
+	if review.Lint != nil {
+		totals.Errors += review.Lint.ErrorCount
+		totals.Warnings += review.Lint.WarningCount
+	}
+	if review.Improvements != nil {
+		totals.Improvements += len(review.Improvements.Improvements)
+	}
+}
+
+// reviewScore starts from 100 and deducts points for every error and lint issue, and
+// the points the analyzer deducted from the improvements' score. Issues are counted
+// from the totals, since the lists are cut down to MaxIssues.
+func reviewScore(result *types.ReviewResult) int {
+	score := 100
+
+	if result.TypeCheck != nil {
+		score -= result.TypeCheck.ErrorCount * typeErrorPenalty
+	}
+
+	if result.Lint != nil {
+		score -= result.Lint.ErrorCount*lintErrorPenalty + result.Lint.WarningCount*lintWarningPenalty
+	}
+
+	if result.Improvements != nil {
+		score -= 100 - result.Improvements.Score
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// reviewSummary describes the findings of each review step in one line
+func reviewSummary(result *types.ReviewResult) string {
+	typeErrors, lintIssues, improvements := "type check skipped", "lint skipped", "analysis skipped"
+
+	if result.TypeCheck != nil {
+		typeErrors = fmt.Sprintf("%d type errors", result.TypeCheck.ErrorCount)
+	}
+	if result.Lint != nil {
+		lintIssues = fmt.Sprintf("%d lint issues", result.Lint.TotalIssues)
+	}
+	if result.Improvements != nil {
+		improvements = fmt.Sprintf("%d suggested improvements", len(result.Improvements.Improvements))
+	}
+
+	return fmt.Sprintf("Score %d/100: %s, %s, %s", result.Score, typeErrors, lintIssues, improvements)
+}
+
+// reviewFilesSummary describes a multi-file review in one line
+func reviewFilesSummary(result *types.ReviewResult) string {
+	totals := result.Totals
+	summary := fmt.Sprintf("Average score %d/100 across %d files: %d errors, %d warnings, %d suggested improvements",
+		result.Score, totals.Files, totals.Errors, totals.Warnings, totals.Improvements)
+	if failed := len(result.StepErrors); failed > 0 {
+		summary += fmt.Sprintf(" (%d files could not be reviewed)", failed)
+	}
+	return summary
+}
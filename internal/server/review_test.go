@@ -0,0 +1,47 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+func TestReviewScoreCountsTruncatedIssues(t *testing.T) {
+	// 300 type errors and 250 lint errors, of which only the first entries were kept
+	result := &types.ReviewResult{
+		TypeCheck: &types.TypeCheckResult{
+			Errors:     make([]types.TypeScriptError, types.DefaultMaxIssues),
+			Truncated:  true,
+			Total:      300,
+			ErrorCount: 300,
+		},
+		Lint: &types.LintResult{
+			Issues:       []types.LintIssue{{Severity: "warning"}},
+			Truncated:    true,
+			TotalIssues:  251,
+			ErrorCount:   250,
+			WarningCount: 1,
+		},
+	}
+	if score := reviewScore(result); score != 0 {
+		t.Errorf("reviewScore = %d, want 0", score)
+	}
+
+	result.Score = reviewScore(result)
+	summary := reviewSummary(result)
+	if !strings.Contains(summary, "300 type errors") || !strings.Contains(summary, "251 lint issues") {
+		t.Errorf("reviewSummary = %q, want the full counts", summary)
+	}
+}
+
+func TestReviewScoreDeductsPerIssue(t *testing.T) {
+	result := &types.ReviewResult{
+		TypeCheck: &types.TypeCheckResult{ErrorCount: 1, WarningCount: 3},
+		Lint:      &types.LintResult{ErrorCount: 1, WarningCount: 2},
+	}
+	want := 100 - typeErrorPenalty - lintErrorPenalty - 2*lintWarningPenalty
+	if score := reviewScore(result); score != want {
+		t.Errorf("reviewScore = %d, want %d", score, want)
+	}
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mcp-typescript-assistant/internal/paths"
+)
+
+// pathArguments are the tool arguments, at any depth, that name files or directories
+var pathArguments = map[string]bool{
+	"file_path":      true,
+	"file_paths":     true,
+	"project_root":   true,
+	"guideline_path": true,
+	"config_path":    true,
+	"ignore_path":    true,
+	"out_dir":        true,
+}
+
+// SetAllowedRoots restricts every path a tool call names to the given directories.
+// Roots are resolved once, so they must exist; no roots lift the restriction.
+func (h *Handlers) SetAllowedRoots(roots []string) error {
+	resolved := make([]string, 0, len(roots))
+	for _, root := range roots {
+		path, err := paths.Resolve(root)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", root, err)
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", root)
+		}
+		resolved = append(resolved, path)
+	}
+	h.allowedRoots = resolved
+	return nil
+}
+
+// validateWithinRoots returns an error unless path, with ".." segments and symlinks
+// resolved, lies inside one of the allowed roots
+func (h *Handlers) validateWithinRoots(path string) error {
+	if len(h.allowedRoots) == 0 || path == "" {
+		return nil
+	}
+
+	resolved, err := paths.Resolve(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	for _, root := range h.allowedRoots {
+		if paths.Within(root, resolved) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is outside the allowed roots (%s)", path, strings.Join(h.allowedRoots, ", "))
+}
+
+// validateArgumentPaths checks every path argument of a tool call with
+// validateWithinRoots. Arguments are inspected as JSON so that new tools and nested
+// parameters are covered without handler changes. Tools resolve relative paths against
+// the project root they run in, or the server's working directory, so a relative path
+// must stay within the roots from both.
+func (h *Handlers) validateArgumentPaths(args any) error {
+	if len(h.allowedRoots) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to inspect arguments: %w", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("failed to inspect arguments: %w", err)
+	}
+
+	// The working directory is the empty base
+	bases := []string{""}
+	projectRoot := h.config.ProjectRoot
+	if fields, ok := decoded.(map[string]any); ok {
+		if err := validateProcessOptions(fields); err != nil {
+			return err
+		}
+		if root, ok := fields["project_root"].(string); ok && root != "" {
+			projectRoot = root
+		}
+	}
+	if projectRoot != "" {
+		bases = append(bases, projectRoot)
+	}
+	return h.validateValuePaths(decoded, false, bases)
+}
+
+// validateValuePaths walks a decoded JSON value, validating the strings found under
+// path arguments against each base directory
+func (h *Handlers) validateValuePaths(value any, isPath bool, bases []string) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			fieldBases := bases
+			if key == "project_root" {
+				// The project root itself is relative to the working directory
+				fieldBases = bases[:1]
+			}
+			if err := h.validateValuePaths(field, pathArguments[key], fieldBases); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := h.validateValuePaths(item, isPath, bases); err != nil {
+				return err
+			}
+		}
+	case string:
+		if !isPath {
+			return nil
+		}
+		if filepath.IsAbs(v) {
+			return h.validateWithinRoots(v)
+		}
+		for _, base := range bases {
+			if err := h.validateWithinRoots(filepath.Join(base, v)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateProcessOptions rejects per-call node_options and env that could run code
+// outside the allowed roots, such as --require or an overridden PATH. Only heap size
+// options are let through, for projects whose check runs out of memory.
+func validateProcessOptions(fields map[string]any) error {
+	if env, ok := fields["env"].(map[string]any); ok && len(env) > 0 {
+		return fmt.Errorf("env is not allowed when allowed roots are configured")
+	}
+	nodeOptions, _ := fields["node_options"].(string)
+	for _, option := range strings.Fields(nodeOptions) {
+		if !isHeapSizeOption(option) {
+			return fmt.Errorf("node option %s is not allowed when allowed roots are configured; only --max-old-space-size is", option)
+		}
+	}
+	return nil
+}
+
+// isHeapSizeOption reports whether option is --max-old-space-size=N
+func isHeapSizeOption(option string) bool {
+	size, ok := strings.CutPrefix(option, "--max-old-space-size=")
+	if !ok || size == "" {
+		return false
+	}
+	for _, r := range size {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+
+	"mcp-typescript-assistant/internal/config"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+func TestValidateArgumentPathsProcessOptions(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "index.ts")
+
+	tests := []struct {
+		name    string
+		params  types.TypeCheckParams
+		wantErr bool
+	}{
+		{"no options", types.TypeCheckParams{FilePath: file}, false},
+		{"heap size", types.TypeCheckParams{FilePath: file, NodeOptions: "--max-old-space-size=8192"}, false},
+		{"require", types.TypeCheckParams{FilePath: file, NodeOptions: "--require /tmp/evil.js"}, true},
+		{"heap size and import", types.TypeCheckParams{FilePath: file, NodeOptions: "--max-old-space-size=8192 --import=/tmp/evil.mjs"}, true},
+		{"bad heap size", types.TypeCheckParams{FilePath: file, NodeOptions: "--max-old-space-size=$(id)"}, true},
+		{"env", types.TypeCheckParams{FilePath: file, Env: map[string]string{"PATH": "/tmp"}}, true},
+	}
+
+	restricted := NewHandlers(&config.Config{})
+	if err := restricted.SetAllowedRoots([]string{root}); err != nil {
+		t.Fatal(err)
+	}
+	open := NewHandlers(&config.Config{})
+	for _, tt := range tests {
+		if err := restricted.validateArgumentPaths(tt.params); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateArgumentPaths = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if err := open.validateArgumentPaths(tt.params); err != nil {
+			t.Errorf("%s: validateArgumentPaths without roots = %v, want no error", tt.name, err)
+		}
+	}
+}
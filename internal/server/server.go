@@ -2,9 +2,12 @@ package server
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"mcp-typescript-assistant/internal/config"
+	"mcp-typescript-assistant/internal/tools"
 )
 
 // TypeScriptMCPServer represents the main MCP server for TypeScript tools
@@ -13,9 +16,24 @@ type TypeScriptMCPServer struct {
 	handlers *Handlers
 }
 
-// NewTypeScriptMCPServer creates a new TypeScript MCP server
-func NewTypeScriptMCPServer() *TypeScriptMCPServer {
-	handlers := NewHandlers()
+// NewTypeScriptMCPServer creates a new TypeScript MCP server, loading its configuration
+// from the config file and environment
+func NewTypeScriptMCPServer() (*TypeScriptMCPServer, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	handlers := NewHandlers(cfg)
+	if err := handlers.analyzer.SetCheckDefaults(cfg.EnabledChecks, cfg.DisabledChecks); err != nil {
+		return nil, fmt.Errorf("invalid analyzer checks in configuration: %w", err)
+	}
+	handlers.analyzer.SetComplexityThreshold(cfg.ComplexityThreshold)
+	if err := handlers.analyzer.SetNamingRules(cfg.NamingRules); err != nil {
+		return nil, fmt.Errorf("invalid naming rules in configuration: %w", err)
+	}
+	if err := handlers.SetAllowedRoots(cfg.AllowedRoots); err != nil {
+		return nil, fmt.Errorf("invalid allowed roots in configuration: %w", err)
+	}
 	
 	server := mcp.NewServer("typescript-analyzer", "1.0.0", nil)
 
@@ -25,35 +43,54 @@ func NewTypeScriptMCPServer() *TypeScriptMCPServer {
 	}
 
 	mcpServer.registerTools()
-	return mcpServer
+	return mcpServer, nil
+}
+
+// Config returns the configuration the server was created with
+func (s *TypeScriptMCPServer) Config() *config.Config {
+	return s.handlers.config
 }
 
 // registerTools registers all the TypeScript tools with the MCP server
 func (s *TypeScriptMCPServer) registerTools() {
 	// Create tools using NewServerTool
-	typeCheckTool := mcp.NewServerTool("type-check", "Run TypeScript type checking on files or projects", s.handlers.TypeCheckHandler)
-	getTypesTool := mcp.NewServerTool("get-types", "Extract type information for symbols in TypeScript files", s.handlers.GetTypesHandler)
-	lintCheckTool := mcp.NewServerTool("lint-check", "Run ESLint checking on TypeScript files", s.handlers.LintCheckHandler)
-	suggestImprovementsTool := mcp.NewServerTool("suggest-improvements", "Analyze TypeScript code and suggest improvements following best practices", s.handlers.SuggestImprovementsHandler)
-	loadGuidelinesTool := mcp.NewServerTool("load-guidelines", "Load custom coding guidelines from markdown files", s.handlers.LoadGuidelinesHandler)
+	typeCheckTool := mcp.NewServerTool("type-check", "Run TypeScript type checking on files or projects", instrument(s.handlers, "type-check", s.handlers.TypeCheckHandler))
+	getTypesTool := mcp.NewServerTool("get-types", "Extract type information for a symbol or a line/column position in a TypeScript file", instrument(s.handlers, "get-types", s.handlers.GetTypesHandler))
+	lintCheckTool := mcp.NewServerTool("lint-check", "Run ESLint checking on TypeScript files", instrument(s.handlers, "lint-check", s.handlers.LintCheckHandler))
+	suggestImprovementsTool := mcp.NewServerTool("suggest-improvements", "Analyze TypeScript code and suggest improvements following best practices", instrument(s.handlers, "suggest-improvements", s.handlers.SuggestImprovementsHandler))
+	loadGuidelinesTool := mcp.NewServerTool("load-guidelines", "Load custom coding guidelines from markdown files", instrument(s.handlers, "load-guidelines", s.handlers.LoadGuidelinesHandler))
+	estimateSizeTool := mcp.NewServerTool("estimate-size", "Estimate the byte, line and token size of a TypeScript file or snippet", instrument(s.handlers, "estimate-size", s.handlers.EstimateSizeHandler))
+	configDumpTool := mcp.NewServerTool("config-dump", "Show the effective server configuration with secrets redacted", instrument(s.handlers, "config-dump", s.handlers.ConfigDumpHandler))
+	serverInfoTool := mcp.NewServerTool("server-info", "Report server capabilities, tool availability and versions", instrument(s.handlers, "server-info", s.handlers.GetServerInfoHandler))
+	transpileTool := mcp.NewServerTool("transpile", "Compile TypeScript to JavaScript and return the emitted files", instrument(s.handlers, "transpile", s.handlers.TranspileHandler))
+	validateGuidelinesTool := mcp.NewServerTool("validate-guidelines", "Parse and validate a guideline file without loading it", instrument(s.handlers, "validate-guidelines", s.handlers.ValidateGuidelinesHandler))
+	reviewTool := mcp.NewServerTool("review", "Type-check, lint and suggest improvements for a file, or several files aggregated, in one call", instrument(s.handlers, "review", s.handlers.ReviewHandler))
+	runTestsTool := mcp.NewServerTool("run-tests", "Run the project's jest or vitest tests and report failures", instrument(s.handlers, "run-tests", s.handlers.RunTestsHandler))
+	checkDependenciesTool := mcp.NewServerTool("check-dependencies", "List outdated npm dependencies and, optionally, known vulnerabilities", instrument(s.handlers, "check-dependencies", s.handlers.CheckDependenciesHandler))
+	importGraphTool := mcp.NewServerTool("import-graph", "Map module imports across a project and detect circular dependencies", instrument(s.handlers, "import-graph", s.handlers.ImportGraphHandler))
+	applyImprovementTool := mcp.NewServerTool("apply-improvement", "Apply a suggested before/after replacement to a file", instrument(s.handlers, "apply-improvement", s.handlers.ApplyImprovementHandler))
+	findReferencesTool := mcp.NewServerTool("find-references", "Find every reference to a symbol or the identifier at a line/column position", instrument(s.handlers, "find-references", s.handlers.FindReferencesHandler))
+	goToDefinitionTool := mcp.NewServerTool("go-to-definition", "Find where a symbol or the identifier at a line/column position is defined", instrument(s.handlers, "go-to-definition", s.handlers.GoToDefinitionHandler))
+	renameSymbolTool := mcp.NewServerTool("rename-symbol", "Compute, and optionally apply, the edits that rename a symbol across the project", instrument(s.handlers, "rename-symbol", s.handlers.RenameSymbolHandler))
+	completeTool := mcp.NewServerTool("complete", "List code completions at a line/column position in a TypeScript file or snippet", instrument(s.handlers, "complete", s.handlers.CompleteHandler))
+	initTSConfigTool := mcp.NewServerTool("init-tsconfig", "Inspect a project and write a recommended strict tsconfig.json", instrument(s.handlers, "init-tsconfig", s.handlers.InitTSConfigHandler))
+	auditTSConfigTool := mcp.NewServerTool("audit-tsconfig", "Report which tsconfig strictness flags are off and recommend changes by priority", instrument(s.handlers, "audit-tsconfig", s.handlers.AuditTSConfigHandler))
+	typeCheckDiffTool := mcp.NewServerTool("type-check-diff", "Type check and report only errors missing from a baseline error list or git ref", instrument(s.handlers, "type-check-diff", s.handlers.TypeCheckDiffHandler))
+	getTypesBatchTool := mcp.NewServerTool("get-types-batch", "Extract type information for several symbols in one file with a single language service run", instrument(s.handlers, "get-types-batch", s.handlers.GetTypesBatchHandler))
+	resolveModuleTool := mcp.NewServerTool("resolve-module", "Resolve an import specifier to its module path, type declarations and @types package", instrument(s.handlers, "resolve-module", s.handlers.ResolveModuleHandler))
+	listESLintRulesTool := mcp.NewServerTool("list-eslint-rules", "List every ESLint rule configured for a file and its severity, grouped by plugin", instrument(s.handlers, "list-eslint-rules", s.handlers.ListESLintRulesHandler))
+	statsTool := mcp.NewServerTool("stats", "Report tool call counts, failures, latency, issues found and cache hit rate since startup", instrument(s.handlers, "stats", s.handlers.StatsHandler))
+	doctorTool := mcp.NewServerTool("doctor", "Diagnose the environment: Node, tsc, ESLint, tsconfig and scratch directories", instrument(s.handlers, "doctor", s.handlers.DoctorHandler))
 
 	// Add tools to server
-	s.server.AddTools(typeCheckTool, getTypesTool, lintCheckTool, suggestImprovementsTool, loadGuidelinesTool)
+	s.server.AddTools(typeCheckTool, getTypesTool, lintCheckTool, suggestImprovementsTool, loadGuidelinesTool, estimateSizeTool, configDumpTool, serverInfoTool, transpileTool, validateGuidelinesTool, reviewTool, runTestsTool, checkDependenciesTool, importGraphTool, applyImprovementTool, doctorTool, findReferencesTool, goToDefinitionTool, renameSymbolTool, completeTool, initTSConfigTool, auditTSConfigTool, typeCheckDiffTool, getTypesBatchTool, resolveModuleTool, listESLintRulesTool, statsTool)
 
-	log.Println("Registered TypeScript MCP tools:")
-	log.Println("- type-check: TypeScript type checking")
-	log.Println("- get-types: Type information extraction")
-	log.Println("- lint-check: ESLint checking")
-	log.Println("- suggest-improvements: Code improvement suggestions")
-	log.Println("- load-guidelines: Custom guideline loading")
+	slog.Debug("Registered TypeScript MCP tools", "tools", toolNames)
 }
 
 // Run starts the MCP server with stdio transport
 func (s *TypeScriptMCPServer) Run(ctx context.Context) error {
-	log.Println("Starting TypeScript MCP Server...")
-	log.Println("Server name: typescript-analyzer")
-	log.Println("Version: 1.0.0")
-	log.Println("Transport: stdio")
+	slog.Info("Starting TypeScript MCP Server", "name", "typescript-analyzer", "version", "1.0.0", "transport", "stdio")
 	
 	// Check tool availability and log status
 	s.logToolStatus()
@@ -63,33 +100,39 @@ func (s *TypeScriptMCPServer) Run(ctx context.Context) error {
 
 // logToolStatus logs the availability status of external tools
 func (s *TypeScriptMCPServer) logToolStatus() {
-	log.Println("Checking external tool availability...")
+	slog.Debug("Checking external tool availability")
+	
+	if version, err := tools.GetNodeVersion(); err != nil {
+		slog.Warn("Node.js not available; tsc and eslint need it to run", "error", err)
+	} else if tools.NodeBelowMinimum(version, s.handlers.config.MinNodeVersion) {
+		slog.Warn("Node.js is older than the supported minimum; newer TypeScript and ESLint releases may crash", "version", version, "minimum", s.handlers.config.MinNodeVersion)
+	} else {
+		slog.Info("Node.js available", "version", version)
+	}
 	
 	if err := s.handlers.tscTool.CheckTSCAvailable(); err != nil {
-		log.Printf("Warning: TypeScript compiler not available: %v", err)
-		log.Println("  Make sure 'tsc' is installed (npm install -g typescript)")
+		slog.Warn("TypeScript compiler not available; make sure 'tsc' is installed (npm install -g typescript)", "error", err)
 	} else {
 		if version, err := s.handlers.tscTool.GetVersion(); err == nil {
-			log.Printf("TypeScript compiler available: %s", version)
+			slog.Info("TypeScript compiler available", "version", version)
 		} else {
-			log.Println("TypeScript compiler available")
+			slog.Info("TypeScript compiler available")
 		}
 	}
 	
 	if err := s.handlers.eslintTool.CheckESLintAvailable(); err != nil {
-		log.Printf("Warning: ESLint not available: %v", err)
-		log.Println("  Make sure 'eslint' is installed (npm install -g eslint)")
+		slog.Warn("ESLint not available; make sure 'eslint' is installed (npm install -g eslint)", "error", err)
 	} else {
 		if version, err := s.handlers.eslintTool.GetVersion(); err == nil {
-			log.Printf("ESLint available: %s", version)
+			slog.Info("ESLint available", "version", version)
 		} else {
-			log.Println("ESLint available")
+			slog.Info("ESLint available")
 		}
 	}
 }
 
 // Shutdown gracefully shuts down the server
 func (s *TypeScriptMCPServer) Shutdown(ctx context.Context) error {
-	log.Println("Shutting down TypeScript MCP Server...")
+	slog.Info("Shutting down TypeScript MCP Server")
 	return nil
 }
\ No newline at end of file
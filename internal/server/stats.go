@@ -0,0 +1,123 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// toolStats counts tool calls, their latency and outcome, the issues they found and the
+// incremental cache hits of type checks, for the stats tool. It is safe for concurrent
+// use, and a nil *toolStats records nothing.
+type toolStats struct {
+	mu          sync.Mutex
+	since       time.Time
+	tools       map[string]*toolCounters
+	cacheHits   int
+	cacheMisses int
+}
+
+// toolCounters are the counters of one tool
+type toolCounters struct {
+	calls    int
+	failures int
+	issues   int
+	duration time.Duration
+}
+
+// newToolStats creates empty counters
+func newToolStats() *toolStats {
+	return &toolStats{since: time.Now(), tools: make(map[string]*toolCounters)}
+}
+
+// counters returns the counters of tool, creating them; the caller must hold s.mu
+func (s *toolStats) counters(tool string) *toolCounters {
+	counters, ok := s.tools[tool]
+	if !ok {
+		counters = &toolCounters{}
+		s.tools[tool] = counters
+	}
+	return counters
+}
+
+// recordCall counts a call to tool that took duration and failed or returned an error result
+func (s *toolStats) recordCall(tool string, duration time.Duration, failed bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counters := s.counters(tool)
+	counters.calls++
+	counters.duration += duration
+	if failed {
+		counters.failures++
+	}
+}
+
+// recordIssues counts the diagnostics or improvements a call to tool found
+func (s *toolStats) recordIssues(tool string, issues int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters(tool).issues += issues
+}
+
+// recordCache counts an incremental type check that did or didn't find a previous build
+func (s *toolStats) recordCache(hit bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
+
+// snapshot returns the counters, sorted by tool name, then clears them if reset is set
+func (s *toolStats) snapshot(reset bool) *types.StatsResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &types.StatsResult{
+		Since:       s.since.UTC().Format(time.RFC3339),
+		Tools:       []types.ToolStats{},
+		CacheHits:   s.cacheHits,
+		CacheMisses: s.cacheMisses,
+	}
+	for name, counters := range s.tools {
+		tool := types.ToolStats{
+			Name:        name,
+			Calls:       counters.calls,
+			Failures:    counters.failures,
+			IssuesFound: counters.issues,
+		}
+		if counters.calls > 0 {
+			tool.AverageLatency = (counters.duration / time.Duration(counters.calls)).Round(time.Millisecond).String()
+		}
+		result.Tools = append(result.Tools, tool)
+		result.TotalCalls += counters.calls
+		result.TotalFailures += counters.failures
+		result.TotalIssues += counters.issues
+	}
+	sort.Slice(result.Tools, func(i, j int) bool { return result.Tools[i].Name < result.Tools[j].Name })
+	if lookups := s.cacheHits + s.cacheMisses; lookups > 0 {
+		result.CacheHitRate = float64(s.cacheHits) / float64(lookups)
+	}
+
+	if reset {
+		s.since = time.Now()
+		s.tools = make(map[string]*toolCounters)
+		s.cacheHits, s.cacheMisses = 0, 0
+		result.Reset = true
+	}
+	return result
+}
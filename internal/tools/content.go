@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultContentName is the logical file name used for inline content without a file_path
+const defaultContentName = "input.ts"
+
+// decodeFileContent decodes inline file content sent as "base64" or as raw text
+// (an empty encoding or "utf8")
+func decodeFileContent(content, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "", "utf8", "utf-8", "raw":
+		return []byte(content), nil
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 file content: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q: use \"base64\" or \"utf8\"", encoding)
+	}
+}
+
+// checkFileInput validates that a request names a file on disk or sends its content
+func checkFileInput(filePath, fileContent string) error {
+	if filePath == "" && fileContent == "" {
+		return fmt.Errorf("either file_path or file_content is required")
+	}
+	return nil
+}
+
+// checkFilePath validates that path names a readable file before it is handed to an
+// external tool, whose own error for a missing file is often cryptic. Directories are
+// rejected unless allowDir is set. Errors report the absolute path.
+func checkFilePath(path string, allowDir bool) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("file not found: %s", absPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access %s: %w", absPath, err)
+	}
+	if info.IsDir() {
+		if allowDir {
+			return nil
+		}
+		return fmt.Errorf("%s is a directory, not a file", absPath)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("file is not readable: %s: %w", absPath, err)
+	}
+	return file.Close()
+}
+
+// contentName returns the logical name reported for inline content
+func contentName(filePath string) string {
+	if filePath == "" {
+		return defaultContentName
+	}
+	return filePath
+}
+
+// writeFileContent writes inline content to a scratch file under the base name of
+// logicalPath, so tools see the right extension. The file is removed by the returned
+// cleanup function.
+func writeFileContent(logicalPath, content, encoding string) (string, func(), error) {
+	data, err := decodeFileContent(content, encoding)
+	if err != nil {
+		return "", nil, err
+	}
+	return newScratchNamedFile(contentName(logicalPath), data)
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"os/exec"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// CheckNodeAvailable checks if Node.js is available
+func CheckNodeAvailable() error {
+	_, err := GetNodeVersion()
+	return err
+}
+
+// GetNodeVersion returns the version reported by `node --version`, e.g. "v20.11.1"
+func GetNodeVersion() (string, error) {
+	output, err := exec.Command("node", "--version").Output()
+	if err != nil {
+		return "", nodeUnavailable(err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// NodeBelowMinimum reports whether a `node --version` string is older than the minimum
+// major version. Unparseable versions are not reported as too old.
+func NodeBelowMinimum(version string, minimum int) bool {
+	major := parseMajorVersion(version)
+	return major > 0 && major < minimum
+}
+
+// nodeUnavailable wraps err in a ToolUnavailableError for Node.js
+func nodeUnavailable(err error) *types.ToolUnavailableError {
+	return &types.ToolUnavailableError{
+		Tool:    "node",
+		Install: "install Node.js from https://nodejs.org or with a version manager such as nvm",
+		Err:     err,
+	}
+}
@@ -1,26 +1,49 @@
 package tools
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"mcp-typescript-assistant/pkg/types"
 )
 
 // ESLintTool provides ESLint integration for TypeScript files
 type ESLintTool struct {
-	eslintPath string
+	runner runner
+
+	// versions caches the ESLint major version per resolved binary
+	mu       sync.Mutex
+	versions map[string]int
+
+	// configs caches GetConfig results per binary, directory and file extension
+	configMu sync.Mutex
+	configs  map[string]cachedConfig
+}
+
+// cachedConfig is a resolved ESLint config and the state of the config files it was
+// resolved from
+type cachedConfig struct {
+	signature string
+	config    map[string]interface{}
 }
 
 // NewESLintTool creates a new ESLint tool instance
-func NewESLintTool() *ESLintTool {
-	eslintPath := "eslint"
-	if path, err := exec.LookPath("npx"); err == nil {
-		eslintPath = path
+func NewESLintTool(opts ...Option) *ESLintTool {
+	return &ESLintTool{
+		runner:   newRunner("eslint", opts...),
+		versions: make(map[string]int),
+		configs:  make(map[string]cachedConfig),
 	}
-	return &ESLintTool{eslintPath: eslintPath}
 }
 
 // ESLintOutput represents the JSON output from ESLint
@@ -52,13 +75,80 @@ type Fix struct {
 
 // LintCheck performs ESLint checking on a TypeScript file
 func (eslint *ESLintTool) LintCheck(params types.LintCheckParams) (*types.LintResult, error) {
-	var args []string
+	if err := validateFailOn(params.FailOn); err != nil {
+		return nil, err
+	}
+
+	result, err := eslint.lintCheck(params)
+	if err != nil {
+		return nil, err
+	}
+	result.WarningsExceeded = exceedsMaxWarnings(params.MaxWarnings, result.WarningCount)
+	result.Passed = passesFailOn(params.FailOn, result.ErrorCount, result.WarningCount) && !result.WarningsExceeded
+	if result.WarningsExceeded {
+		result.Summary += fmt.Sprintf(" (more than the maximum of %d warnings)", *params.MaxWarnings)
+	}
+	if params.QuietOnly {
+		result.Summary += " (warnings suppressed by quiet_only)"
+	}
+	return result, nil
+}
+
+// lintCheck runs ESLint on a file, several files or the changed files of a project
+func (eslint *ESLintTool) lintCheck(params types.LintCheckParams) (*types.LintResult, error) {
+	if params.ChangedOnly {
+		return eslint.lintChanged(params)
+	}
+	if len(params.FilePaths) > 0 {
+		return eslint.lintFiles(params)
+	}
+	if err := checkFileInput(params.FilePath, params.FileContent); err != nil {
+		return nil, err
+	}
+	if params.FileContent == "" {
+		// ESLint lints every matching file in a directory
+		if err := checkFilePath(params.FilePath, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.ConfigPath != "" {
+		if _, err := os.Stat(params.ConfigPath); err != nil {
+			return nil, fmt.Errorf("ESLint config file not found: %s: %w", params.ConfigPath, err)
+		}
+	}
+
+	dir := filepath.Dir(params.FilePath)
+	flatConfig := eslint.usesFlatConfig(dir)
 
-	if eslint.eslintPath == "npx" {
-		args = append(args, "eslint")
+	args := []string{"--format", "json"}
+
+	if params.ConfigPath != "" {
+		args = append(args, "--config", params.ConfigPath)
+	}
+	if params.NoEslintrc {
+		if flatConfig {
+			args = append(args, "--no-config-lookup")
+		} else {
+			args = append(args, "--no-eslintrc")
+		}
+	}
+	if params.IgnorePath != "" {
+		ignoreArgs, err := ignorePathArgs(params.IgnorePath, flatConfig)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ignoreArgs...)
 	}
 
-	args = append(args, "--format", "json")
+	if params.QuietOnly {
+		args = append(args, "--quiet")
+	}
+	if params.MaxWarnings != nil && *params.MaxWarnings >= 0 {
+		// ESLint then exits non-zero with too many warnings, which only affects Success;
+		// the JSON report on stdout is parsed as usual
+		args = append(args, "--max-warnings", strconv.Itoa(*params.MaxWarnings))
+	}
 
 	if len(params.Rules) > 0 {
 		// Add specific rules
@@ -67,22 +157,78 @@ func (eslint *ESLintTool) LintCheck(params types.LintCheckParams) (*types.LintRe
 		}
 	}
 
-	args = append(args, params.FilePath)
+	var stdin []byte
+	if params.FileContent != "" {
+		// Inline content is linted from stdin under its logical name so the project's
+		// ESLint configuration still applies
+		content, err := decodeFileContent(params.FileContent, params.ContentEncoding)
+		if err != nil {
+			return nil, err
+		}
+		stdin = content
+		params.FilePath = contentName(params.FilePath)
+		args = append(args, "--stdin", "--stdin-filename", params.FilePath)
+	} else {
+		args = append(args, params.FilePath)
+	}
 
-	cmd := exec.Command(eslint.eslintPath, args...)
-	output, err := cmd.Output()
+	lintRunner := eslint.runner.withContext(params.Context)
+	output, err := lintRunner.retry(dir, lintRunner.retries, func() ([]byte, error) {
+		cmd := lintRunner.command(dir, args...)
+		if stdin != nil {
+			cmd.Stdin = bytes.NewReader(stdin)
+		}
+		return cmd.Output()
+	})
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, eslintUnavailable(err)
+	}
 
 	// ESLint returns non-zero exit code when there are linting errors
 	// but we still want to parse the output
 	result := &types.LintResult{
-		Success: err == nil,
+		Success:      err == nil,
+		Binary:       lintRunner.describe(filepath.Dir(params.FilePath)),
+		ConfigFormat: "eslintrc",
+	}
+	if flatConfig {
+		result.ConfigFormat = "flat"
 	}
 
 	if len(output) > 0 {
 		issues, fixableCount := eslint.parseESLintOutput(output)
-		result.Issues = issues
+		if params.QuietOnly {
+			// Older ESLint versions still report warnings from some configs with --quiet
+			issues, fixableCount = dropWarnings(issues)
+		}
+		if !params.AbsolutePaths {
+			// ESLint always reports absolute paths
+			for i := range issues {
+				issues[i].File = normalizePath(filepath.Dir(params.FilePath), issues[i].File)
+			}
+		}
+		if params.FileContent != "" {
+			for i := range issues {
+				issues[i].File = params.FilePath
+			}
+		}
+		if params.IncludeRuleDocs {
+			enrichRuleDocs(issues)
+		}
+
+		maxIssues := params.MaxIssues
+		if maxIssues <= 0 {
+			maxIssues = types.DefaultMaxIssues
+		}
+		result.TotalIssues = len(issues)
+		result.ErrorCount, result.WarningCount = countSeverities(issues)
 		result.Fixable = fixableCount
 		result.Summary = eslint.generateSummary(issues, fixableCount)
+		if len(issues) > maxIssues {
+			issues = issues[:maxIssues]
+			result.Truncated = true
+		}
+		result.Issues = issues
 	} else if err != nil {
 		// If there's an error and no output, ESLint might not be configured properly
 		return nil, fmt.Errorf("ESLint execution failed: %w", err)
@@ -91,6 +237,50 @@ func (eslint *ESLintTool) LintCheck(params types.LintCheckParams) (*types.LintRe
 	return result, nil
 }
 
+// lintChanged lints the files under params.ProjectRoot changed since params.BaseRef,
+// reporting paths relative to the project root
+func (eslint *ESLintTool) lintChanged(params types.LintCheckParams) (*types.LintResult, error) {
+	if params.ProjectRoot == "" {
+		return nil, fmt.Errorf("changed_only requires a project_root")
+	}
+	if params.FilePath != "" || len(params.FilePaths) > 0 || params.FileContent != "" {
+		return nil, fmt.Errorf("changed_only can't be combined with file_path, file_paths or file_content")
+	}
+
+	changed, err := ChangedFiles(params.ProjectRoot, params.BaseRef)
+	if err != nil {
+		return nil, err
+	}
+	changedFiles := make([]string, len(changed))
+	for i, file := range changed {
+		changedFiles[i] = normalizePath(params.ProjectRoot, file)
+	}
+	if len(changed) == 0 {
+		return &types.LintResult{
+			Success:      true,
+			Summary:      "No changed TypeScript files to lint",
+			ChangedFiles: []string{},
+		}, nil
+	}
+
+	filesParams := params
+	filesParams.ChangedOnly = false
+	filesParams.FilePaths = changed
+	filesParams.AbsolutePaths = true
+	result, err := eslint.lintFiles(filesParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if !params.AbsolutePaths {
+		for i := range result.Issues {
+			result.Issues[i].File = normalizePath(params.ProjectRoot, result.Issues[i].File)
+		}
+	}
+	result.ChangedFiles = changedFiles
+	return result, nil
+}
+
 // parseESLintOutput parses ESLint JSON output into structured issues
 func (eslint *ESLintTool) parseESLintOutput(output []byte) ([]types.LintIssue, int) {
 	var eslintResults []ESLintOutput
@@ -122,6 +312,12 @@ func (eslint *ESLintTool) parseESLintOutput(output []byte) ([]types.LintIssue, i
 				Severity: severity,
 				Fixable:  fixable,
 			}
+			if fixable {
+				issue.Fix = &types.FixInfo{
+					Range: message.Fix.Range,
+					Text:  message.Fix.Text,
+				}
+			}
 			issues = append(issues, issue)
 		}
 	}
@@ -129,15 +325,26 @@ func (eslint *ESLintTool) parseESLintOutput(output []byte) ([]types.LintIssue, i
 	return issues, fixableCount
 }
 
-// generateSummary creates a summary of linting results
-func (eslint *ESLintTool) generateSummary(issues []types.LintIssue, fixableCount int) string {
-	if len(issues) == 0 {
-		return "No linting issues found"
+// dropWarnings removes the warnings from issues, returning the remaining issues and how
+// many of them are fixable
+func dropWarnings(issues []types.LintIssue) ([]types.LintIssue, int) {
+	var errorIssues []types.LintIssue
+	fixableCount := 0
+	for _, issue := range issues {
+		if issue.Severity != "error" {
+			continue
+		}
+		if issue.Fixable {
+			fixableCount++
+		}
+		errorIssues = append(errorIssues, issue)
 	}
+	return errorIssues, fixableCount
+}
 
-	errorCount := 0
-	warningCount := 0
-
+// countSeverities counts the errors and warnings among issues
+func countSeverities(issues []types.LintIssue) (int, int) {
+	errorCount, warningCount := 0, 0
 	for _, issue := range issues {
 		if issue.Severity == "error" {
 			errorCount++
@@ -145,6 +352,16 @@ func (eslint *ESLintTool) generateSummary(issues []types.LintIssue, fixableCount
 			warningCount++
 		}
 	}
+	return errorCount, warningCount
+}
+
+// generateSummary creates a summary of linting results
+func (eslint *ESLintTool) generateSummary(issues []types.LintIssue, fixableCount int) string {
+	if len(issues) == 0 {
+		return "No linting issues found"
+	}
+
+	errorCount, warningCount := countSeverities(issues)
 
 	summary := fmt.Sprintf("Found %d issue(s): %d error(s), %d warning(s)",
 		len(issues), errorCount, warningCount)
@@ -158,16 +375,12 @@ func (eslint *ESLintTool) generateSummary(issues []types.LintIssue, fixableCount
 
 // AutoFix attempts to automatically fix ESLint issues
 func (eslint *ESLintTool) AutoFix(filePath string) (*types.LintResult, error) {
-	var args []string
-
-	if eslint.eslintPath == "npx" {
-		args = append(args, "eslint")
-	}
+	args := []string{"--fix", "--format", "json", filePath}
 
-	args = append(args, "--fix", "--format", "json", filePath)
-
-	cmd := exec.Command(eslint.eslintPath, args...)
-	output, err := cmd.Output()
+	dir := filepath.Dir(filePath)
+	output, err := eslint.runner.retry(dir, eslint.runner.retries, func() ([]byte, error) {
+		return eslint.runner.command(dir, args...).Output()
+	})
 
 	result := &types.LintResult{
 		Success: err == nil,
@@ -183,32 +396,32 @@ func (eslint *ESLintTool) AutoFix(filePath string) (*types.LintResult, error) {
 	return result, nil
 }
 
+// Path returns the command used to invoke ESLint from the default project root
+func (eslint *ESLintTool) Path() string {
+	return eslint.runner.describe("")
+}
+
 // CheckESLintAvailable checks if ESLint is available
 func (eslint *ESLintTool) CheckESLintAvailable() error {
-	var cmd *exec.Cmd
-	if eslint.eslintPath == "npx" {
-		cmd = exec.Command("npx", "eslint", "--version")
-	} else {
-		cmd = exec.Command("eslint", "--version")
-	}
-
-	_, err := cmd.Output()
+	_, err := eslint.runner.probe("", "--version")
 	if err != nil {
-		return fmt.Errorf("ESLint not available: %w", err)
+		return eslintUnavailable(err)
 	}
 	return nil
 }
 
-// GetVersion returns the ESLint version
-func (eslint *ESLintTool) GetVersion() (string, error) {
-	var cmd *exec.Cmd
-	if eslint.eslintPath == "npx" {
-		cmd = exec.Command("npx", "eslint", "--version")
-	} else {
-		cmd = exec.Command("eslint", "--version")
+// eslintUnavailable wraps err in a ToolUnavailableError for ESLint
+func eslintUnavailable(err error) *types.ToolUnavailableError {
+	return &types.ToolUnavailableError{
+		Tool:    "eslint",
+		Install: "npm install -g eslint @typescript-eslint/parser @typescript-eslint/eslint-plugin",
+		Err:     err,
 	}
+}
 
-	output, err := cmd.Output()
+// GetVersion returns the ESLint version
+func (eslint *ESLintTool) GetVersion() (string, error) {
+	output, err := eslint.runner.probe("", "--version")
 	if err != nil {
 		return "", err
 	}
@@ -217,18 +430,141 @@ func (eslint *ESLintTool) GetVersion() (string, error) {
 	return version, nil
 }
 
-// GetConfig returns ESLint configuration for a file
-func (eslint *ESLintTool) GetConfig(filePath string) (map[string]interface{}, error) {
+// VersionIn returns the version of the ESLint that lints projects in dir, which may be
+// a project-local install, or a ToolUnavailableError
+func (eslint *ESLintTool) VersionIn(dir string) (string, error) {
+	output, err := eslint.runner.probe(dir, "--version")
+	if err != nil {
+		return "", eslintUnavailable(err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PathIn returns the command used to run ESLint for projects in dir
+func (eslint *ESLintTool) PathIn(dir string) string {
+	return eslint.runner.describe(dir)
+}
+
+// flatConfigFiles are the config files that make ESLint 8 use flat config
+var flatConfigFiles = []string{"eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts", "eslint.config.mts", "eslint.config.cts"}
+
+// usesFlatConfig reports whether the ESLint that runs in dir uses flat config. ESLint 9
+// does by default, ESLint 8 only when an eslint.config.* file is present, and both honor
+// the ESLINT_USE_FLAT_CONFIG environment variable, which the server's env setting can set.
+func (eslint *ESLintTool) usesFlatConfig(dir string) bool {
+	switch eslint.runner.getenv("ESLINT_USE_FLAT_CONFIG") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	major := eslint.majorVersion(dir)
+	if major >= 9 {
+		return true
+	}
+	if major == 8 {
+		return hasFlatConfigFile(dir)
+	}
+	return false
+}
+
+// majorVersion returns the major version of the ESLint that runs in dir, or 0 if unknown
+func (eslint *ESLintTool) majorVersion(dir string) int {
+	key := eslint.runner.describe(dir)
+
+	eslint.mu.Lock()
+	defer eslint.mu.Unlock()
+
+	if major, ok := eslint.versions[key]; ok {
+		return major
+	}
+
+	output, err := eslint.runner.probe(dir, "--version")
+	if err != nil {
+		return 0
+	}
+	major := parseMajorVersion(string(output))
+	eslint.versions[key] = major
+	return major
+}
+
+// versionRegex matches a version such as "v9.4.0"
+var versionRegex = regexp.MustCompile(`v?(\d+)\.\d+`)
+
+// parseMajorVersion extracts the major version from `eslint --version` output
+func parseMajorVersion(version string) int {
+	matches := versionRegex.FindStringSubmatch(version)
+	if len(matches) < 2 {
+		return 0
+	}
+	major, _ := strconv.Atoi(matches[1])
+	return major
+}
+
+// hasFlatConfigFile walks up from dir looking for an eslint.config.* file
+func hasFlatConfigFile(dir string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	for {
+		for _, name := range flatConfigFiles {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// ignorePathArgs returns the flags for an ignore file. Flat config has no --ignore-path,
+// so its patterns are passed individually with --ignore-pattern.
+func ignorePathArgs(ignorePath string, flatConfig bool) ([]string, error) {
+	if !flatConfig {
+		return []string{"--ignore-path", ignorePath}, nil
+	}
+
+	content, err := os.ReadFile(ignorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
 	var args []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args = append(args, "--ignore-pattern", line)
+	}
+	return args, nil
+}
 
-	if eslint.eslintPath == "npx" {
-		args = append(args, "eslint")
+// GetConfig returns ESLint configuration for a file. Configs are cached per directory
+// and file extension until a config file above the directory changes, so the returned
+// map is shared and must not be modified.
+func (eslint *ESLintTool) GetConfig(filePath string) (map[string]interface{}, error) {
+	dir := filepath.Dir(filePath)
+	key := eslint.runner.describe(dir) + "\x00" + dir + "\x00" + filepath.Ext(filePath)
+	signature := configSignature(dir)
+
+	eslint.configMu.Lock()
+	cached, ok := eslint.configs[key]
+	eslint.configMu.Unlock()
+	if ok && cached.signature == signature {
+		return cached.config, nil
 	}
 
-	args = append(args, "--print-config", filePath)
+	args := []string{"--print-config", filePath}
 
-	cmd := exec.Command(eslint.eslintPath, args...)
-	output, err := cmd.Output()
+	output, err := eslint.runner.probe(dir, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ESLint config: %w", err)
 	}
@@ -238,6 +574,54 @@ func (eslint *ESLintTool) GetConfig(filePath string) (map[string]interface{}, er
 		return nil, fmt.Errorf("failed to parse ESLint config: %w", err)
 	}
 
+	eslint.configMu.Lock()
+	eslint.configs[key] = cachedConfig{signature: signature, config: config}
+	eslint.configMu.Unlock()
+
 	return config, nil
 }
 
+// ConfigCacheSize returns how many configs GetConfig has cached
+func (eslint *ESLintTool) ConfigCacheSize() int {
+	eslint.configMu.Lock()
+	defer eslint.configMu.Unlock()
+	return len(eslint.configs)
+}
+
+// ClearConfigCache drops every config cached by GetConfig
+func (eslint *ESLintTool) ClearConfigCache() {
+	eslint.configMu.Lock()
+	defer eslint.configMu.Unlock()
+	clear(eslint.configs)
+}
+
+// legacyConfigFiles are the eslintrc config files, which cascade from the directories
+// above a file; package.json can hold an eslintConfig key
+var legacyConfigFiles = []string{".eslintrc.js", ".eslintrc.cjs", ".eslintrc.yaml", ".eslintrc.yml", ".eslintrc.json", ".eslintrc", "package.json"}
+
+// configSignature describes the path and modification time of every ESLint config file
+// in dir and the directories above it, so that a cached config can tell when any of
+// them was added, removed or edited
+func configSignature(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	var signature strings.Builder
+	for {
+		for _, name := range slices.Concat(flatConfigFiles, legacyConfigFiles) {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil {
+				fmt.Fprintf(&signature, "%s@%d;", path, info.ModTime().UnixNano())
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return signature.String()
+		}
+		dir = parent
+	}
+}
+
@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// ruleDescriptions holds short descriptions of common core and typescript-eslint rules
+var ruleDescriptions = map[string]string{
+	"eqeqeq":                    "Require the use of === and !==",
+	"no-console":                "Disallow the use of console",
+	"no-debugger":               "Disallow the use of debugger",
+	"no-empty":                  "Disallow empty block statements",
+	"no-undef":                  "Disallow the use of undeclared variables",
+	"no-unused-vars":            "Disallow unused variables",
+	"no-var":                    "Require let or const instead of var",
+	"prefer-const":              "Require const declarations for variables that are never reassigned",
+	"no-shadow":                 "Disallow variable declarations from shadowing variables declared in the outer scope",
+	"no-use-before-define":      "Disallow the use of variables before they are defined",
+	"no-duplicate-imports":      "Disallow duplicate module imports",
+	"no-unreachable":            "Disallow unreachable code after return, throw, continue, and break statements",
+	"no-fallthrough":            "Disallow fallthrough of case statements",
+	"no-param-reassign":         "Disallow reassigning function parameters",
+	"no-implicit-coercion":      "Disallow shorthand type conversions",
+	"curly":                     "Enforce consistent brace style for all control statements",
+	"complexity":                "Enforce a maximum cyclomatic complexity allowed in a program",
+	"max-lines-per-function":    "Enforce a maximum number of lines of code in a function",
+	"max-params":                "Enforce a maximum number of parameters in function definitions",
+	"no-magic-numbers":          "Disallow magic numbers",
+	"prefer-template":           "Require template literals instead of string concatenation",
+	"object-shorthand":          "Require or disallow method and property shorthand syntax for object literals",
+	"no-async-promise-executor": "Disallow using an async function as a Promise executor",
+	"require-await":             "Disallow async functions which have no await expression",
+
+	"@typescript-eslint/no-explicit-any":                "Disallow the any type",
+	"@typescript-eslint/no-unused-vars":                 "Disallow unused variables",
+	"@typescript-eslint/explicit-function-return-type":  "Require explicit return types on functions and class methods",
+	"@typescript-eslint/explicit-module-boundary-types": "Require explicit return and argument types on exported functions' and classes' public class methods",
+	"@typescript-eslint/no-non-null-assertion":          "Disallow non-null assertions using the ! postfix operator",
+	"@typescript-eslint/no-floating-promises":           "Require Promise-like statements to be handled appropriately",
+	"@typescript-eslint/no-misused-promises":            "Disallow Promises in places not designed to handle them",
+	"@typescript-eslint/await-thenable":                 "Disallow awaiting a value that is not a Thenable",
+	"@typescript-eslint/consistent-type-imports":        "Enforce consistent usage of type imports",
+	"@typescript-eslint/consistent-type-definitions":    "Enforce type definitions to consistently use either interface or type",
+	"@typescript-eslint/consistent-type-assertions":     "Enforce consistent usage of type assertions",
+	"@typescript-eslint/ban-ts-comment":                 "Disallow @ts-<directive> comments or require descriptions after directives",
+	"@typescript-eslint/no-empty-function":              "Disallow empty functions",
+	"@typescript-eslint/no-empty-interface":             "Disallow the declaration of empty interfaces",
+	"@typescript-eslint/no-inferrable-types":            "Disallow explicit type declarations for variables or parameters initialized to a number, string, or boolean",
+	"@typescript-eslint/no-var-requires":                "Disallow require statements except in import statements",
+	"@typescript-eslint/no-require-imports":             "Disallow invocation of require()",
+	"@typescript-eslint/prefer-nullish-coalescing":      "Enforce using the nullish coalescing operator instead of logical assignments or chaining",
+	"@typescript-eslint/prefer-optional-chain":          "Enforce using concise optional chain expressions instead of chained logical ands, negated logical ors, or empty objects",
+	"@typescript-eslint/prefer-as-const":                "Enforce the use of as const over literal type",
+	"@typescript-eslint/naming-convention":              "Enforce naming conventions for everything across a codebase",
+	"@typescript-eslint/no-shadow":                      "Disallow variable declarations from shadowing variables declared in the outer scope",
+	"@typescript-eslint/no-use-before-define":           "Disallow the use of variables before they are defined",
+	"@typescript-eslint/no-unsafe-assignment":           "Disallow assigning a value with type any to variables and properties",
+	"@typescript-eslint/no-unsafe-member-access":        "Disallow member access on a value with type any",
+	"@typescript-eslint/no-unsafe-call":                 "Disallow calling a value with type any",
+	"@typescript-eslint/no-unsafe-return":               "Disallow returning a value with type any from a function",
+	"@typescript-eslint/no-unsafe-argument":             "Disallow calling a function with a value with type any",
+	"@typescript-eslint/restrict-template-expressions":  "Enforce template literal expressions to be of string type",
+	"@typescript-eslint/switch-exhaustiveness-check":    "Require switch-case statements to be exhaustive",
+	"@typescript-eslint/strict-boolean-expressions":     "Disallow certain types in boolean expressions",
+	"@typescript-eslint/no-unnecessary-type-assertion":  "Disallow type assertions that do not change the type of an expression",
+	"@typescript-eslint/array-type":                     "Require consistently using either T[] or Array<T> for arrays",
+	"@typescript-eslint/prefer-readonly":                "Require private members to be marked as readonly if they're never modified outside of the constructor",
+	"@typescript-eslint/no-namespace":                   "Disallow TypeScript namespaces",
+	"@typescript-eslint/prefer-enum-initializers":       "Require each enum member value to be explicitly initialized",
+	"@typescript-eslint/no-duplicate-enum-values":       "Disallow duplicate enum member values",
+	"@typescript-eslint/return-await":                   "Enforce consistent awaiting of returned promises",
+}
+
+// pluginDocs maps an ESLint plugin prefix to the URL its rule documentation lives under
+var pluginDocs = map[string]string{
+	"@typescript-eslint": "https://typescript-eslint.io/rules/%s",
+	"react":              "https://github.com/jsx-eslint/eslint-plugin-react/blob/master/docs/rules/%s.md",
+	"import":             "https://github.com/import-js/eslint-plugin-import/blob/main/docs/rules/%s.md",
+	"jsx-a11y":           "https://github.com/jsx-eslint/eslint-plugin-jsx-a11y/blob/main/docs/rules/%s.md",
+}
+
+// ruleURL returns the documentation URL for a rule, or an empty string for unknown plugins
+func ruleURL(rule string) string {
+	if rule == "" {
+		return ""
+	}
+
+	slash := strings.LastIndex(rule, "/")
+	if slash < 0 {
+		return "https://eslint.org/docs/latest/rules/" + rule
+	}
+
+	if pattern, ok := pluginDocs[rule[:slash]]; ok {
+		return strings.Replace(pattern, "%s", rule[slash+1:], 1)
+	}
+	return ""
+}
+
+// enrichRuleDocs fills in the documentation URL and description of each issue's rule
+func enrichRuleDocs(issues []types.LintIssue) {
+	for i := range issues {
+		issues[i].RuleURL = ruleURL(issues[i].Rule)
+		issues[i].RuleDescription = ruleDescriptions[issues[i].Rule]
+	}
+}
+
+// ruleSeverities maps the numeric rule severities to their names
+var ruleSeverities = map[float64]string{0: "off", 1: "warn", 2: "error"}
+
+// ListRules reports every rule configured for a file, with its severity and options,
+// grouped by plugin. Core ESLint rules are in the "core" group, which comes first.
+func (eslint *ESLintTool) ListRules(filePath string) (*types.ESLintRulesResult, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file_path is required")
+	}
+
+	config, err := eslint.GetConfig(filePath)
+	if err != nil {
+		return nil, err
+	}
+	rules, _ := config["rules"].(map[string]interface{})
+
+	groups := make(map[string]*types.ESLintRuleGroup)
+	result := &types.ESLintRulesResult{File: filePath, Total: len(rules)}
+	for name, setting := range rules {
+		status := types.RuleStatus{
+			Name:        name,
+			URL:         ruleURL(name),
+			Description: ruleDescriptions[name],
+		}
+		status.Severity, status.Options = parseRuleSetting(setting)
+
+		switch status.Severity {
+		case "error":
+			result.Errors++
+		case "warn":
+			result.Warnings++
+		default:
+			result.Off++
+		}
+
+		plugin := rulePlugin(name)
+		group, ok := groups[plugin]
+		if !ok {
+			group = &types.ESLintRuleGroup{Plugin: plugin}
+			groups[plugin] = group
+		}
+		group.Rules = append(group.Rules, status)
+	}
+
+	for _, group := range groups {
+		slices.SortFunc(group.Rules, func(a, b types.RuleStatus) int { return strings.Compare(a.Name, b.Name) })
+		result.Plugins = append(result.Plugins, *group)
+	}
+	slices.SortFunc(result.Plugins, func(a, b types.ESLintRuleGroup) int {
+		if (a.Plugin == "core") != (b.Plugin == "core") {
+			if a.Plugin == "core" {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Plugin, b.Plugin)
+	})
+	if result.Plugins == nil {
+		result.Plugins = []types.ESLintRuleGroup{}
+	}
+
+	result.Summary = fmt.Sprintf("%d rule(s) configured: %d error, %d warn, %d off", result.Total, result.Errors, result.Warnings, result.Off)
+	return result, nil
+}
+
+// parseRuleSetting reads a rule's severity and options from its printed config, which
+// is a severity (0-2 or "off"/"warn"/"error") or an array of a severity and options
+func parseRuleSetting(setting interface{}) (string, []interface{}) {
+	var options []interface{}
+	if array, ok := setting.([]interface{}); ok {
+		if len(array) == 0 {
+			return "off", nil
+		}
+		setting, options = array[0], array[1:]
+	}
+
+	switch severity := setting.(type) {
+	case float64:
+		if name, ok := ruleSeverities[severity]; ok {
+			return name, options
+		}
+	case string:
+		switch severity = strings.ToLower(severity); severity {
+		case "off", "warn", "error":
+			return severity, options
+		}
+	}
+	return "off", options
+}
+
+// rulePlugin returns the plugin prefix of a rule, such as "@typescript-eslint", or
+// "core" for ESLint's own rules
+func rulePlugin(rule string) string {
+	if slash := strings.LastIndex(rule, "/"); slash >= 0 {
+		return rule[:slash]
+	}
+	return "core"
+}
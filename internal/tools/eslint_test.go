@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// fakeESLint installs an eslint that reports version for --version, counting those
+// calls in the returned file, and an empty JSON report for anything else
+func fakeESLint(t *testing.T, root, version string) (argsFile, versionCalls string) {
+	t.Helper()
+	versionCalls = filepath.Join(root, "version-calls")
+	script := `if [ "$1" = "--version" ]; then
+  echo x >> '` + versionCalls + `'
+  echo '` + version + `'
+  exit 0
+fi
+echo '[]'
+`
+	return writeFakeBinary(t, root, "eslint", script), versionCalls
+}
+
+// lintFixture creates a project with one source file and an .eslintignore
+func lintFixture(t *testing.T) (root, file, ignoreFile string) {
+	t.Helper()
+	root = t.TempDir()
+	file = writeFile(t, root, "src/index.ts", "export const value = 1;\n")
+	ignoreFile = writeFile(t, root, ".eslintignore", "# build output\ndist/\n\ncoverage/\n")
+	return root, file, ignoreFile
+}
+
+func TestParseMajorVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   int
+	}{
+		{"v9.4.0\n", 9},
+		{"v8.57.0", 8},
+		{"7.32.0", 7},
+		{"ESLint v10.0.0-alpha.1", 10},
+		{"", 0},
+		{"command not found", 0},
+	}
+	for _, tt := range tests {
+		if got := parseMajorVersion(tt.output); got != tt.want {
+			t.Errorf("parseMajorVersion(%q) = %d, want %d", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestLintCheckFlagsByESLintVersion(t *testing.T) {
+	t.Setenv("ESLINT_USE_FLAT_CONFIG", "")
+
+	tests := []struct {
+		name       string
+		version    string
+		flatFile   bool
+		env        map[string]string
+		wantFormat string
+		wantArgs   []string
+		rejectArgs []string
+	}{
+		{
+			name:       "eslint 8",
+			version:    "v8.57.0",
+			wantFormat: "eslintrc",
+			wantArgs:   []string{"--no-eslintrc", "--ignore-path"},
+			rejectArgs: []string{"--no-config-lookup", "--ignore-pattern"},
+		},
+		{
+			name:       "eslint 8 with eslint.config.js",
+			version:    "v8.57.0",
+			flatFile:   true,
+			wantFormat: "flat",
+			wantArgs:   []string{"--no-config-lookup", "--ignore-pattern", "dist/", "coverage/"},
+			rejectArgs: []string{"--no-eslintrc", "--ignore-path", "# build output"},
+		},
+		{
+			name:       "eslint 9",
+			version:    "v9.4.0",
+			wantFormat: "flat",
+			wantArgs:   []string{"--no-config-lookup", "--ignore-pattern", "dist/", "coverage/"},
+			rejectArgs: []string{"--no-eslintrc", "--ignore-path"},
+		},
+		{
+			name:       "eslint 9 with ESLINT_USE_FLAT_CONFIG=false",
+			version:    "v9.4.0",
+			env:        map[string]string{"ESLINT_USE_FLAT_CONFIG": "false"},
+			wantFormat: "eslintrc",
+			wantArgs:   []string{"--no-eslintrc", "--ignore-path"},
+			rejectArgs: []string{"--no-config-lookup"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, file, ignoreFile := lintFixture(t)
+			if tt.flatFile {
+				writeFile(t, root, "eslint.config.js", "export default [];\n")
+			}
+			argsFile, _ := fakeESLint(t, root, tt.version)
+
+			eslint := NewESLintTool(WithEnv(tt.env))
+			result, err := eslint.LintCheck(types.LintCheckParams{FilePath: file, NoEslintrc: true, IgnorePath: ignoreFile})
+			if err != nil {
+				t.Fatalf("LintCheck: %v", err)
+			}
+			if result.ConfigFormat != tt.wantFormat {
+				t.Errorf("ConfigFormat = %q, want %q", result.ConfigFormat, tt.wantFormat)
+			}
+
+			args := readArgs(t, argsFile)
+			for _, arg := range tt.wantArgs {
+				if !slices.Contains(args, arg) {
+					t.Errorf("eslint args = %q, want %s", args, arg)
+				}
+			}
+			for _, arg := range tt.rejectArgs {
+				if slices.Contains(args, arg) {
+					t.Errorf("eslint args = %q, must not contain %s", args, arg)
+				}
+			}
+			if args[len(args)-1] != file {
+				t.Errorf("eslint args = %q, want the file last", args)
+			}
+		})
+	}
+}
+
+func TestESLintVersionIsCached(t *testing.T) {
+	t.Setenv("ESLINT_USE_FLAT_CONFIG", "")
+	root, file, _ := lintFixture(t)
+	_, versionCalls := fakeESLint(t, root, "v9.4.0")
+
+	eslint := NewESLintTool(WithProjectRoot(root))
+	for i := 0; i < 3; i++ {
+		if _, err := eslint.LintCheck(types.LintCheckParams{FilePath: file}); err != nil {
+			t.Fatalf("LintCheck: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(versionCalls)
+	if err != nil {
+		t.Fatalf("eslint --version never ran: %v", err)
+	}
+	if calls := strings.Count(string(data), "x"); calls != 1 {
+		t.Errorf("eslint --version ran %d times, want 1", calls)
+	}
+
+	version, err := eslint.GetVersion()
+	if err != nil || version != "v9.4.0" {
+		t.Errorf("GetVersion = %q, %v; want v9.4.0", version, err)
+	}
+}
+
+// fakePrintConfig installs an eslint whose --print-config output reports how many times
+// it has run, so tests can tell a cached config from a fresh one
+func fakePrintConfig(t *testing.T, root string) {
+	t.Helper()
+	counter := filepath.Join(root, "print-config-calls")
+	writeFakeBinary(t, root, "eslint", `echo x >> '`+counter+`'
+echo "{\"rules\":{\"semi\":[\"error\"]},\"calls\":$(wc -l < '`+counter+`')}"
+`)
+}
+
+// configCalls returns how many --print-config runs produced config
+func configCalls(t *testing.T, config map[string]interface{}) int {
+	t.Helper()
+	calls, ok := config["calls"].(float64)
+	if !ok {
+		t.Fatalf("config = %v, want a calls count", config)
+	}
+	return int(calls)
+}
+
+func TestGetConfigCache(t *testing.T) {
+	root := t.TempDir()
+	fakePrintConfig(t, root)
+	configFile := writeFile(t, root, "eslint.config.js", "export default [];\n")
+	file := writeFile(t, root, "src/index.ts", "export {};\n")
+	sibling := writeFile(t, root, "src/util.ts", "export {};\n")
+	script := writeFile(t, root, "src/legacy.js", "module.exports = {};\n")
+
+	eslint := NewESLintTool()
+	lookup := func(path string) int {
+		t.Helper()
+		config, err := eslint.GetConfig(path)
+		if err != nil {
+			t.Fatalf("GetConfig(%s): %v", path, err)
+		}
+		return configCalls(t, config)
+	}
+
+	if calls := lookup(file); calls != 1 {
+		t.Fatalf("first lookup ran eslint %d times, want 1", calls)
+	}
+	if calls := lookup(file); calls != 1 {
+		t.Errorf("second lookup ran eslint again (calls = %d), want it served from cache", calls)
+	}
+	if calls := lookup(sibling); calls != 1 {
+		t.Errorf("lookup of a file with the same directory and extension ran eslint again (calls = %d)", calls)
+	}
+	if calls := lookup(script); calls != 2 {
+		t.Errorf("lookup of a .js file = %d calls, want a separate lookup", calls)
+	}
+
+	// Editing the config file invalidates the cached config
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(configFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if calls := lookup(file); calls != 3 {
+		t.Errorf("lookup after a config change = %d calls, want a fresh lookup", calls)
+	}
+	if calls := lookup(file); calls != 3 {
+		t.Errorf("lookup after refreshing = %d calls, want the refreshed config cached", calls)
+	}
+
+	// So does adding a config file closer to the source
+	writeFile(t, root, "src/.eslintrc.json", "{}\n")
+	if calls := lookup(file); calls != 4 {
+		t.Errorf("lookup after adding a config file = %d calls, want a fresh lookup", calls)
+	}
+
+	if size := eslint.ConfigCacheSize(); size != 2 {
+		t.Errorf("ConfigCacheSize = %d, want 2 (.ts and .js in src)", size)
+	}
+	eslint.ClearConfigCache()
+	if size := eslint.ConfigCacheSize(); size != 0 {
+		t.Errorf("ConfigCacheSize after ClearConfigCache = %d, want 0", size)
+	}
+	if calls := lookup(file); calls != 5 {
+		t.Errorf("lookup after ClearConfigCache = %d calls, want a fresh lookup", calls)
+	}
+}
@@ -0,0 +1,207 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mcp-typescript-assistant/internal/paths"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", gitUnavailable(err)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitUnavailable wraps err in a ToolUnavailableError for git
+func gitUnavailable(err error) *types.ToolUnavailableError {
+	return &types.ToolUnavailableError{
+		Tool:    "git",
+		Install: "install git from https://git-scm.com",
+		Err:     err,
+	}
+}
+
+// checkRefSyntax rejects refs git would parse as an option
+func checkRefSyntax(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid ref %q: refs can't start with '-'", ref)
+	}
+	return nil
+}
+
+// verifyCommit returns an error unless ref names a commit of the repository in dir
+func verifyCommit(dir, ref string) error {
+	if err := checkRefSyntax(ref); err != nil {
+		return err
+	}
+	if _, err := git(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		var unavailable *types.ToolUnavailableError
+		if errors.As(err, &unavailable) {
+			return err
+		}
+		return fmt.Errorf("unknown ref %q: not a commit in %s", ref, dir)
+	}
+	return nil
+}
+
+// gitWorktree is a temporary checkout of a git ref alongside the repository containing a path
+type gitWorktree struct {
+	repoRoot string
+	dir      string
+}
+
+// newGitWorktree checks out ref into a temporary worktree of the repository containing
+// path. The node_modules directories between path and the repository root are linked
+// into the worktree so tools resolve the same dependencies. Call remove when done.
+func newGitWorktree(path, ref string) (*gitWorktree, error) {
+	dir := path
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	repoRoot, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyCommit(repoRoot, ref); err != nil {
+		return nil, err
+	}
+
+	tmp, err := NewScratchDir("baseline-*")
+	if err != nil {
+		return nil, err
+	}
+	// git worktree add requires the target not to exist
+	worktreeDir := filepath.Join(tmp, "worktree")
+	if _, err := git(repoRoot, "worktree", "add", "--detach", "--", worktreeDir, ref); err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+
+	w := &gitWorktree{repoRoot: repoRoot, dir: worktreeDir}
+	w.linkNodeModules(dir)
+	return w, nil
+}
+
+// path returns where p, a path inside the repository, lives in the worktree
+func (w *gitWorktree) path(p string) (string, error) {
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+
+	rel, err := filepath.Rel(w.repoRoot, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the git repository %s", p, w.repoRoot)
+	}
+	return filepath.Join(w.dir, rel), nil
+}
+
+// linkNodeModules symlinks the untracked node_modules directories from dir up to the
+// repository root into the worktree
+func (w *gitWorktree) linkNodeModules(dir string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	for current := dir; ; current = filepath.Dir(current) {
+		source := filepath.Join(current, "node_modules")
+		if info, err := os.Stat(source); err == nil && info.IsDir() {
+			if target, err := w.path(source); err == nil {
+				if _, err := os.Lstat(target); os.IsNotExist(err) {
+					os.Symlink(source, target)
+				}
+			}
+		}
+
+		if resolved, err := filepath.EvalSymlinks(current); err == nil && resolved == w.repoRoot {
+			return
+		}
+		if filepath.Dir(current) == current {
+			return
+		}
+	}
+}
+
+// remove deletes the worktree and its temporary directory
+func (w *gitWorktree) remove() {
+	git(w.repoRoot, "worktree", "remove", "--force", w.dir)
+	os.RemoveAll(filepath.Dir(w.dir))
+}
+
+// ChangedFiles lists the TypeScript files under dir that differ from baseRef ("HEAD" when
+// empty), as absolute paths. Uncommitted and untracked files count as changed, so a dirty
+// worktree is analyzed as it is on disk; deleted files are left out. For a branch such as
+// "main", files are compared against its merge base with HEAD, like a pull request diff.
+func ChangedFiles(dir, baseRef string) ([]string, error) {
+	if baseRef == "" {
+		baseRef = "HEAD"
+	}
+	if err := checkRefSyntax(baseRef); err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve directory: %w", err)
+	}
+
+	if _, err := git(dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		var unavailable *types.ToolUnavailableError
+		if errors.As(err, &unavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+
+	base := baseRef
+	if baseRef != "HEAD" {
+		if mergeBase, err := git(dir, "merge-base", baseRef, "HEAD"); err == nil {
+			base = mergeBase
+		}
+	}
+	if _, err := git(dir, "rev-parse", "--verify", "--quiet", base+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown base ref %q", baseRef)
+	}
+
+	diff, err := git(dir, "diff", "-z", "--name-only", "--relative", "--diff-filter=ACMR", base)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(dir, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, name := range strings.Split(diff+"\x00"+untracked, "\x00") {
+		if name == "" || seen[name] || !paths.IsTypeScriptFile(name) {
+			continue
+		}
+		seen[name] = true
+		files = append(files, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// langServiceScript answers queries with the TypeScript language service installed
+// for the project being analyzed
+//
+//go:embed langservice.js
+var langServiceScript []byte
+
+// lsRequest is the JSON request read by langservice.js
+type lsRequest struct {
+	Op       string `json:"op"`
+	File     string `json:"file"`
+	TSConfig string `json:"tsconfig,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	// Symbols are the declarations a types_batch request describes
+	Symbols []string `json:"symbols,omitempty"`
+	Line    int      `json:"line,omitempty"`
+	Column  int      `json:"column,omitempty"`
+	NewName string   `json:"new_name,omitempty"`
+	// Specifier is the import a resolve_module request resolves from File
+	Specifier string `json:"specifier,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	// MaxEntries caps the completion entries the script looks up details for
+	MaxEntries int `json:"max_entries,omitempty"`
+}
+
+// lsResponse is the JSON response written by langservice.js
+type lsResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+}
+
+// positionQuery is a language service request for a symbol or position in one file
+type positionQuery struct {
+	request lsRequest
+	// logicalPath is the file name reported in results, which differs from the
+	// queried file when inline content was written to a temporary file
+	logicalPath string
+	cleanup     func()
+}
+
+// newPositionQuery validates params and builds the request for op, writing inline
+// content to a temporary file. The caller must call cleanup when done.
+func newPositionQuery(op string, params types.SymbolPositionParams) (*positionQuery, error) {
+	if err := checkFileInput(params.FilePath, params.FileContent); err != nil {
+		return nil, err
+	}
+	if params.SymbolName == "" && params.Line <= 0 {
+		return nil, fmt.Errorf("either symbol_name or line is required")
+	}
+	if params.Column < 0 {
+		return nil, fmt.Errorf("column must be positive")
+	}
+	if params.FileContent == "" {
+		if err := checkFilePath(params.FilePath, false); err != nil {
+			return nil, err
+		}
+	}
+
+	query := &positionQuery{
+		request: lsRequest{
+			Op:     op,
+			File:   params.FilePath,
+			Symbol: params.SymbolName,
+			Line:   params.Line,
+			Column: params.Column,
+		},
+		logicalPath: params.FilePath,
+		cleanup:     func() {},
+	}
+
+	if params.FileContent != "" {
+		query.logicalPath = contentName(params.FilePath)
+		contentFile, cleanup, err := writeFileContent(query.logicalPath, params.FileContent, params.ContentEncoding)
+		if err != nil {
+			return nil, err
+		}
+		query.request.File = contentFile
+		query.cleanup = cleanup
+	}
+
+	return query, nil
+}
+
+// logicalLocation reports a file returned by the language service relative to the
+// queried file's directory, mapping inline content back to its logical name
+func (q *positionQuery) logicalLocation(file string) string {
+	if absFile, err := filepath.Abs(q.request.File); err == nil && absFile == file {
+		return q.logicalPath
+	}
+	return normalizePath(filepath.Dir(q.request.File), file)
+}
+
+// langServiceScriptPath writes the embedded script to the cache directory, keyed by
+// its hash so that a stale copy from another server version is never used
+func (tsc *TypeScriptCompiler) langServiceScriptPath() (string, error) {
+	hash := sha256.Sum256(langServiceScript)
+	path, err := tsc.runner.cachePath("langservice-" + hex.EncodeToString(hash[:8]) + ".js")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.WriteFile(path, langServiceScript, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write language service script: %w", err)
+	}
+	return path, nil
+}
+
+// queryLanguageService runs request through langservice.js and decodes its result into out
+func (tsc *TypeScriptCompiler) queryLanguageService(request lsRequest, out interface{}) error {
+	script, err := tsc.langServiceScriptPath()
+	if err != nil {
+		return err
+	}
+
+	if request.File, err = filepath.Abs(request.File); err != nil {
+		return fmt.Errorf("failed to resolve file path: %w", err)
+	}
+	if tsc.runner.tsconfig != "" {
+		if request.TSConfig, err = filepath.Abs(tsc.ConfigPath(tsc.runner.projectRoot)); err != nil {
+			return fmt.Errorf("failed to resolve tsconfig path: %w", err)
+		}
+	}
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode language service request: %w", err)
+	}
+
+	cmd := tsc.runner.exec("node", script)
+	cmd.Dir = filepath.Dir(request.File)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nodeUnavailable(err)
+	}
+	if err != nil {
+		return fmt.Errorf("language service failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var response lsResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return fmt.Errorf("failed to parse language service output: %w", err)
+	}
+	if response.Error != "" {
+		return errors.New(response.Error)
+	}
+	if err := json.Unmarshal(response.Result, out); err != nil {
+		return fmt.Errorf("failed to parse language service result: %w", err)
+	}
+	return nil
+}
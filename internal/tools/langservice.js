@@ -0,0 +1,352 @@
+// Runs a query against the TypeScript language service for the MCP server.
+// Reads a JSON request on stdin and writes {"result": ...} or {"error": "..."} to stdout.
+'use strict';
+
+const fs = require('fs');
+const path = require('path');
+
+// loadTypeScript loads the typescript package installed for the project, falling back to a global install
+function loadTypeScript(dirs) {
+  for (const dir of dirs) {
+    try {
+      return require(require.resolve('typescript', { paths: [dir] }));
+    } catch (e) {
+      // try the next directory
+    }
+  }
+  try {
+    const root = require('child_process').execSync('npm root -g', { encoding: 'utf8' }).trim();
+    return require(path.join(root, 'typescript'));
+  } catch (e) {
+    throw new Error('the typescript package was not found; install it with npm install --save-dev typescript');
+  }
+}
+
+// createService builds a language service over the project that contains file
+function createService(ts, file, tsconfig) {
+  const configPath = tsconfig || ts.findConfigFile(path.dirname(file), ts.sys.fileExists);
+  let options = { allowJs: true, jsx: ts.JsxEmit.Preserve };
+  let fileNames = [file];
+
+  if (configPath && fs.existsSync(configPath)) {
+    const config = ts.readConfigFile(configPath, ts.sys.readFile);
+    if (!config.error) {
+      const parsed = ts.parseJsonConfigFileContent(config.config, ts.sys, path.dirname(configPath));
+      options = parsed.options;
+      fileNames = parsed.fileNames.map((name) => path.resolve(name));
+      if (!fileNames.includes(file)) {
+        fileNames.push(file);
+      }
+    }
+  }
+
+  const host = {
+    getScriptFileNames: () => fileNames,
+    getScriptVersion: () => '0',
+    getScriptSnapshot: (name) => {
+      if (!fs.existsSync(name)) {
+        return undefined;
+      }
+      return ts.ScriptSnapshot.fromString(fs.readFileSync(name, 'utf8'));
+    },
+    getCurrentDirectory: () => (configPath ? path.dirname(configPath) : path.dirname(file)),
+    getCompilationSettings: () => options,
+    getDefaultLibFileName: (compilerOptions) => ts.getDefaultLibFilePath(compilerOptions),
+    fileExists: ts.sys.fileExists,
+    readFile: ts.sys.readFile,
+    readDirectory: ts.sys.readDirectory,
+    directoryExists: ts.sys.directoryExists,
+    getDirectories: ts.sys.getDirectories,
+  };
+
+  return ts.createLanguageService(host, ts.createDocumentRegistry());
+}
+
+// nodeAt returns the innermost node spanning pos
+function nodeAt(ts, sourceFile, pos) {
+  let found = sourceFile;
+  const visit = (node) => {
+    if (pos >= node.getStart(sourceFile) && pos < node.getEnd()) {
+      found = node;
+      ts.forEachChild(node, visit);
+    }
+  };
+  ts.forEachChild(sourceFile, visit);
+  return found;
+}
+
+// findDeclarationName returns the name node of the first declaration called name
+function findDeclarationName(ts, sourceFile, name) {
+  let found;
+  const visit = (node) => {
+    if (found) {
+      return;
+    }
+    if (node.name && ts.isIdentifier(node.name) && node.name.text === name) {
+      found = node.name;
+      return;
+    }
+    ts.forEachChild(node, visit);
+  };
+  ts.forEachChild(sourceFile, visit);
+  return found;
+}
+
+// resolvePosition returns the offset of the request's line/column, or of the declaration of its symbol
+function resolvePosition(ts, sourceFile, request) {
+  if (request.line > 0) {
+    const lines = sourceFile.getLineStarts();
+    if (request.line > lines.length) {
+      throw new Error(`line ${request.line} is past the end of the file (${lines.length} lines)`);
+    }
+    return sourceFile.getPositionOfLineAndCharacter(request.line - 1, Math.max(request.column, 1) - 1);
+  }
+
+  const name = findDeclarationName(ts, sourceFile, request.symbol);
+  if (!name) {
+    throw new Error(`no declaration named ${request.symbol} found`);
+  }
+  return name.getStart(sourceFile);
+}
+
+// location converts a node's start to a 1-based file/line/column
+function location(node) {
+  const sourceFile = node.getSourceFile();
+  const { line, character } = sourceFile.getLineAndCharacterOfPosition(node.getStart(sourceFile));
+  return { file: sourceFile.fileName, line: line + 1, column: character + 1 };
+}
+
+// typeInfo describes the symbol and type at pos, like an editor hover
+function typeInfo(ts, service, file, pos) {
+  const program = service.getProgram();
+  const checker = program.getTypeChecker();
+  const sourceFile = program.getSourceFile(file);
+  const node = nodeAt(ts, sourceFile, pos);
+  const quickInfo = service.getQuickInfoAtPosition(file, pos);
+
+  let symbol = checker.getSymbolAtLocation(node);
+  if (symbol && symbol.flags & ts.SymbolFlags.Alias) {
+    symbol = checker.getAliasedSymbol(symbol);
+  }
+
+  // Interfaces, type aliases and classes are described by their declared instance type
+  let type;
+  if (symbol && symbol.flags & (ts.SymbolFlags.Interface | ts.SymbolFlags.TypeAlias | ts.SymbolFlags.Class | ts.SymbolFlags.Enum)) {
+    type = checker.getDeclaredTypeOfSymbol(symbol);
+  } else if (symbol) {
+    type = checker.getTypeOfSymbolAtLocation(symbol, node);
+  } else {
+    type = checker.getTypeAtLocation(node);
+  }
+
+  const properties = checker.getPropertiesOfType(type).slice(0, 100).map((property) => ({
+    name: property.getName(),
+    type: checker.typeToString(checker.getTypeOfSymbolAtLocation(property, node)),
+    optional: (property.flags & ts.SymbolFlags.Optional) !== 0,
+    documentation: ts.displayPartsToString(property.getDocumentationComment(checker)),
+  }));
+
+  const declaration = symbol && symbol.declarations && symbol.declarations[0];
+  return {
+    symbol_name: symbol ? symbol.getName() : node.getText(sourceFile),
+    type: checker.typeToString(type, undefined, ts.TypeFormatFlags.NoTruncation),
+    kind: quickInfo ? quickInfo.kind : 'unknown',
+    quick_info: quickInfo ? ts.displayPartsToString(quickInfo.displayParts) : '',
+    documentation: quickInfo ? ts.displayPartsToString(quickInfo.documentation) : '',
+    location: declaration ? location(declaration) : location(node),
+    properties,
+  };
+}
+
+// spanLocation converts a text span in fileName to a 1-based file/line/column
+function spanLocation(ts, program, fileName, span) {
+  let sourceFile = program.getSourceFile(fileName);
+  if (!sourceFile) {
+    sourceFile = ts.createSourceFile(fileName, fs.readFileSync(fileName, 'utf8'), ts.ScriptTarget.Latest);
+  }
+  const { line, character } = sourceFile.getLineAndCharacterOfPosition(span.start);
+  return { file: fileName, line: line + 1, column: character + 1 };
+}
+
+// symbolName returns the name of the identifier at pos
+function symbolName(ts, service, file, pos) {
+  const program = service.getProgram();
+  const sourceFile = program.getSourceFile(file);
+  return nodeAt(ts, sourceFile, pos).getText(sourceFile);
+}
+
+// references lists every reference to the symbol at pos, including its declarations
+function references(ts, service, file, pos) {
+  const entries = service.getReferencesAtPosition(file, pos) || [];
+  const program = service.getProgram();
+  return {
+    symbol_name: symbolName(ts, service, file, pos),
+    locations: entries.map((entry) => spanLocation(ts, program, entry.fileName, entry.textSpan)),
+  };
+}
+
+// definitions lists where the symbol at pos is defined
+function definitions(ts, service, file, pos) {
+  const entries = service.getDefinitionAtPosition(file, pos) || [];
+  const program = service.getProgram();
+  return {
+    symbol_name: symbolName(ts, service, file, pos),
+    locations: entries.map((entry) => spanLocation(ts, program, entry.fileName, entry.textSpan)),
+  };
+}
+
+// spanRange converts a text span in fileName to 1-based start and end lines and columns
+function spanRange(ts, program, fileName, span) {
+  const start = spanLocation(ts, program, fileName, span);
+  const end = spanLocation(ts, program, fileName, { start: span.start + span.length });
+  return { file: fileName, line: start.line, column: start.column, end_line: end.line, end_column: end.column };
+}
+
+// isReservedWord reports whether name is a keyword that can't be used as an identifier
+function isReservedWord(ts, name) {
+  const token = ts.stringToToken(name);
+  return token !== undefined && token >= ts.SyntaxKind.FirstReservedWord && token <= ts.SyntaxKind.LastReservedWord;
+}
+
+// rename lists the edits that rename the symbol at pos to newName across the project
+function rename(ts, service, file, pos, newName) {
+  const info = service.getRenameInfo(file, pos, { allowRenameOfImportPath: false });
+  if (!info.canRename) {
+    throw new Error(`cannot rename: ${info.localizedErrorMessage}`);
+  }
+  if (!ts.isIdentifierText(newName, ts.ScriptTarget.Latest) || isReservedWord(ts, newName)) {
+    throw new Error(`cannot rename: ${newName} is not a valid identifier`);
+  }
+
+  const locations = service.findRenameLocations(file, pos, false, false, { providePrefixAndSuffixTextForRename: true }) || [];
+  const program = service.getProgram();
+  return {
+    symbol_name: info.displayName,
+    edits: locations.map((location) => ({
+      ...spanRange(ts, program, location.fileName, location.textSpan),
+      new_text: (location.prefixText || '') + newName + (location.suffixText || ''),
+    })),
+  };
+}
+
+// completions lists the completion entries at pos whose names start with prefix, with
+// details for the first max of them
+function completions(ts, service, file, pos, prefix, max) {
+  const info = service.getCompletionsAtPosition(file, pos, { includeCompletionsWithInsertText: true });
+  if (!info) {
+    return { entries: [], total: 0 };
+  }
+
+  const lowerPrefix = (prefix || '').toLowerCase();
+  const matching = info.entries
+    .filter((entry) => entry.name.toLowerCase().startsWith(lowerPrefix))
+    .sort((a, b) => a.sortText.localeCompare(b.sortText) || a.name.localeCompare(b.name));
+
+  const entries = matching.slice(0, max).map((entry) => {
+    const details = service.getCompletionEntryDetails(file, pos, entry.name, undefined, entry.source, undefined, entry.data);
+    return {
+      label: entry.name,
+      kind: entry.kind,
+      detail: details ? ts.displayPartsToString(details.displayParts) : '',
+      documentation: details ? ts.displayPartsToString(details.documentation) : '',
+      insert_text: entry.insertText || '',
+      source: entry.source || '',
+    };
+  });
+
+  return { entries, total: matching.length, is_member_completion: info.isMemberCompletion };
+}
+
+// notInProgram is the error for a file the project's TypeScript program doesn't include
+function notInProgram(file) {
+  return new Error(`${file} is not part of the TypeScript program`);
+}
+
+// typesBatch describes each of the request's symbols, reporting a symbol that can't be
+// resolved in its entry instead of failing the whole batch
+function typesBatch(ts, service, sourceFile, request) {
+  if (!sourceFile) {
+    throw notInProgram(request.file);
+  }
+  return request.symbols.map((symbol) => {
+    try {
+      return typeInfo(ts, service, request.file, resolvePosition(ts, sourceFile, { symbol }));
+    } catch (e) {
+      return { symbol_name: symbol, error: e.message };
+    }
+  });
+}
+
+// typesPackageRegex matches a path inside an @types package, capturing its name
+const typesPackageRegex = /[\\/]node_modules[\\/]@types[\\/]([^\\/]+)[\\/]/;
+
+// resolveModule resolves specifier as if imported from file, with the project's module
+// resolution settings, and reports where its types are declared
+function resolveModule(ts, service, file, specifier) {
+  const options = service.getProgram().getCompilerOptions();
+  const { resolvedModule } = ts.resolveModuleName(specifier, file, options, ts.sys);
+  if (!resolvedModule) {
+    throw new Error(`cannot resolve module ${specifier} from ${file}`);
+  }
+
+  const resolvedPath = path.resolve(resolvedModule.resolvedFileName);
+  // Declaration files and TypeScript sources both declare the module's types
+  const hasTypes = /\.[cm]?tsx?$/.test(resolvedPath);
+  const typesPackage = resolvedPath.match(typesPackageRegex);
+  const packageId = resolvedModule.packageId;
+  return {
+    specifier,
+    resolved_path: resolvedPath,
+    declaration_path: hasTypes ? resolvedPath : '',
+    extension: resolvedModule.extension,
+    is_external: Boolean(resolvedModule.isExternalLibraryImport),
+    package_name: packageId ? packageId.name : '',
+    package_version: packageId ? packageId.version : '',
+    types_package: typesPackage ? `@types/${typesPackage[1]}` : '',
+  };
+}
+
+// operations answer requests about a symbol or position in a file
+const operations = {
+  types: (ts, service, request, pos) => typeInfo(ts, service, request.file, pos),
+  references: (ts, service, request, pos) => references(ts, service, request.file, pos),
+  definition: (ts, service, request, pos) => definitions(ts, service, request.file, pos),
+  rename: (ts, service, request, pos) => rename(ts, service, request.file, pos, request.new_name),
+  complete: (ts, service, request, pos) => completions(ts, service, request.file, pos, request.prefix, request.max_entries),
+};
+
+// fileOperations answer requests about a file as a whole; sourceFile is undefined when
+// the program doesn't include the file
+const fileOperations = {
+  types_batch: (ts, service, request, sourceFile) => typesBatch(ts, service, sourceFile, request),
+  resolve_module: (ts, service, request) => resolveModule(ts, service, request.file, request.specifier),
+};
+
+function main() {
+  const request = JSON.parse(fs.readFileSync(0, 'utf8'));
+  request.file = path.resolve(request.file);
+
+  const operation = operations[request.op];
+  const fileOperation = fileOperations[request.op];
+  if (!operation && !fileOperation) {
+    throw new Error(`unknown operation ${request.op}`);
+  }
+
+  const ts = loadTypeScript([path.dirname(request.file), process.cwd()]);
+  const service = createService(ts, request.file, request.tsconfig);
+  const sourceFile = service.getProgram().getSourceFile(request.file);
+  if (fileOperation) {
+    return fileOperation(ts, service, request, sourceFile);
+  }
+  if (!sourceFile) {
+    throw notInProgram(request.file);
+  }
+
+  return operation(ts, service, request, resolvePosition(ts, sourceFile, request));
+}
+
+try {
+  process.stdout.write(JSON.stringify({ result: main() }));
+} catch (e) {
+  process.stdout.write(JSON.stringify({ error: e.message }));
+}
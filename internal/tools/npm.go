@@ -0,0 +1,377 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// NPMTool checks a project's dependencies with its package manager
+type NPMTool struct {
+	runner runner
+}
+
+// NewNPMTool creates a new npm tool instance. Commands run with the options' package
+// manager, environment, NODE_OPTIONS and timeout.
+func NewNPMTool(opts ...Option) *NPMTool {
+	return &NPMTool{
+		runner: newRunner(PackageManagerNPM, opts...),
+	}
+}
+
+// npmOutdatedEntry is one package in npm outdated --json output
+type npmOutdatedEntry struct {
+	Current  string `json:"current"`
+	Wanted   string `json:"wanted"`
+	Latest   string `json:"latest"`
+	Type     string `json:"type"`
+	Location string `json:"location"`
+	// DependencyType is pnpm's name for Type
+	DependencyType string `json:"dependencyType"`
+}
+
+// npmAuditReport is the npm audit --json output. npm 7 and later report
+// vulnerabilities by package; pnpm, like npm 6, reports advisories instead.
+type npmAuditReport struct {
+	Vulnerabilities map[string]struct {
+		Severity     string          `json:"severity"`
+		Range        string          `json:"range"`
+		FixAvailable json.RawMessage `json:"fixAvailable"`
+	} `json:"vulnerabilities"`
+	Advisories map[string]auditAdvisory `json:"advisories"`
+	Metadata   struct {
+		Vulnerabilities map[string]int `json:"vulnerabilities"`
+	} `json:"metadata"`
+}
+
+// auditAdvisory is one advisory in pnpm and yarn audit output
+type auditAdvisory struct {
+	ModuleName         string `json:"module_name"`
+	Severity           string `json:"severity"`
+	VulnerableVersions string `json:"vulnerable_versions"`
+	PatchedVersions    string `json:"patched_versions"`
+}
+
+// yarnLine is one line of yarn's newline-delimited --json output
+type yarnLine struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// severityRank orders audit severities from least to most severe
+var severityRank = map[string]int{"info": 1, "low": 2, "moderate": 3, "high": 4, "critical": 5}
+
+// dependencyCommands are the outdated and audit commands of each package manager
+var dependencyCommands = map[string]struct {
+	outdated []string
+	audit    []string
+}{
+	PackageManagerNPM:  {[]string{"outdated", "--json", "--long"}, []string{"audit", "--json"}},
+	PackageManagerPNPM: {[]string{"outdated", "--format", "json"}, []string{"audit", "--json"}},
+	PackageManagerYarn: {[]string{"outdated", "--json"}, []string{"audit", "--json"}},
+}
+
+// CheckDependencies lists outdated dependencies and, if requested, known vulnerabilities
+func (npm *NPMTool) CheckDependencies(params types.CheckDependenciesParams) (*types.DependencyReport, error) {
+	if params.ProjectRoot == "" {
+		return nil, fmt.Errorf("project_root is required")
+	}
+	if _, err := os.Stat(filepath.Join(params.ProjectRoot, "package.json")); err != nil {
+		return nil, fmt.Errorf("no package.json found in %s: %w", params.ProjectRoot, err)
+	}
+
+	packageManager := npm.runner.packageManager
+	if packageManager == "" {
+		packageManager = detectPackageManager(params.ProjectRoot)
+	}
+	commands, ok := dependencyCommands[packageManager]
+	if !ok {
+		return nil, fmt.Errorf("unsupported package manager %q: use npm, pnpm or yarn", packageManager)
+	}
+
+	// outdated exits with code 1 whenever something is outdated, so the output
+	// is parsed regardless of the exit code
+	output, err := npm.run(params.ProjectRoot, packageManager, commands.outdated...)
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, npmUnavailable(packageManager, err)
+	}
+
+	var outdated []types.OutdatedPackage
+	var parseErr error
+	if packageManager == PackageManagerYarn {
+		outdated, parseErr = parseYarnOutdatedOutput(output)
+	} else {
+		outdated, parseErr = parseOutdatedOutput(output)
+	}
+	if parseErr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("%s outdated failed: %w", packageManager, err)
+		}
+		return nil, parseErr
+	}
+
+	report := &types.DependencyReport{
+		Outdated: outdated,
+	}
+	summary := fmt.Sprintf("%d outdated packages", len(outdated))
+
+	if params.Audit {
+		// audit also exits non-zero when vulnerabilities are found
+		auditOutput, err := npm.run(params.ProjectRoot, packageManager, commands.audit...)
+		var vulnerabilities *types.VulnerabilitySummary
+		var parseErr error
+		if packageManager == PackageManagerYarn {
+			vulnerabilities, parseErr = parseYarnAuditOutput(auditOutput)
+		} else {
+			vulnerabilities, parseErr = parseAuditOutput(auditOutput)
+		}
+		if parseErr != nil {
+			if err != nil {
+				return nil, fmt.Errorf("%s audit failed: %w", packageManager, err)
+			}
+			return nil, parseErr
+		}
+		report.Vulnerabilities = vulnerabilities
+		summary += fmt.Sprintf(", %d vulnerable packages", len(vulnerabilities.Packages))
+	}
+
+	report.Summary = summary
+	return report, nil
+}
+
+// run executes packageManager with args in dir and returns its stdout
+func (npm *NPMTool) run(dir, packageManager string, args ...string) ([]byte, error) {
+	cmd := npm.runner.exec(packageManager, args...)
+	cmd.Dir = dir
+	return cmd.Output()
+}
+
+// parseOutdatedOutput parses npm outdated --json output. Packages installed in several
+// workspaces are reported as an array of entries rather than a single object.
+func parseOutdatedOutput(output []byte) ([]types.OutdatedPackage, error) {
+	outdated := []types.OutdatedPackage{}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return outdated, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse npm outdated output: %w", err)
+	}
+
+	for name, data := range raw {
+		var entries []npmOutdatedEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			var entry npmOutdatedEntry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return nil, fmt.Errorf("failed to parse npm outdated entry for %s: %w", name, err)
+			}
+			entries = []npmOutdatedEntry{entry}
+		}
+
+		for _, entry := range entries {
+			packageType := entry.Type
+			if packageType == "" {
+				packageType = entry.DependencyType
+			}
+			outdated = append(outdated, types.OutdatedPackage{
+				Name:     name,
+				Current:  entry.Current,
+				Wanted:   entry.Wanted,
+				Latest:   entry.Latest,
+				Type:     packageType,
+				Location: entry.Location,
+			})
+		}
+	}
+
+	sortOutdated(outdated)
+	return outdated, nil
+}
+
+// parseYarnOutdatedOutput parses yarn (v1) outdated --json output, whose packages are
+// the rows of a "table" line
+func parseYarnOutdatedOutput(output []byte) ([]types.OutdatedPackage, error) {
+	outdated := []types.OutdatedPackage{}
+
+	lines, err := parseYarnLines(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yarn outdated output: %w", err)
+	}
+	for _, line := range lines {
+		if line.Type != "table" {
+			continue
+		}
+		var table struct {
+			Head []string   `json:"head"`
+			Body [][]string `json:"body"`
+		}
+		if err := json.Unmarshal(line.Data, &table); err != nil {
+			return nil, fmt.Errorf("failed to parse yarn outdated table: %w", err)
+		}
+
+		columns := make(map[string]int, len(table.Head))
+		for i, name := range table.Head {
+			columns[name] = i
+		}
+		cell := func(row []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		for _, row := range table.Body {
+			outdated = append(outdated, types.OutdatedPackage{
+				Name:    cell(row, "Package"),
+				Current: cell(row, "Current"),
+				Wanted:  cell(row, "Wanted"),
+				Latest:  cell(row, "Latest"),
+				Type:    cell(row, "Package Type"),
+			})
+		}
+	}
+
+	sortOutdated(outdated)
+	return outdated, nil
+}
+
+// sortOutdated orders outdated packages by name, then location
+func sortOutdated(outdated []types.OutdatedPackage) {
+	sort.Slice(outdated, func(i, j int) bool {
+		if outdated[i].Name != outdated[j].Name {
+			return outdated[i].Name < outdated[j].Name
+		}
+		return outdated[i].Location < outdated[j].Location
+	})
+}
+
+// parseYarnLines splits yarn's newline-delimited --json output into its lines
+func parseYarnLines(output []byte) ([]yarnLine, error) {
+	var lines []yarnLine
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var line yarnLine
+		if err := json.Unmarshal(text, &line); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseAuditOutput parses npm or pnpm audit --json output into a vulnerability summary
+func parseAuditOutput(output []byte) (*types.VulnerabilitySummary, error) {
+	var report npmAuditReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse npm audit output: %w", err)
+	}
+	return summarizeAudit(&report), nil
+}
+
+// parseYarnAuditOutput parses yarn (v1) audit --json output, which has a line per
+// advisory and a summary line with the counts
+func parseYarnAuditOutput(output []byte) (*types.VulnerabilitySummary, error) {
+	lines, err := parseYarnLines(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yarn audit output: %w", err)
+	}
+
+	report := npmAuditReport{Advisories: map[string]auditAdvisory{}}
+	for i, line := range lines {
+		switch line.Type {
+		case "auditAdvisory":
+			var data struct {
+				Advisory auditAdvisory `json:"advisory"`
+			}
+			if err := json.Unmarshal(line.Data, &data); err != nil {
+				return nil, fmt.Errorf("failed to parse yarn audit advisory: %w", err)
+			}
+			report.Advisories[fmt.Sprint(i)] = data.Advisory
+		case "auditSummary":
+			var data struct {
+				Vulnerabilities map[string]int `json:"vulnerabilities"`
+			}
+			if err := json.Unmarshal(line.Data, &data); err != nil {
+				return nil, fmt.Errorf("failed to parse yarn audit summary: %w", err)
+			}
+			report.Metadata.Vulnerabilities = data.Vulnerabilities
+		}
+	}
+	return summarizeAudit(&report), nil
+}
+
+// summarizeAudit builds a vulnerability summary from an audit report. Advisories are
+// merged per package, keeping the most severe.
+func summarizeAudit(report *npmAuditReport) *types.VulnerabilitySummary {
+	summary := &types.VulnerabilitySummary{
+		Counts: report.Metadata.Vulnerabilities,
+	}
+	if summary.Counts == nil {
+		summary.Counts = map[string]int{}
+	}
+
+	for name, vulnerability := range report.Vulnerabilities {
+		// fixAvailable is either false or an object describing the fix
+		fixAvailable := len(vulnerability.FixAvailable) > 0 && string(vulnerability.FixAvailable) != "false"
+
+		summary.Packages = append(summary.Packages, types.VulnerablePackage{
+			Name:         name,
+			Severity:     vulnerability.Severity,
+			Range:        vulnerability.Range,
+			FixAvailable: fixAvailable,
+		})
+	}
+
+	advised := map[string]int{}
+	for _, advisory := range report.Advisories {
+		if _, ok := report.Vulnerabilities[advisory.ModuleName]; ok {
+			continue
+		}
+		// "<0.0.0" means no version has the fix
+		pkg := types.VulnerablePackage{
+			Name:         advisory.ModuleName,
+			Severity:     advisory.Severity,
+			Range:        advisory.VulnerableVersions,
+			FixAvailable: advisory.PatchedVersions != "" && advisory.PatchedVersions != "<0.0.0",
+		}
+		if i, ok := advised[pkg.Name]; !ok {
+			advised[pkg.Name] = len(summary.Packages)
+			summary.Packages = append(summary.Packages, pkg)
+		} else if severityRank[pkg.Severity] > severityRank[summary.Packages[i].Severity] {
+			summary.Packages[i] = pkg
+		}
+	}
+
+	sort.Slice(summary.Packages, func(i, j int) bool {
+		return summary.Packages[i].Name < summary.Packages[j].Name
+	})
+
+	return summary
+}
+
+// npmUnavailable wraps err in a ToolUnavailableError for packageManager
+func npmUnavailable(packageManager string, err error) *types.ToolUnavailableError {
+	install := "install Node.js from https://nodejs.org, which includes npm"
+	if packageManager != PackageManagerNPM {
+		install = "npm install -g " + packageManager
+	}
+	return &types.ToolUnavailableError{
+		Tool:    packageManager,
+		Install: install,
+		Err:     err,
+	}
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// fakePackageManager installs name on PATH, printing outdated for outdated and audit for
+// anything else. Both may refer to $MARKER, which tests set through WithEnv.
+func fakePackageManager(t *testing.T, name, outdated, audit string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nif [ \"$1\" = outdated ]; then\ncat <<EOF\n" + outdated + "\nEOF\nexit 1\nfi\ncat <<EOF\n" + audit + "\nEOF\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	root := t.TempDir()
+	writeFile(t, root, "package.json", "{}\n")
+	return root
+}
+
+func TestCheckDependenciesNPM(t *testing.T) {
+	root := fakePackageManager(t, "npm",
+		`{"left-pad":{"current":"1.0.0","wanted":"1.0.1","latest":"2.0.0","type":"dependencies","location":"$MARKER"}}`,
+		`{"vulnerabilities":{"lodash":{"severity":"high","range":"<4.17.21","fixAvailable":true}},"metadata":{"vulnerabilities":{"high":1}}}`)
+
+	npm := NewNPMTool(WithPackageManager(PackageManagerNPM), WithEnv(map[string]string{"MARKER": "node_modules/left-pad"}))
+	report, err := npm.CheckDependencies(types.CheckDependenciesParams{ProjectRoot: root, Audit: true})
+	if err != nil {
+		t.Fatalf("CheckDependencies: %v", err)
+	}
+
+	want := types.OutdatedPackage{Name: "left-pad", Current: "1.0.0", Wanted: "1.0.1", Latest: "2.0.0", Type: "dependencies", Location: "node_modules/left-pad"}
+	if len(report.Outdated) != 1 || report.Outdated[0] != want {
+		t.Errorf("Outdated = %+v, want %+v with the env from the tool options", report.Outdated, want)
+	}
+	vulnerable := report.Vulnerabilities
+	if vulnerable == nil || len(vulnerable.Packages) != 1 || vulnerable.Packages[0].Name != "lodash" || !vulnerable.Packages[0].FixAvailable || vulnerable.Counts["high"] != 1 {
+		t.Errorf("Vulnerabilities = %+v, want lodash with a fix", vulnerable)
+	}
+}
+
+func TestCheckDependenciesPNPM(t *testing.T) {
+	root := fakePackageManager(t, "pnpm",
+		`{"typescript":{"current":"5.3.3","wanted":"5.3.3","latest":"5.6.2","dependencyType":"devDependencies"}}`,
+		`{"advisories":{"1":{"module_name":"semver","severity":"moderate","vulnerable_versions":"<7.5.2","patched_versions":">=7.5.2"},"2":{"module_name":"semver","severity":"high","vulnerable_versions":"<6.0.0","patched_versions":"<0.0.0"}},"metadata":{"vulnerabilities":{"moderate":1,"high":1}}}`)
+	writeFile(t, root, "pnpm-lock.yaml", "lockfileVersion: '9.0'\n")
+
+	report, err := NewNPMTool().CheckDependencies(types.CheckDependenciesParams{ProjectRoot: root, Audit: true})
+	if err != nil {
+		t.Fatalf("CheckDependencies: %v", err)
+	}
+	if len(report.Outdated) != 1 || report.Outdated[0].Name != "typescript" || report.Outdated[0].Type != "devDependencies" {
+		t.Errorf("Outdated = %+v, want typescript from devDependencies", report.Outdated)
+	}
+	packages := report.Vulnerabilities.Packages
+	if len(packages) != 1 || packages[0].Severity != "high" || packages[0].FixAvailable {
+		t.Errorf("Packages = %+v, want semver once, at its most severe advisory", packages)
+	}
+}
+
+func TestCheckDependenciesYarn(t *testing.T) {
+	root := fakePackageManager(t, "yarn",
+		`{"type":"info","data":"Color legend"}
+{"type":"table","data":{"head":["Package","Current","Wanted","Latest","Package Type","URL"],"body":[["react","18.2.0","18.3.1","19.0.0","dependencies","https://react.dev"]]}}`,
+		`{"type":"auditAdvisory","data":{"advisory":{"module_name":"minimist","severity":"critical","vulnerable_versions":"<1.2.6","patched_versions":">=1.2.6"}}}
+{"type":"auditSummary","data":{"vulnerabilities":{"critical":1}}}`)
+
+	report, err := NewNPMTool(WithPackageManager(PackageManagerYarn)).CheckDependencies(types.CheckDependenciesParams{ProjectRoot: root, Audit: true})
+	if err != nil {
+		t.Fatalf("CheckDependencies: %v", err)
+	}
+	want := types.OutdatedPackage{Name: "react", Current: "18.2.0", Wanted: "18.3.1", Latest: "19.0.0", Type: "dependencies"}
+	if len(report.Outdated) != 1 || report.Outdated[0] != want {
+		t.Errorf("Outdated = %+v, want %+v", report.Outdated, want)
+	}
+	vulnerable := report.Vulnerabilities
+	if len(vulnerable.Packages) != 1 || vulnerable.Packages[0].Name != "minimist" || !vulnerable.Packages[0].FixAvailable || vulnerable.Counts["critical"] != 1 {
+		t.Errorf("Vulnerabilities = %+v, want minimist with a fix", vulnerable)
+	}
+}
@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// workerCount returns how many files to process at once: concurrency when set,
+// capped at runtime.NumCPU() and the number of files
+func workerCount(concurrency, files int) int {
+	workers := runtime.NumCPU()
+	if concurrency > 0 && concurrency < workers {
+		workers = concurrency
+	}
+	if files < workers {
+		workers = files
+	}
+	return max(workers, 1)
+}
+
+// forEachFile calls fn for every file using at most workers goroutines, stopping at the
+// first error. progress, when set, is called in order as each file completes.
+func forEachFile(files []string, workers int, progress types.ProgressFunc, fn func(i int, file string) error) error {
+	var mu sync.Mutex
+	done := 0
+
+	var g errgroup.Group
+	g.SetLimit(workers)
+	for i, file := range files {
+		g.Go(func() error {
+			if err := fn(i, file); err != nil {
+				return err
+			}
+			if progress != nil {
+				mu.Lock()
+				done++
+				progress(done, len(files))
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// commonDir returns the deepest directory containing every file
+func commonDir(files []string) string {
+	var common []string
+	for i, file := range files {
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			return ""
+		}
+		parts := strings.Split(filepath.Dir(absFile), string(filepath.Separator))
+		if i == 0 {
+			common = parts
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 {
+		return ""
+	}
+	if len(common) == 1 {
+		return string(filepath.Separator)
+	}
+	return strings.Join(common, string(filepath.Separator))
+}
+
+// lintFiles lints params.FilePaths concurrently and merges the results, ordering
+// issues by file and position regardless of which process finished first
+func (eslint *ESLintTool) lintFiles(params types.LintCheckParams) (*types.LintResult, error) {
+	if params.FilePath != "" || params.FileContent != "" {
+		return nil, fmt.Errorf("file_paths can't be combined with file_path or file_content")
+	}
+
+	results := make([]*types.LintResult, len(params.FilePaths))
+	workers := workerCount(params.Concurrency, len(params.FilePaths))
+	err := forEachFile(params.FilePaths, workers, params.Progress, func(i int, file string) error {
+		fileParams := params
+		fileParams.FilePaths = nil
+		fileParams.Progress = nil
+		fileParams.FilePath = file
+		fileParams.AbsolutePaths = true
+		fileParams.MaxIssues = math.MaxInt
+		// The warning limit applies to the files together, once merged
+		fileParams.MaxWarnings = nil
+		result, err := eslint.LintCheck(fileParams)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		results[i] = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &types.LintResult{
+		Success:      true,
+		Binary:       results[0].Binary,
+		ConfigFormat: results[0].ConfigFormat,
+	}
+	var issues []types.LintIssue
+	for _, result := range results {
+		merged.Success = merged.Success && result.Success
+		merged.Fixable += result.Fixable
+		issues = append(issues, result.Issues...)
+	}
+
+	if !params.AbsolutePaths {
+		base := commonDir(params.FilePaths)
+		for i := range issues {
+			issues[i].File = normalizePath(base, issues[i].File)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return lessPosition(issues[i].File, issues[i].Line, issues[i].Column, issues[j].File, issues[j].Line, issues[j].Column)
+	})
+
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = types.DefaultMaxIssues
+	}
+	merged.TotalIssues = len(issues)
+	merged.ErrorCount, merged.WarningCount = countSeverities(issues)
+	merged.Summary = fmt.Sprintf("Linted %d files. %s", len(params.FilePaths), eslint.generateSummary(issues, merged.Fixable))
+	if len(issues) > maxIssues {
+		issues = issues[:maxIssues]
+		merged.Truncated = true
+	}
+	merged.Issues = issues
+
+	return merged, nil
+}
+
+// typeCheckFiles type checks params.FilePaths concurrently, one tsc process per file,
+// and merges the diagnostics in file and position order
+func (tsc *TypeScriptCompiler) typeCheckFiles(params types.TypeCheckParams) (*types.TypeCheckResult, error) {
+	if params.FilePath != "" || params.FileContent != "" || params.CodeSnippet != "" || params.ProjectRoot != "" {
+		return nil, fmt.Errorf("file_paths can't be combined with file_path, file_content, code_snippet or project_root")
+	}
+
+	startTime := time.Now()
+	results := make([]*types.TypeCheckResult, len(params.FilePaths))
+	workers := workerCount(params.Concurrency, len(params.FilePaths))
+	err := forEachFile(params.FilePaths, workers, params.Progress, func(i int, file string) error {
+		fileParams := params
+		fileParams.FilePaths = nil
+		fileParams.Progress = nil
+		fileParams.FilePath = file
+		fileParams.AbsolutePaths = true
+		fileParams.MaxIssues = math.MaxInt
+		result, err := tsc.TypeCheck(fileParams)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		results[i] = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &types.TypeCheckResult{
+		Success:      true,
+		Mode:         "noEmit",
+		Binary:       results[0].Binary,
+		CountsByCode: make(map[string]int),
+	}
+	for _, result := range results {
+		merged.Success = merged.Success && result.Success
+		merged.Errors = append(merged.Errors, result.Errors...)
+		merged.Warnings = append(merged.Warnings, result.Warnings...)
+		merged.Total += result.Total
+		for code, count := range result.CountsByCode {
+			merged.CountsByCode[code] += count
+		}
+	}
+	merged.CompileTime = time.Since(startTime).String()
+
+	if !params.AbsolutePaths {
+		base := commonDir(params.FilePaths)
+		normalizeDiagnosticPaths(merged, "", base, false)
+	}
+	for _, diagnostics := range [][]types.TypeScriptError{merged.Errors, merged.Warnings} {
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			return lessPosition(diagnostics[i].File, diagnostics[i].Line, diagnostics[i].Column, diagnostics[j].File, diagnostics[j].Line, diagnostics[j].Column)
+		})
+	}
+	limitDiagnostics(merged, params.MaxIssues)
+
+	return merged, nil
+}
+
+// lessPosition orders two source positions by file, then line, then column
+func lessPosition(fileA string, lineA, colA int, fileB string, lineB, colB int) bool {
+	if fileA != fileB {
+		return fileA < fileB
+	}
+	if lineA != lineB {
+		return lineA < lineB
+	}
+	return colA < colB
+}
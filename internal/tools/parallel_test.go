@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// lastArg is a shell snippet setting $file to the last argument, the file being checked
+const lastArg = `for arg; do file=$arg; done
+`
+
+// fakeTSCScript reports two diagnostics, out of order, for the file it checks. Files
+// named slow* take longer, so processes finish in a different order than they start.
+// tsc prints the file as it was named on the command line.
+const fakeTSCScript = lastArg + `case "$(basename "$file")" in slow*) sleep 0.2 ;; esac
+echo "$file(9,1): error TS2322: Type 'string' is not assignable to type 'number'."
+echo "$file(2,5): error TS7006: Parameter 'x' implicitly has an 'any' type."
+exit 2
+`
+
+// fakeESLintScript reports the same for ESLint, as its JSON report
+const fakeESLintScript = `if [ "$1" = "--version" ]; then echo v9.4.0; exit 0; fi
+` + lastArg + `case "$(basename "$file")" in slow*) sleep 0.2 ;; esac
+cat <<EOF
+[{"filePath":"$file","messages":[{"ruleId":"no-unused-vars","severity":2,"message":"unused","line":9,"column":1},{"ruleId":"eqeqeq","severity":1,"message":"use ===","line":2,"column":5}],"errorCount":1,"warningCount":1}]
+EOF
+exit 1
+`
+
+// parallelFixture creates files in separate directories, the first of them slow, and
+// installs script as the named binary above them
+func parallelFixture(t testing.TB, binary, script string, count int) []string {
+	t.Helper()
+	root := t.TempDir()
+	writeFakeBinary(t, root, binary, script)
+
+	files := make([]string, count)
+	for i := range files {
+		name := "fast.ts"
+		if i == 0 {
+			name = "slow.ts"
+		}
+		files[i] = writeFile(t, root, fmt.Sprintf("pkg%02d/%s", i, name), "export const value = 1;\n")
+	}
+	return files
+}
+
+func TestWorkerCount(t *testing.T) {
+	cpus := runtime.NumCPU()
+	tests := []struct {
+		concurrency, files, want int
+	}{
+		{0, 1000, cpus},
+		{1, 1000, 1},
+		{cpus + 10, 1000, cpus},
+		{0, 1, 1},
+		{4, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := workerCount(tt.concurrency, tt.files); got != tt.want {
+			t.Errorf("workerCount(%d, %d) = %d, want %d", tt.concurrency, tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestTypeCheckFilesMergesInOrder(t *testing.T) {
+	files := parallelFixture(t, "tsc", fakeTSCScript, 4)
+
+	var mu sync.Mutex
+	var progress []int
+	result, err := NewTypeScriptCompiler().TypeCheck(types.TypeCheckParams{
+		FilePaths:   files,
+		Concurrency: 4,
+		Progress: func(done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, done)
+			if total != len(files) {
+				t.Errorf("progress total = %d, want %d", total, len(files))
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("TypeCheck: %v", err)
+	}
+
+	if result.Success || len(result.Errors) != 8 || result.Total != 8 {
+		t.Fatalf("result = %+v, want 8 errors from a failed check", result)
+	}
+	if result.CountsByCode["TS2322"] != 4 || result.CountsByCode["TS7006"] != 4 {
+		t.Errorf("CountsByCode = %v, want 4 of each code", result.CountsByCode)
+	}
+	for i, diagnostic := range result.Errors {
+		wantFile, _ := filepath.Rel(filepath.Dir(filepath.Dir(files[0])), files[i/2])
+		wantLine := []int{2, 9}[i%2]
+		if diagnostic.File != wantFile || diagnostic.Line != wantLine {
+			t.Errorf("Errors[%d] = %s:%d, want %s:%d", i, diagnostic.File, diagnostic.Line, wantFile, wantLine)
+		}
+	}
+	if !sort.IntsAreSorted(progress) || len(progress) != len(files) || progress[len(progress)-1] != len(files) {
+		t.Errorf("progress = %v, want 1 to %d in order", progress, len(files))
+	}
+}
+
+func TestLintFilesMergesInOrder(t *testing.T) {
+	t.Setenv("ESLINT_USE_FLAT_CONFIG", "")
+	files := parallelFixture(t, "eslint", fakeESLintScript, 4)
+
+	result, err := NewESLintTool().LintCheck(types.LintCheckParams{FilePaths: files, AbsolutePaths: true})
+	if err != nil {
+		t.Fatalf("LintCheck: %v", err)
+	}
+
+	if result.Success || result.TotalIssues != 8 || result.ErrorCount != 4 || result.WarningCount != 4 {
+		t.Fatalf("result = %+v, want 4 errors and 4 warnings from a failed run", result)
+	}
+	if result.ConfigFormat != "flat" {
+		t.Errorf("ConfigFormat = %q, want flat", result.ConfigFormat)
+	}
+	for i, issue := range result.Issues {
+		wantLine := []int{2, 9}[i%2]
+		if issue.File != files[i/2] || issue.Line != wantLine {
+			t.Errorf("Issues[%d] = %s:%d, want %s:%d", i, issue.File, issue.Line, files[i/2], wantLine)
+		}
+	}
+}
+
+// benchmarkTypeCheckFiles type checks 16 files, each taking a fake tsc 0.2s, with the
+// given concurrency
+func benchmarkTypeCheckFiles(b *testing.B, concurrency int) {
+	slowScript := lastArg + "sleep 0.2\necho \"$file(1,1): error TS2322: Type mismatch.\"\nexit 2\n"
+	files := parallelFixture(b, "tsc", slowScript, 16)
+	tsc := NewTypeScriptCompiler()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := tsc.TypeCheck(types.TypeCheckParams{FilePaths: files, Concurrency: concurrency})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result.Errors) != len(files) {
+			b.Fatalf("got %d errors, want %d", len(result.Errors), len(files))
+		}
+	}
+}
+
+func BenchmarkTypeCheckFilesSerial(b *testing.B) {
+	benchmarkTypeCheckFiles(b, 1)
+}
+
+func BenchmarkTypeCheckFilesParallel(b *testing.B) {
+	benchmarkTypeCheckFiles(b, 0)
+}
+
+// benchmarkLintFiles lints 16 files with the fake ESLint with the given concurrency
+func benchmarkLintFiles(b *testing.B, concurrency int) {
+	files := parallelFixture(b, "eslint", fakeESLintScript, 16)
+	eslint := NewESLintTool()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := eslint.LintCheck(types.LintCheckParams{FilePaths: files, Concurrency: concurrency}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLintFilesSerial(b *testing.B) {
+	benchmarkLintFiles(b, 1)
+}
+
+func BenchmarkLintFilesParallel(b *testing.B) {
+	benchmarkLintFiles(b, 0)
+}
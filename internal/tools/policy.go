@@ -0,0 +1,38 @@
+package tools
+
+import "fmt"
+
+// Severity thresholds accepted by the FailOn parameter
+const (
+	FailOnError   = "error"
+	FailOnWarning = "warning"
+	FailOnAny     = "any"
+)
+
+// validateFailOn checks that failOn is empty or a known threshold
+func validateFailOn(failOn string) error {
+	switch failOn {
+	case "", FailOnError, FailOnWarning, FailOnAny:
+		return nil
+	default:
+		return fmt.Errorf("unknown fail_on %q: use %q, %q or %q", failOn, FailOnError, FailOnWarning, FailOnAny)
+	}
+}
+
+// passesFailOn reports whether the given error and warning counts stay below the
+// failOn threshold. tsc and ESLint only report errors and warnings, so "any" and
+// "warning" currently fail on the same diagnostics.
+func passesFailOn(failOn string, errors, warnings int) bool {
+	switch failOn {
+	case FailOnWarning, FailOnAny:
+		return errors == 0 && warnings == 0
+	default:
+		return errors == 0
+	}
+}
+
+// exceedsMaxWarnings reports whether warnings is above maxWarnings; nil or negative
+// means no limit
+func exceedsMaxWarnings(maxWarnings *int, warnings int) bool {
+	return maxWarnings != nil && *maxWarnings >= 0 && warnings > *maxWarnings
+}
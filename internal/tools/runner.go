@@ -0,0 +1,427 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Supported package managers used to run project binaries
+const (
+	PackageManagerNPM  = "npm"
+	PackageManagerPNPM = "pnpm"
+	PackageManagerYarn = "yarn"
+)
+
+// Option configures how a tool locates and runs its binary
+type Option func(*runner)
+
+// WithPackageManager forces a package manager ("npm", "pnpm" or "yarn") instead of
+// detecting it from the lockfile
+func WithPackageManager(packageManager string) Option {
+	return func(r *runner) {
+		r.packageManager = packageManager
+	}
+}
+
+// WithProjectRoot sets the directory used to resolve binaries when a call has no
+// project or file location of its own
+func WithProjectRoot(root string) Option {
+	return func(r *runner) {
+		r.projectRoot = root
+	}
+}
+
+// WithCacheDir sets the directory where tool caches such as .tsbuildinfo files are kept
+func WithCacheDir(dir string) Option {
+	return func(r *runner) {
+		r.cacheDir = dir
+	}
+}
+
+// WithTimeout bounds how long each command may run; zero means no limit
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *runner) {
+		r.timeout = timeout
+	}
+}
+
+// WithRetries sets how often a diagnostic command such as a type check or lint run is
+// retried after a transient npx or network failure; zero disables retries
+func WithRetries(retries int) Option {
+	return func(r *runner) {
+		r.retries = retries
+	}
+}
+
+// WithNodeOptions adds options such as --max-old-space-size=8192 to NODE_OPTIONS for
+// every command, after any inherited from the server's environment
+func WithNodeOptions(nodeOptions string) Option {
+	return func(r *runner) {
+		r.nodeOptions = nodeOptions
+	}
+}
+
+// WithEnv sets extra environment variables for every command, overriding the ones
+// inherited from the server
+func WithEnv(env map[string]string) Option {
+	return func(r *runner) {
+		r.env = env
+	}
+}
+
+// WithTSConfig sets the tsconfig file used in project mode, relative to the project root
+func WithTSConfig(name string) Option {
+	return func(r *runner) {
+		r.tsconfig = name
+	}
+}
+
+// runner resolves how to invoke a Node.js binary such as tsc or eslint
+type runner struct {
+	binary         string
+	packageManager string
+	projectRoot    string
+	cacheDir       string
+	timeout        time.Duration
+	retries        int
+	tsconfig       string
+	nodeOptions    string
+	env            map[string]string
+	// ctx kills running commands once it is done (never when nil)
+	ctx context.Context
+}
+
+// probeRetries is how often commands that don't report diagnostics, such as --version,
+// are retried after a transient failure. They can't legitimately exit non-zero, so
+// unlike diagnostic commands they are always retried.
+const probeRetries = 2
+
+// killWaitDelay is how long a killed command's output is still read before it is abandoned
+const killWaitDelay = 2 * time.Second
+
+// retryBackoff is the delay before the first retry; it doubles with each attempt
+const retryBackoff = 500 * time.Millisecond
+
+// transientMarkers appear in the output of npx and the package managers when resolving
+// or downloading a package fails for reasons that a retry can fix
+var transientMarkers = []string{
+	"ETIMEDOUT",
+	"ECONNRESET",
+	"ECONNREFUSED",
+	"ENOTFOUND",
+	"EAI_AGAIN",
+	"socket hang up",
+	"npm ERR! network",
+	"npm error network",
+}
+
+// newRunner creates a runner for binary with the given options applied
+func newRunner(binary string, opts ...Option) runner {
+	r := runner{binary: binary}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// cachePath returns a path inside the server cache directory for name, creating
+// the directory if needed. The user's cache directory is used unless overridden.
+func (r runner) cachePath(name string) (string, error) {
+	dir := r.cacheDir
+	if dir == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			userCache = os.TempDir()
+		}
+		dir = filepath.Join(userCache, "mcp-typescript-assistant")
+	}
+
+	dir = filepath.Join(dir, r.binary)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// command builds an exec.Cmd that runs the binary with args for a project in dir.
+// The process is killed once the runner's timeout elapses.
+func (r runner) command(dir string, args ...string) *exec.Cmd {
+	name, prefix := r.resolve(dir)
+	return r.exec(name, append(prefix, args...)...)
+}
+
+// exec builds an exec.Cmd for name that is killed once the runner's context is done or
+// its timeout elapses, and runs with the runner's extra environment
+func (r runner) exec(name string, args ...string) *exec.Cmd {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var cmd *exec.Cmd
+	if r.timeout <= 0 {
+		cmd = exec.CommandContext(ctx, name, args...)
+	} else {
+		// The context can't be cancelled when the command finishes since callers run it
+		// themselves, so it is released when the timeout fires instead
+		ctx, cancel := context.WithTimeout(ctx, r.timeout)
+		time.AfterFunc(r.timeout, cancel)
+		cmd = exec.CommandContext(ctx, name, args...)
+	}
+	// Children of a killed npx or shell wrapper can keep its output pipes open
+	cmd.WaitDelay = killWaitDelay
+	cmd.Env = r.environ()
+	return cmd
+}
+
+// withContext returns a copy of the runner whose commands are killed once ctx is done.
+// A nil ctx leaves the runner unchanged.
+func (r runner) withContext(ctx context.Context) runner {
+	if ctx != nil {
+		r.ctx = ctx
+	}
+	return r
+}
+
+// withEnv returns a copy of the runner that adds nodeOptions and env to its own
+func (r runner) withEnv(nodeOptions string, env map[string]string) runner {
+	if nodeOptions != "" {
+		r.nodeOptions = strings.TrimSpace(r.nodeOptions + " " + nodeOptions)
+	}
+	if len(env) > 0 {
+		merged := make(map[string]string, len(r.env)+len(env))
+		for key, value := range r.env {
+			merged[key] = value
+		}
+		for key, value := range env {
+			merged[key] = value
+		}
+		r.env = merged
+	}
+	return r
+}
+
+// environ returns the environment for a command, or nil to inherit the server's
+// unchanged. Later entries win, so the extra variables override inherited ones.
+func (r runner) environ() []string {
+	if r.nodeOptions == "" && len(r.env) == 0 {
+		return nil
+	}
+
+	environ := os.Environ()
+	keys := make([]string, 0, len(r.env))
+	for key := range r.env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		environ = append(environ, key+"="+r.env[key])
+	}
+
+	if r.nodeOptions != "" {
+		nodeOptions := r.nodeOptions
+		if inherited := envValue(environ, "NODE_OPTIONS"); inherited != "" {
+			nodeOptions = inherited + " " + nodeOptions
+		}
+		environ = append(environ, "NODE_OPTIONS="+nodeOptions)
+	}
+	return environ
+}
+
+// getenv returns the value of key in the environment commands run with
+func (r runner) getenv(key string) string {
+	if environ := r.environ(); environ != nil {
+		return envValue(environ, key)
+	}
+	return os.Getenv(key)
+}
+
+// envValue returns the last value of key in environ
+func envValue(environ []string, key string) string {
+	value := ""
+	for _, entry := range environ {
+		if name, v, ok := strings.Cut(entry, "="); ok && name == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// probe runs the binary with args from dir and returns its standard output, retrying
+// transient failures
+func (r runner) probe(dir string, args ...string) ([]byte, error) {
+	return r.retry(dir, probeRetries, func() ([]byte, error) {
+		return r.command(dir, args...).Output()
+	})
+}
+
+// retry calls run until it succeeds, fails for a reason other than a transient npx or
+// network error, or has been retried retries times, backing off exponentially between
+// attempts. run must build a new command on each call. Binaries that are run directly
+// rather than through npx or a package manager are never retried.
+func (r runner) retry(dir string, retries int, run func() ([]byte, error)) ([]byte, error) {
+	if _, prefix := r.resolve(dir); len(prefix) == 0 {
+		retries = 0
+	}
+
+	delay := retryBackoff
+	for attempt := 0; ; attempt++ {
+		output, err := run()
+		if err == nil || attempt >= retries || !isTransientFailure(output, err) || r.ctx != nil && r.ctx.Err() != nil {
+			return output, err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isTransientFailure reports whether a failed command looks like npx or the package
+// manager couldn't fetch the binary, rather than the binary itself exiting non-zero.
+// Timeouts aren't retried since another attempt would likely time out too.
+func isTransientFailure(output []byte, err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || errors.Is(err, context.DeadlineExceeded) || !exitErr.Exited() {
+		return false
+	}
+
+	text := string(output) + string(exitErr.Stderr)
+	for _, marker := range transientMarkers {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe returns the command line used to invoke the binary from dir
+func (r runner) describe(dir string) string {
+	name, prefix := r.resolve(dir)
+	return strings.Join(append([]string{name}, prefix...), " ")
+}
+
+// resolve returns the executable and leading arguments used to run the binary.
+// A project-local node_modules/.bin install is preferred, then the configured or
+// detected package manager, then npx, then a global install.
+func (r runner) resolve(dir string) (string, []string) {
+	if dir == "" {
+		dir = r.projectRoot
+	}
+
+	if path := findLocalBinary(dir, r.binary); path != "" {
+		return path, nil
+	}
+
+	packageManager := r.packageManager
+	if packageManager == "" {
+		packageManager = detectPackageManager(dir)
+	}
+
+	switch packageManager {
+	case PackageManagerPNPM:
+		if path, err := exec.LookPath("pnpm"); err == nil {
+			return path, []string{"exec", r.binary}
+		}
+	case PackageManagerYarn:
+		if path, err := exec.LookPath("yarn"); err == nil {
+			return path, []string{r.binary}
+		}
+	}
+
+	if path, err := exec.LookPath("npx"); err == nil {
+		return path, []string{r.binary}
+	}
+
+	return r.binary, nil
+}
+
+// findLocalBinary walks up from dir looking for node_modules/.bin/binary and
+// returns its path, or an empty string when there is no local install
+func findLocalBinary(dir, binary string) string {
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, "node_modules", ".bin", binary)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// detectPackageManager walks up from dir looking for a lockfile and returns the
+// package manager that owns it, defaulting to npm
+func detectPackageManager(dir string) string {
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return PackageManagerNPM
+	}
+
+	lockfiles := []struct {
+		name           string
+		packageManager string
+	}{
+		{"pnpm-lock.yaml", PackageManagerPNPM},
+		{"yarn.lock", PackageManagerYarn},
+		{"package-lock.json", PackageManagerNPM},
+	}
+
+	for {
+		for _, lockfile := range lockfiles {
+			if _, err := os.Stat(filepath.Join(dir, lockfile.name)); err == nil {
+				return lockfile.packageManager
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return PackageManagerNPM
+		}
+		dir = parent
+	}
+}
+
+// normalizePath returns p relative to base using forward slashes, so results don't
+// depend on where the server runs. Relative paths are resolved against the current
+// directory; p is returned unchanged if it can't be made relative.
+func normalizePath(base, p string) string {
+	if base == "" || p == "" {
+		return p
+	}
+
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return p
+	}
+	absPath, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+
+	relPath, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return p
+	}
+	return filepath.ToSlash(relPath)
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scratchPrefix names the per-server scratch roots inside the system temp directory
+const scratchPrefix = "mcp-typescript-assistant-"
+
+// staleScratchAge is how long a scratch root of another server must go unmodified
+// before CleanStaleScratch considers it abandoned
+const staleScratchAge = 24 * time.Hour
+
+// scratchRoot returns this server's scratch root, named after its process ID. It is
+// (re)created with owner-only permissions on every call, so a root removed while the
+// server runs doesn't break later requests.
+func scratchRoot() (string, error) {
+	root := filepath.Join(os.TempDir(), scratchPrefix+strconv.Itoa(os.Getpid()))
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	return root, nil
+}
+
+// NewScratchFile writes content to a new owner-only file with extension ext in the
+// scratch root. The file is removed by the returned cleanup function.
+func NewScratchFile(ext string, content []byte) (string, func(), error) {
+	root, err := scratchRoot()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// CreateTemp opens the file with 0600 permissions
+	file, err := os.CreateTemp(root, "snippet-*"+ext)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create scratch file: %w", err)
+	}
+	cleanup := func() { os.Remove(file.Name()) }
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write scratch file: %w", err)
+	}
+
+	return file.Name(), cleanup, nil
+}
+
+// newScratchNamedFile writes content to an owner-only file called name, inside a new
+// directory in the scratch root so the name can't collide. The directory is removed by
+// the returned cleanup function.
+func newScratchNamedFile(name string, content []byte) (string, func(), error) {
+	dir, err := NewScratchDir("file-content-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	path := filepath.Join(dir, filepath.Base(name))
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write scratch file: %w", err)
+	}
+
+	return path, cleanup, nil
+}
+
+// NewScratchDir creates a new owner-only directory in the scratch root, named after
+// pattern as in os.MkdirTemp. Removing it is up to the caller.
+func NewScratchDir(pattern string) (string, error) {
+	root, err := scratchRoot()
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp(root, pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	return dir, nil
+}
+
+// RemoveScratch removes this server's scratch root and everything in it, for use when
+// the server shuts down
+func RemoveScratch() error {
+	return os.RemoveAll(filepath.Join(os.TempDir(), scratchPrefix+strconv.Itoa(os.Getpid())))
+}
+
+// CleanStaleScratch removes the scratch roots left behind by servers that crashed or
+// were killed, i.e. those of other processes that haven't changed in staleScratchAge.
+// It returns how many roots were removed.
+func CleanStaleScratch() (int, error) {
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list temp directory: %w", err)
+	}
+
+	own := scratchPrefix + strconv.Itoa(os.Getpid())
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(name, scratchPrefix) || name == own {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < staleScratchAge {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(os.TempDir(), name)); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// Supported test runners
+const (
+	TestRunnerJest   = "jest"
+	TestRunnerVitest = "vitest"
+)
+
+// maxFailureMessage caps the length of a failure message, since stack traces can be long
+const maxFailureMessage = 2000
+
+// TestRunner runs a project's jest or vitest suite and parses the results
+type TestRunner struct {
+	opts        []Option
+	projectRoot string
+}
+
+// NewTestRunner creates a new test runner instance
+func NewTestRunner(opts ...Option) *TestRunner {
+	return &TestRunner{
+		opts:        opts,
+		projectRoot: newRunner("", opts...).projectRoot,
+	}
+}
+
+// jestReport is the JSON report written by jest --json and vitest --reporter=json
+type jestReport struct {
+	Success         bool             `json:"success"`
+	NumPassedTests  int              `json:"numPassedTests"`
+	NumFailedTests  int              `json:"numFailedTests"`
+	NumPendingTests int              `json:"numPendingTests"`
+	NumTodoTests    int              `json:"numTodoTests"`
+	NumTotalTests   int              `json:"numTotalTests"`
+	TestResults     []jestFileResult `json:"testResults"`
+}
+
+// jestFileResult holds the results for one test file
+type jestFileResult struct {
+	Name             string                `json:"name"`
+	Status           string                `json:"status"`
+	Message          string                `json:"message"`
+	AssertionResults []jestAssertionResult `json:"assertionResults"`
+}
+
+// jestAssertionResult holds the result of a single test
+type jestAssertionResult struct {
+	FullName        string   `json:"fullName"`
+	Title           string   `json:"title"`
+	Status          string   `json:"status"`
+	FailureMessages []string `json:"failureMessages"`
+	Location        *struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"location"`
+}
+
+// stackLocationRegex matches a file:line:column location in a stack trace
+var stackLocationRegex = regexp.MustCompile(`\(?([^\s()]+):(\d+):(\d+)\)?`)
+
+// RunTests runs the project's test suite, optionally scoped to a file and a test name pattern
+func (tr *TestRunner) RunTests(params types.RunTestsParams) (*types.TestResult, error) {
+	startTime := time.Now()
+
+	// The runner executes from the project root, so the file must not depend on our working directory
+	if params.FilePath != "" {
+		absPath, err := filepath.Abs(params.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve file path: %w", err)
+		}
+		params.FilePath = absPath
+	}
+
+	projectRoot := params.ProjectRoot
+	if projectRoot == "" && params.FilePath != "" {
+		projectRoot = findPackageRoot(filepath.Dir(params.FilePath))
+	}
+	if projectRoot == "" {
+		projectRoot = tr.projectRoot
+	}
+	if projectRoot == "" {
+		return nil, fmt.Errorf("running tests requires a project_root or a file_path inside a project")
+	}
+
+	runnerName := params.Runner
+	if runnerName == "" {
+		runnerName = detectTestRunner(projectRoot)
+	}
+
+	var args []string
+	switch runnerName {
+	case TestRunnerJest:
+		args = []string{"--json", "--testLocationInResults"}
+		if params.TestNamePattern != "" {
+			args = append(args, "--testNamePattern", params.TestNamePattern)
+		}
+		if params.FilePath != "" {
+			args = append(args, "--runTestsByPath", params.FilePath)
+		}
+	case TestRunnerVitest:
+		args = []string{"run", "--reporter=json", "--includeTaskLocation"}
+		if params.TestNamePattern != "" {
+			args = append(args, "--testNamePattern", params.TestNamePattern)
+		}
+		if params.FilePath != "" {
+			args = append(args, params.FilePath)
+		}
+	case "":
+		return nil, fmt.Errorf("no test runner found in %s: add jest or vitest to package.json", projectRoot)
+	default:
+		return nil, fmt.Errorf("unsupported test runner %q: use \"jest\" or \"vitest\"", runnerName)
+	}
+
+	r := newRunner(runnerName, tr.opts...)
+	cmd := r.command(projectRoot, args...)
+	cmd.Dir = projectRoot
+
+	// Both runners write the JSON report to stdout and progress to stderr
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, &types.ToolUnavailableError{
+			Tool:    runnerName,
+			Install: "npm install --save-dev " + runnerName,
+			Err:     err,
+		}
+	}
+
+	result := &types.TestResult{
+		Runner:   runnerName,
+		Binary:   r.describe(projectRoot),
+		Duration: time.Since(startTime).String(),
+	}
+
+	report, parseErr := parseJestReport(output)
+	if parseErr != nil {
+		// No report usually means the runner failed to start, e.g. a config error
+		result.Success = false
+		result.RawOutput = strings.TrimSpace(string(output) + "\n" + stderr.String())
+		result.Summary = fmt.Sprintf("%s did not produce a JSON report: %v", runnerName, parseErr)
+		return result, nil
+	}
+
+	result.Success = report.Success && err == nil
+	result.Passed = report.NumPassedTests
+	result.Failed = report.NumFailedTests
+	result.Skipped = report.NumPendingTests + report.NumTodoTests
+	result.Total = report.NumTotalTests
+	result.Failures = collectTestFailures(report, projectRoot)
+	result.Summary = fmt.Sprintf("%d passed, %d failed, %d skipped of %d tests", result.Passed, result.Failed, result.Skipped, result.Total)
+
+	return result, nil
+}
+
+// parseJestReport decodes the JSON report, skipping any text printed before it
+func parseJestReport(output []byte) (*jestReport, error) {
+	start := strings.Index(string(output), "{")
+	if start < 0 {
+		return nil, fmt.Errorf("no JSON output")
+	}
+
+	var report jestReport
+	if err := json.NewDecoder(strings.NewReader(string(output[start:]))).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse test report: %w", err)
+	}
+	return &report, nil
+}
+
+// collectTestFailures lists every failed test with its file and, when known, line and column.
+// Test files that failed to run at all (e.g. a syntax error) are reported as one failure.
+func collectTestFailures(report *jestReport, projectRoot string) []types.TestFailure {
+	var failures []types.TestFailure
+
+	for _, fileResult := range report.TestResults {
+		file := normalizePath(projectRoot, fileResult.Name)
+
+		if fileResult.Status == "failed" && len(fileResult.AssertionResults) == 0 {
+			failures = append(failures, types.TestFailure{
+				File:    file,
+				Test:    "(test file failed to run)",
+				Message: truncateMessage(fileResult.Message),
+			})
+			continue
+		}
+
+		for _, assertion := range fileResult.AssertionResults {
+			if assertion.Status != "failed" {
+				continue
+			}
+
+			failure := types.TestFailure{
+				File:    file,
+				Test:    assertion.FullName,
+				Message: truncateMessage(strings.Join(assertion.FailureMessages, "\n")),
+			}
+			if failure.Test == "" {
+				failure.Test = assertion.Title
+			}
+
+			if assertion.Location != nil {
+				failure.Line = assertion.Location.Line
+				failure.Column = assertion.Location.Column
+			} else {
+				failure.Line, failure.Column = stackLocation(failure.Message, fileResult.Name)
+			}
+
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures
+}
+
+// stackLocation finds the first stack frame in message that points into file
+func stackLocation(message, file string) (int, int) {
+	for _, matches := range stackLocationRegex.FindAllStringSubmatch(message, -1) {
+		if !strings.HasSuffix(file, strings.TrimPrefix(matches[1], "file://")) {
+			continue
+		}
+		line, _ := strconv.Atoi(matches[2])
+		column, _ := strconv.Atoi(matches[3])
+		return line, column
+	}
+	return 0, 0
+}
+
+// truncateMessage shortens message to maxFailureMessage bytes
+func truncateMessage(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) > maxFailureMessage {
+		return message[:maxFailureMessage] + "..."
+	}
+	return message
+}
+
+// findPackageRoot walks up from dir to the nearest directory containing a package.json
+func findPackageRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "package.json")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// detectTestRunner picks vitest or jest from the project's package.json scripts and
+// dependencies, falling back to whichever binary is installed locally
+func detectTestRunner(projectRoot string) string {
+	var manifest struct {
+		Scripts         map[string]string `json:"scripts"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+
+	if data, err := os.ReadFile(filepath.Join(projectRoot, "package.json")); err == nil && json.Unmarshal(data, &manifest) == nil {
+		testScript := manifest.Scripts["test"]
+		for _, name := range []string{TestRunnerVitest, TestRunnerJest} {
+			if strings.Contains(testScript, name) {
+				return name
+			}
+		}
+		for _, name := range []string{TestRunnerVitest, TestRunnerJest} {
+			if _, ok := manifest.DevDependencies[name]; ok {
+				return name
+			}
+			if _, ok := manifest.Dependencies[name]; ok {
+				return name
+			}
+		}
+	}
+
+	for _, name := range []string{TestRunnerVitest, TestRunnerJest} {
+		if findLocalBinary(projectRoot, name) != "" {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -1,124 +1,827 @@
 package tools
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"mcp-typescript-assistant/internal/paths"
 	"mcp-typescript-assistant/pkg/types"
 )
 
+var (
+	// TypeScript error format: file(line,column): error TS####: message
+	errorRegex = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\):\s+(error|warning)\s+TS(\d+):\s+(.+)$`)
+	// Project-level diagnostics (common in build mode) have no file location
+	globalRegex = regexp.MustCompile(`^(error|warning)\s+TS(\d+):\s+(.+)$`)
+
+	compositeRegex  = regexp.MustCompile(`"composite"\s*:\s*true`)
+	referencesRegex = regexp.MustCompile(`"references"\s*:\s*\[\s*\{`)
+	// Build mode format with --verbose: [time] Building project '/path/tsconfig.json'...
+	buildProjectRegex = regexp.MustCompile(`Building project '([^']+)'`)
+)
+
 // TypeScriptCompiler provides TypeScript compilation and type checking capabilities
 type TypeScriptCompiler struct {
-	tscPath string
+	runner runner
 }
 
 // NewTypeScriptCompiler creates a new TypeScript compiler instance
-func NewTypeScriptCompiler() *TypeScriptCompiler {
-	tscPath := "tsc"
-	if path, err := exec.LookPath("npx"); err == nil {
-		tscPath = path
-	}
-	return &TypeScriptCompiler{tscPath: tscPath}
+func NewTypeScriptCompiler(opts ...Option) *TypeScriptCompiler {
+	return &TypeScriptCompiler{runner: newRunner("tsc", opts...)}
 }
 
 // TypeCheck performs TypeScript type checking on a file or project
 func (tsc *TypeScriptCompiler) TypeCheck(params types.TypeCheckParams) (*types.TypeCheckResult, error) {
-	startTime := time.Now()
+	if err := validateFailOn(params.FailOn); err != nil {
+		return nil, err
+	}
+	if params.NodeOptions != "" || len(params.Env) > 0 {
+		tsc = &TypeScriptCompiler{runner: tsc.runner.withEnv(params.NodeOptions, params.Env)}
+	}
+	if params.Context != nil {
+		tsc = &TypeScriptCompiler{runner: tsc.runner.withContext(params.Context)}
+	}
+
+	result, err := tsc.typeCheck(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors are kept ahead of warnings when diagnostics are truncated, so the
+	// remaining counts still decide the policy. A tsc failure without diagnostics
+	// can't be judged and never passes.
+	result.Passed = result.RawOutput == "" && passesFailOn(params.FailOn, len(result.Errors), len(result.Warnings))
+	return result, nil
+}
 
-	var args []string
-	if tsc.tscPath == "npx" {
-		args = append(args, "tsc")
+// typeCheck type checks a file or project
+func (tsc *TypeScriptCompiler) typeCheck(params types.TypeCheckParams) (*types.TypeCheckResult, error) {
+	if params.ChangedOnly {
+		return tsc.typeCheckChanged(params)
+	}
+	if len(params.FilePaths) > 0 {
+		return tsc.typeCheckFiles(params)
+	}
+	if params.FileContent != "" {
+		return tsc.typeCheckContent(params)
 	}
 
-	args = append(args, "--noEmit", "--pretty", "false")
+	if params.CodeSnippet != "" && params.FilePath == "" && params.ProjectRoot == "" {
+		if strings.TrimSpace(params.CodeSnippet) == "" {
+			return nil, fmt.Errorf("no code provided: code_snippet is empty")
+		}
+		snippetFile, cleanup, err := NewScratchFile(paths.ExtensionFor(params.Language), []byte(params.CodeSnippet))
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		params.FilePath = snippetFile
+	} else if params.FilePath != "" && params.ProjectRoot == "" {
+		if err := checkFilePath(params.FilePath, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Build {
+		if params.ProjectRoot == "" {
+			return nil, fmt.Errorf("build mode requires a project_root")
+		}
+		configPath := tsc.ConfigPath(params.ProjectRoot)
+		if tsc.isCompositeProject(configPath) {
+			result, err := tsc.buildCheck(params.ProjectRoot, configPath)
+			if err != nil {
+				return nil, err
+			}
+			normalizeDiagnosticPaths(result, params.ProjectRoot, params.ProjectRoot, params.AbsolutePaths)
+			limitDiagnostics(result, params.MaxIssues)
+			return result, nil
+		}
+	}
+
+	startTime := time.Now()
+
+	args := []string{"--noEmit", "--pretty", "false"}
+
+	cacheUsed := false
+	if params.Incremental {
+		buildInfoFile, err := tsc.buildInfoPath(params)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(buildInfoFile); err == nil {
+			cacheUsed = true
+		}
+		args = append(args, "--incremental", "--tsBuildInfoFile", buildInfoFile)
+	}
 
 	if params.ProjectRoot != "" {
 		// Check for project compilation
-		configPath := filepath.Join(params.ProjectRoot, "tsconfig.json")
+		configPath := tsc.ConfigPath(params.ProjectRoot)
 		args = append(args, "--project", configPath)
 	} else {
-		// Single file compilation
+		// Single file compilation has no tsconfig, so JSX support must be enabled explicitly
+		if filepath.Ext(params.FilePath) == ".tsx" {
+			args = append(args, "--jsx", "preserve")
+		}
 		args = append(args, params.FilePath)
 	}
 
-	cmd := exec.Command(tsc.tscPath, args...)
+	workDir := filepath.Dir(params.FilePath)
 	if params.ProjectRoot != "" {
-		cmd.Dir = params.ProjectRoot
+		workDir = params.ProjectRoot
 	}
 
-	output, err := cmd.CombinedOutput()
+	output, err := tsc.runner.retry(workDir, tsc.runner.retries, func() ([]byte, error) {
+		cmd := tsc.runner.command(workDir, args...)
+		if params.ProjectRoot != "" {
+			cmd.Dir = params.ProjectRoot
+		}
+		return cmd.CombinedOutput()
+	})
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, tscUnavailable(err)
+	}
+	compileTime := time.Since(startTime).String()
+
+	result := &types.TypeCheckResult{
+		Success:              err == nil,
+		CompileTime:          compileTime,
+		Mode:                 "noEmit",
+		Binary:               tsc.runner.describe(workDir),
+		IncrementalCacheUsed: cacheUsed,
+	}
+
+	if len(output) > 0 {
+		errors, warnings, counts := tsc.parseTypeScriptOutput(string(output))
+		result.Errors = errors
+		result.Warnings = warnings
+		result.CountsByCode = counts
+		result.Total = len(errors) + len(warnings)
+	}
+
+	tsc.recordFailure(result, output, err)
+
+	// tsc prints paths relative to the directory it ran in
+	runDir, base := "", filepath.Dir(params.FilePath)
+	if params.ProjectRoot != "" {
+		runDir, base = params.ProjectRoot, params.ProjectRoot
+	}
+	normalizeDiagnosticPaths(result, runDir, base, params.AbsolutePaths)
+	limitDiagnostics(result, params.MaxIssues)
+
+	return result, nil
+}
+
+// typeCheckChanged type checks the whole project, so its tsconfig applies, and keeps only
+// the diagnostics in files changed since params.BaseRef
+func (tsc *TypeScriptCompiler) typeCheckChanged(params types.TypeCheckParams) (*types.TypeCheckResult, error) {
+	if params.ProjectRoot == "" {
+		return nil, fmt.Errorf("changed_only requires a project_root")
+	}
+	if params.FilePath != "" || len(params.FilePaths) > 0 || params.FileContent != "" || params.CodeSnippet != "" {
+		return nil, fmt.Errorf("changed_only can't be combined with file_path, file_paths, file_content or code_snippet")
+	}
+
+	changed, err := ChangedFiles(params.ProjectRoot, params.BaseRef)
+	if err != nil {
+		return nil, err
+	}
+	changedFiles := make([]string, len(changed))
+	for i, file := range changed {
+		changedFiles[i] = normalizePath(params.ProjectRoot, file)
+	}
+	if len(changed) == 0 {
+		return &types.TypeCheckResult{Success: true, Mode: "noEmit", ChangedFiles: []string{}}, nil
+	}
+
+	projectParams := params
+	projectParams.ChangedOnly = false
+	projectParams.AbsolutePaths = true
+	projectParams.MaxIssues = math.MaxInt
+	result, err := tsc.TypeCheck(projectParams)
+	if err != nil {
+		return nil, err
+	}
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, file := range changed {
+		isChanged[file] = true
+	}
+	filter := func(diagnostics []types.TypeScriptError) []types.TypeScriptError {
+		var kept []types.TypeScriptError
+		for _, diagnostic := range diagnostics {
+			if isChanged[diagnostic.File] {
+				kept = append(kept, diagnostic)
+			}
+		}
+		return kept
+	}
+	result.Errors = filter(result.Errors)
+	result.Warnings = filter(result.Warnings)
+	result.Success = len(result.Errors) == 0
+	result.Total = len(result.Errors) + len(result.Warnings)
+	result.CountsByCode = make(map[string]int)
+	for _, diagnostics := range [][]types.TypeScriptError{result.Errors, result.Warnings} {
+		for _, diagnostic := range diagnostics {
+			result.CountsByCode[diagnostic.Code]++
+		}
+	}
+	result.ChangedFiles = changedFiles
+
+	normalizeDiagnosticPaths(result, "", params.ProjectRoot, params.AbsolutePaths)
+	limitDiagnostics(result, params.MaxIssues)
+	return result, nil
+}
+
+// TypeCheckDiff type checks a file or project and reports only the errors missing from a
+// baseline, given as a list of errors or computed by type checking a git ref
+func (tsc *TypeScriptCompiler) TypeCheckDiff(params types.TypeCheckDiffParams) (*types.TypeCheckDiffResult, error) {
+	if params.FilePath == "" && params.ProjectRoot == "" {
+		return nil, fmt.Errorf("either file_path or project_root is required")
+	}
+	if len(params.Baseline) == 0 && params.BaselineRef == "" {
+		return nil, fmt.Errorf("either baseline or baseline_ref is required")
+	}
+
+	checkParams := types.TypeCheckParams{
+		FilePath:    params.FilePath,
+		ProjectRoot: params.ProjectRoot,
+		MaxIssues:   math.MaxInt,
+	}
+	current, err := tsc.TypeCheck(checkParams)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := params.Baseline
+	if len(baseline) == 0 {
+		baseline, err = tsc.baselineErrors(checkParams, params.BaselineRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newErrors := diffDiagnostics(baseline, current.Errors)
+	result := &types.TypeCheckDiffResult{
+		Success:        len(newErrors) == 0,
+		NewErrors:      newErrors,
+		BaselineRef:    params.BaselineRef,
+		BaselineErrors: len(baseline),
+		CurrentErrors:  len(current.Errors),
+		FixedErrors:    len(diffDiagnostics(current.Errors, baseline)),
+	}
+
+	maxIssues := params.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = types.DefaultMaxIssues
+	}
+	if len(result.NewErrors) > maxIssues {
+		result.NewErrors = result.NewErrors[:maxIssues]
+		result.Truncated = true
+	}
+
+	result.Summary = fmt.Sprintf("%d new error(s), %d fixed (%d before, %d now)",
+		len(newErrors), result.FixedErrors, result.BaselineErrors, result.CurrentErrors)
+	return result, nil
+}
+
+// baselineErrors type checks the same file or project as params at a git ref
+func (tsc *TypeScriptCompiler) baselineErrors(params types.TypeCheckParams, ref string) ([]types.TypeScriptError, error) {
+	target := params.ProjectRoot
+	if target == "" {
+		target = params.FilePath
+	}
+
+	worktree, err := newGitWorktree(target, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer worktree.remove()
+
+	if params.ProjectRoot != "" {
+		if params.ProjectRoot, err = worktree.path(params.ProjectRoot); err != nil {
+			return nil, err
+		}
+	}
+	if params.FilePath != "" {
+		if params.FilePath, err = worktree.path(params.FilePath); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(params.FilePath); err != nil {
+			// The file is new since ref, so it had no errors
+			return nil, nil
+		}
+	}
+
+	result, err := tsc.TypeCheck(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to type check %s: %w", ref, err)
+	}
+	return result.Errors, nil
+}
+
+// diagnosticKey identifies a diagnostic independently of its line, so errors that only
+// moved because of edits elsewhere in the file still match
+func diagnosticKey(diagnostic types.TypeScriptError) string {
+	return diagnostic.File + "\x00" + diagnostic.Code + "\x00" + strings.TrimSpace(diagnostic.Message)
+}
+
+// diffDiagnostics returns the diagnostics in current that aren't in baseline. Diagnostics
+// with the same file, code and message are matched in line order, so the relative line
+// of an error among identical ones is what identifies it, and only extra occurrences
+// are reported as new.
+func diffDiagnostics(baseline, current []types.TypeScriptError) []types.TypeScriptError {
+	remaining := make(map[string]int)
+	for _, diagnostic := range baseline {
+		remaining[diagnosticKey(diagnostic)]++
+	}
+
+	sorted := make([]types.TypeScriptError, len(current))
+	copy(sorted, current)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+
+	// Surplus occurrences are the last ones in the file, which are the likeliest to be new
+	seen := make(map[string]int)
+	added := []types.TypeScriptError{}
+	for _, diagnostic := range sorted {
+		key := diagnosticKey(diagnostic)
+		seen[key]++
+		if seen[key] > remaining[key] {
+			added = append(added, diagnostic)
+		}
+	}
+	return added
+}
+
+// typeCheckContent type checks inline file content by writing it to a temporary file,
+// reporting diagnostics for it under the logical FilePath
+func (tsc *TypeScriptCompiler) typeCheckContent(params types.TypeCheckParams) (*types.TypeCheckResult, error) {
+	if params.CodeSnippet != "" || params.ProjectRoot != "" {
+		return nil, fmt.Errorf("file_content can't be combined with code_snippet or project_root")
+	}
+
+	logicalPath := contentName(params.FilePath)
+	contentFile, cleanup, err := writeFileContent(logicalPath, params.FileContent, params.ContentEncoding)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	params.FilePath = contentFile
+	params.FileContent = ""
+	result, err := tsc.TypeCheck(params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, diagnostics := range [][]types.TypeScriptError{result.Errors, result.Warnings} {
+		for i := range diagnostics {
+			if diagnostics[i].File == contentFile || diagnostics[i].File == filepath.Base(contentFile) {
+				diagnostics[i].File = logicalPath
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// limitDiagnostics keeps at most maxIssues errors and warnings, preferring errors.
+// Total, ErrorCount, WarningCount and CountsByCode still describe the full output.
+func limitDiagnostics(result *types.TypeCheckResult, maxIssues int) {
+	result.ErrorCount, result.WarningCount = len(result.Errors), len(result.Warnings)
+	if maxIssues <= 0 {
+		maxIssues = types.DefaultMaxIssues
+	}
+	if len(result.Errors)+len(result.Warnings) <= maxIssues {
+		return
+	}
+
+	result.Truncated = true
+	if len(result.Errors) > maxIssues {
+		result.Errors = result.Errors[:maxIssues]
+	}
+	result.Warnings = result.Warnings[:maxIssues-len(result.Errors)]
+}
+
+// normalizeDiagnosticPaths rewrites the file of each diagnostic to be relative to base,
+// or absolute when absolute is set. Relative paths are resolved against runDir first.
+func normalizeDiagnosticPaths(result *types.TypeCheckResult, runDir, base string, absolute bool) {
+	for _, diagnostics := range [][]types.TypeScriptError{result.Errors, result.Warnings} {
+		for i := range diagnostics {
+			file := diagnostics[i].File
+			if file == "" {
+				continue
+			}
+			if !filepath.IsAbs(file) && runDir != "" {
+				file = filepath.Join(runDir, file)
+			}
+			if absolute {
+				if absFile, err := filepath.Abs(file); err == nil {
+					file = absFile
+				}
+			} else {
+				file = normalizePath(base, file)
+			}
+			diagnostics[i].File = file
+		}
+	}
+}
+
+// buildInfoPath returns the server-managed .tsbuildinfo location for a project or file,
+// keeping incremental state out of the user's repository
+func (tsc *TypeScriptCompiler) buildInfoPath(params types.TypeCheckParams) (string, error) {
+	target := params.ProjectRoot
+	if target == "" {
+		target = params.FilePath
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(absTarget))
+	return tsc.runner.cachePath(hex.EncodeToString(hash[:8]) + ".tsbuildinfo")
+}
+
+// buildCheck runs tsc --build --noEmit for composite projects that use project
+// references, type checking every referenced project without writing outputs or
+// .tsbuildinfo into the user's tree. --noEmit with --build needs TypeScript 5.6 or later.
+func (tsc *TypeScriptCompiler) buildCheck(projectRoot, configPath string) (*types.TypeCheckResult, error) {
+	startTime := time.Now()
+
+	args := []string{"--build", "--noEmit", "--verbose", "--pretty", "false", configPath}
+
+	output, err := tsc.runner.retry(projectRoot, tsc.runner.retries, func() ([]byte, error) {
+		cmd := tsc.runner.command(projectRoot, args...)
+		cmd.Dir = projectRoot
+		return cmd.CombinedOutput()
+	})
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, tscUnavailable(err)
+	}
 	compileTime := time.Since(startTime).String()
 
 	result := &types.TypeCheckResult{
-		Success:     err == nil,
 		CompileTime: compileTime,
+		Mode:        "build",
+		Binary:      tsc.runner.describe(projectRoot),
 	}
 
 	if len(output) > 0 {
-		errors, warnings := tsc.parseTypeScriptOutput(string(output))
+		errors, warnings, counts := tsc.parseTypeScriptOutput(string(output))
 		result.Errors = errors
 		result.Warnings = warnings
+		result.CountsByCode = counts
+		result.Total = len(errors) + len(warnings)
+		result.OutdatedProjects = tsc.parseBuildOutput(string(output))
 	}
 
+	// tsc --build exits non-zero for any referenced project with diagnostics,
+	// so success also requires that no errors were reported
+	result.Success = err == nil && len(result.Errors) == 0
+
+	tsc.recordFailure(result, output, err)
+
 	return result, nil
 }
 
-// GetTypes extracts type information for a symbol in a TypeScript file
+// recordFailure attaches the exit code and raw output when tsc failed without
+// producing any diagnostics we could parse, e.g. on a bad flag or a crash
+func (tsc *TypeScriptCompiler) recordFailure(result *types.TypeCheckResult, output []byte, err error) {
+	if err == nil || len(result.Errors) > 0 {
+		return
+	}
+
+	result.ExitCode = -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	}
+
+	result.RawOutput = strings.TrimSpace(string(output))
+	if result.RawOutput == "" {
+		result.RawOutput = err.Error()
+	}
+}
+
+// isCompositeProject reports whether a tsconfig enables composite builds or declares project references
+func (tsc *TypeScriptCompiler) isCompositeProject(configPath string) bool {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return false
+	}
+
+	return compositeRegex.Match(data) || referencesRegex.Match(data)
+}
+
+// parseBuildOutput extracts the projects tsc --build --verbose checked because they were
+// out of date
+func (tsc *TypeScriptCompiler) parseBuildOutput(output string) []string {
+	var projects []string
+
+	for _, matches := range buildProjectRegex.FindAllStringSubmatch(output, -1) {
+		projects = append(projects, matches[1])
+	}
+
+	return projects
+}
+
+// Transpile runs the TypeScript compiler with emit enabled and reports the emitted files.
+// Output goes to a fresh temporary directory unless OutDir is given, so the user's
+// source tree is never written to implicitly.
+func (tsc *TypeScriptCompiler) Transpile(params types.TranspileParams) (*types.TranspileResult, error) {
+	startTime := time.Now()
+
+	outDir := params.OutDir
+	if outDir == "" {
+		tempDir, err := NewScratchDir("transpile-*")
+		if err != nil {
+			return nil, err
+		}
+		outDir = tempDir
+	}
+
+	args := []string{"--pretty", "false", "--listEmittedFiles", "--outDir", outDir}
+	if params.Target != "" {
+		args = append(args, "--target", params.Target)
+	}
+	if params.Module != "" {
+		args = append(args, "--module", params.Module)
+	}
+	args = append(args, params.FilePath)
+
+	dir := filepath.Dir(params.FilePath)
+	output, err := tsc.runner.retry(dir, tsc.runner.retries, func() ([]byte, error) {
+		return tsc.runner.command(dir, args...).CombinedOutput()
+	})
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, tscUnavailable(err)
+	}
+
+	result := &types.TranspileResult{
+		Success:     err == nil,
+		OutDir:      outDir,
+		CompileTime: time.Since(startTime).String(),
+	}
+
+	result.Errors, _, _ = tsc.parseTypeScriptOutput(string(output))
+
+	// --listEmittedFiles format: TSFILE: /path/to/file.js
+	for _, line := range strings.Split(string(output), "\n") {
+		path, ok := strings.CutPrefix(strings.TrimSpace(line), "TSFILE: ")
+		if !ok {
+			continue
+		}
+
+		emitted := types.EmittedFile{Path: path}
+		if params.IncludeContent {
+			if content, readErr := os.ReadFile(path); readErr == nil {
+				emitted.Content = string(content)
+			}
+		}
+		result.EmittedFiles = append(result.EmittedFiles, emitted)
+	}
+
+	return result, nil
+}
+
+// GetTypes extracts type information for a symbol, or for the position at Line and
+// Column, using the TypeScript language service
 func (tsc *TypeScriptCompiler) GetTypes(params types.GetTypesParams) (*types.TypeInfo, error) {
-	// This would ideally use the TypeScript Language Service API
-	// For now, we'll use a simplified approach with compilation output
+	query, err := newPositionQuery("types", types.SymbolPositionParams{
+		FilePath:        params.FilePath,
+		SymbolName:      params.SymbolName,
+		Line:            params.Line,
+		Column:          params.Column,
+		FileContent:     params.FileContent,
+		ContentEncoding: params.ContentEncoding,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer query.cleanup()
 
-	args := []string{"--noEmit", "--listFiles", params.FilePath}
-	if tsc.tscPath == "npx" {
-		args = append([]string{"tsc"}, args...)
+	var typeInfo types.TypeInfo
+	if err := tsc.queryLanguageService(query.request, &typeInfo); err != nil {
+		return nil, err
 	}
 
-	cmd := exec.Command(tsc.tscPath, args...)
-	_, err := cmd.CombinedOutput()
+	if typeInfo.Location != nil {
+		typeInfo.Location.File = query.logicalLocation(typeInfo.Location.File)
+	}
+	return &typeInfo, nil
+}
 
+// GetTypesBatch extracts type information for several symbols declared in one file with
+// a single language service run. A symbol that can't be resolved gets an entry with its
+// Error set instead of failing the whole batch.
+func (tsc *TypeScriptCompiler) GetTypesBatch(params types.GetTypesBatchParams) ([]types.TypeInfo, error) {
+	if len(params.SymbolNames) == 0 {
+		return nil, fmt.Errorf("symbol_names is required")
+	}
+
+	query, err := newPositionQuery("types_batch", types.SymbolPositionParams{
+		FilePath:        params.FilePath,
+		SymbolName:      params.SymbolNames[0],
+		FileContent:     params.FileContent,
+		ContentEncoding: params.ContentEncoding,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to analyze types: %w", err)
+		return nil, err
+	}
+	defer query.cleanup()
+	query.request.Symbols = params.SymbolNames
+
+	var typeInfos []types.TypeInfo
+	if err := tsc.queryLanguageService(query.request, &typeInfos); err != nil {
+		return nil, err
 	}
 
-	// For a more complete implementation, this would parse the TypeScript AST
-	// and extract detailed type information
-	typeInfo := &types.TypeInfo{
+	for i := range typeInfos {
+		if typeInfos[i].Location != nil {
+			typeInfos[i].Location.File = query.logicalLocation(typeInfos[i].Location.File)
+		}
+	}
+	return typeInfos, nil
+}
+
+// ResolveModule resolves an import specifier from a containing file with TypeScript's
+// module resolution and the project's tsconfig, reporting where the module's types are
+// declared. Paths are relative to the containing file's directory.
+func (tsc *TypeScriptCompiler) ResolveModule(params types.ResolveModuleParams) (*types.ResolveModuleResult, error) {
+	if params.Specifier == "" {
+		return nil, fmt.Errorf("specifier is required")
+	}
+	if params.FilePath == "" {
+		return nil, fmt.Errorf("file_path is required")
+	}
+
+	request := lsRequest{Op: "resolve_module", File: params.FilePath, Specifier: params.Specifier}
+	var result types.ResolveModuleResult
+	if err := tsc.queryLanguageService(request, &result); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(params.FilePath)
+	result.ResolvedPath = normalizePath(dir, result.ResolvedPath)
+	result.DeclarationPath = normalizePath(dir, result.DeclarationPath)
+	return &result, nil
+}
+
+// FindReferences lists every reference to a symbol, including its declarations
+func (tsc *TypeScriptCompiler) FindReferences(params types.SymbolPositionParams) (*types.LocationsResult, error) {
+	return tsc.findLocations("references", params)
+}
+
+// GoToDefinition lists where a symbol is defined
+func (tsc *TypeScriptCompiler) GoToDefinition(params types.SymbolPositionParams) (*types.LocationsResult, error) {
+	return tsc.findLocations("definition", params)
+}
+
+// RenameLocations computes the edits that rename a symbol across the project. Edit
+// files are absolute paths so that they can be applied from any directory.
+func (tsc *TypeScriptCompiler) RenameLocations(params types.RenameSymbolParams) (*types.RenameResult, error) {
+	query, err := newPositionQuery("rename", types.SymbolPositionParams{
+		FilePath:   params.FilePath,
 		SymbolName: params.SymbolName,
-		Type:       "unknown",  // Would be extracted from AST analysis
-		Kind:       "variable", // Would be determined from AST
-		Location: &types.SourceLocation{
-			File: params.FilePath,
-		},
+		Line:       params.Line,
+		Column:     params.Column,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer query.cleanup()
+	query.request.NewName = params.NewName
+
+	result := types.RenameResult{NewName: params.NewName}
+	if err := tsc.queryLanguageService(query.request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Complete lists the completion entries at a position, like an editor's autocomplete
+func (tsc *TypeScriptCompiler) Complete(params types.CompleteParams) (*types.CompletionResult, error) {
+	if params.Line <= 0 {
+		return nil, fmt.Errorf("line is required")
+	}
+	if params.CodeSnippet != "" {
+		if params.FileContent != "" {
+			return nil, fmt.Errorf("code_snippet can't be combined with file_content")
+		}
+		params.FileContent = params.CodeSnippet
+		if params.FilePath == "" {
+			params.FilePath = "snippet" + paths.ExtensionFor(params.Language)
+		}
 	}
 
-	if params.SymbolName == "" {
-		typeInfo.SymbolName = "file_analysis"
-		typeInfo.Documentation = fmt.Sprintf("Type analysis for file: %s", params.FilePath)
+	query, err := newPositionQuery("complete", types.SymbolPositionParams{
+		FilePath:        params.FilePath,
+		Line:            params.Line,
+		Column:          params.Column,
+		FileContent:     params.FileContent,
+		ContentEncoding: params.ContentEncoding,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer query.cleanup()
+
+	maxEntries := params.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = types.DefaultMaxCompletions
+	}
+	query.request.Prefix = params.Prefix
+	query.request.MaxEntries = maxEntries
+
+	var result types.CompletionResult
+	if err := tsc.queryLanguageService(query.request, &result); err != nil {
+		return nil, err
+	}
+	if result.Entries == nil {
+		result.Entries = []types.CompletionEntry{}
 	}
+	result.Truncated = result.Total > len(result.Entries)
 
-	return typeInfo, nil
+	return &result, nil
 }
 
-// parseTypeScriptOutput parses TypeScript compiler output into structured errors and warnings
-func (tsc *TypeScriptCompiler) parseTypeScriptOutput(output string) ([]types.TypeScriptError, []types.TypeScriptError) {
+// findLocations runs a navigation operation and returns its locations sorted by file and line
+func (tsc *TypeScriptCompiler) findLocations(op string, params types.SymbolPositionParams) (*types.LocationsResult, error) {
+	query, err := newPositionQuery(op, params)
+	if err != nil {
+		return nil, err
+	}
+	defer query.cleanup()
+
+	var result types.LocationsResult
+	if err := tsc.queryLanguageService(query.request, &result); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Locations {
+		result.Locations[i].File = query.logicalLocation(result.Locations[i].File)
+	}
+	sort.Slice(result.Locations, func(i, j int) bool {
+		a, b := result.Locations[i], result.Locations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	if result.Locations == nil {
+		result.Locations = []types.SourceLocation{}
+	}
+	result.Total = len(result.Locations)
+
+	return &result, nil
+}
+
+// parseTypeScriptOutput parses TypeScript compiler output into structured errors and warnings,
+// along with the number of diagnostics reported for each error code
+func (tsc *TypeScriptCompiler) parseTypeScriptOutput(output string) ([]types.TypeScriptError, []types.TypeScriptError, map[string]int) {
 	var errors []types.TypeScriptError
 	var warnings []types.TypeScriptError
+	counts := make(map[string]int)
 
-	// TypeScript error format: file(line,column): error TS####: message
-	errorRegex := regexp.MustCompile(`^(.+?)\((\d+),(\d+)\):\s+(error|warning)\s+TS(\d+):\s+(.+)$`)
+	// last points at the most recent diagnostic so that indented continuation
+	// lines (elaborations and related information) can be attached to it
+	var last *[]types.TypeScriptError
 
 	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for _, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
 		if line == "" {
 			continue
 		}
 
+		if last != nil && len(*last) > 0 && strings.TrimLeft(rawLine, " \t") != rawLine {
+			diagnostic := &(*last)[len(*last)-1]
+			diagnostic.Details = append(diagnostic.Details, line)
+			continue
+		}
+		last = nil
+
 		matches := errorRegex.FindStringSubmatch(line)
 		if len(matches) == 7 {
 			lineNum, _ := strconv.Atoi(matches[2])
@@ -135,44 +838,92 @@ func (tsc *TypeScriptCompiler) parseTypeScriptOutput(output string) ([]types.Typ
 				Code:     code,
 				Severity: severity,
 			}
+			counts[code]++
 
 			if severity == "error" {
 				errors = append(errors, tsError)
+				last = &errors
+			} else {
+				warnings = append(warnings, tsError)
+				last = &warnings
+			}
+			continue
+		}
+
+		matches = globalRegex.FindStringSubmatch(line)
+		if len(matches) == 4 {
+			tsError := types.TypeScriptError{
+				Message:  matches[3],
+				Code:     "TS" + matches[2],
+				Severity: matches[1],
+			}
+			counts[tsError.Code]++
+
+			if tsError.Severity == "error" {
+				errors = append(errors, tsError)
+				last = &errors
 			} else {
 				warnings = append(warnings, tsError)
+				last = &warnings
 			}
 		}
 	}
 
-	return errors, warnings
+	return errors, warnings, counts
 }
 
-// CheckTSCAvailable checks if TypeScript compiler is available
-func (tsc *TypeScriptCompiler) CheckTSCAvailable() error {
-	var cmd *exec.Cmd
-	if tsc.tscPath == "npx" {
-		cmd = exec.Command("npx", "tsc", "--version")
-	} else {
-		cmd = exec.Command("tsc", "--version")
+// ConfigPath returns the tsconfig file used for projectRoot, tsconfig.json unless configured otherwise
+func (tsc *TypeScriptCompiler) ConfigPath(projectRoot string) string {
+	name := tsc.runner.tsconfig
+	if name == "" {
+		name = "tsconfig.json"
 	}
+	return filepath.Join(projectRoot, name)
+}
 
-	_, err := cmd.Output()
+// ShowConfig parses the tsconfig in projectRoot with `tsc --showConfig`, returning
+// an error that includes tsc's output when the configuration is invalid
+func (tsc *TypeScriptCompiler) ShowConfig(projectRoot string) error {
+	output, err := tsc.runner.retry(projectRoot, probeRetries, func() ([]byte, error) {
+		cmd := tsc.runner.command(projectRoot, "--showConfig", "--project", tsc.ConfigPath(projectRoot))
+		cmd.Dir = projectRoot
+		return cmd.CombinedOutput()
+	})
+	if errors.Is(err, exec.ErrNotFound) {
+		return tscUnavailable(err)
+	}
 	if err != nil {
-		return fmt.Errorf("TypeScript compiler not available: %w", err)
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
 	}
 	return nil
 }
 
-// GetVersion returns the TypeScript compiler version
-func (tsc *TypeScriptCompiler) GetVersion() (string, error) {
-	var cmd *exec.Cmd
-	if tsc.tscPath == "npx" {
-		cmd = exec.Command("npx", "tsc", "--version")
-	} else {
-		cmd = exec.Command("tsc", "--version")
+// Path returns the command used to invoke the TypeScript compiler from the default project root
+func (tsc *TypeScriptCompiler) Path() string {
+	return tsc.runner.describe("")
+}
+
+// CheckTSCAvailable checks if TypeScript compiler is available
+func (tsc *TypeScriptCompiler) CheckTSCAvailable() error {
+	_, err := tsc.runner.probe("", "--version")
+	if err != nil {
+		return tscUnavailable(err)
 	}
+	return nil
+}
 
-	output, err := cmd.Output()
+// tscUnavailable wraps err in a ToolUnavailableError for the TypeScript compiler
+func tscUnavailable(err error) *types.ToolUnavailableError {
+	return &types.ToolUnavailableError{
+		Tool:    "tsc",
+		Install: "npm install -g typescript",
+		Err:     err,
+	}
+}
+
+// GetVersion returns the TypeScript compiler version
+func (tsc *TypeScriptCompiler) GetVersion() (string, error) {
+	output, err := tsc.runner.probe("", "--version")
 	if err != nil {
 		return "", err
 	}
@@ -181,3 +932,171 @@ func (tsc *TypeScriptCompiler) GetVersion() (string, error) {
 	return version, nil
 }
 
+// VersionIn returns the version of the TypeScript compiler that checks projects in dir,
+// which may be a project-local install, or a ToolUnavailableError
+func (tsc *TypeScriptCompiler) VersionIn(dir string) (string, error) {
+	output, err := tsc.runner.probe(dir, "--version")
+	if err != nil {
+		return "", tscUnavailable(err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PathIn returns the command used to run the TypeScript compiler for projects in dir
+func (tsc *TypeScriptCompiler) PathIn(dir string) string {
+	return tsc.runner.describe(dir)
+}
+
+// bundlers are dependencies that mean a project's TypeScript is compiled by a bundler
+// rather than emitted by tsc
+var bundlers = []string{"vite", "next", "webpack", "parcel", "esbuild", "@remix-run/dev", "react-scripts"}
+
+// RecommendTSConfig inspects a project's package.json and layout and recommends a
+// strict tsconfig.json for it
+func (tsc *TypeScriptCompiler) RecommendTSConfig(projectRoot string) (*types.TSConfig, error) {
+	info, err := os.Stat(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access project root: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("project root %s is not a directory", projectRoot)
+	}
+
+	var manifest struct {
+		Type            string            `json:"type"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if data, err := os.ReadFile(filepath.Join(projectRoot, "package.json")); err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse package.json: %w", err)
+		}
+	}
+	hasDependency := func(name string) bool {
+		_, dep := manifest.Dependencies[name]
+		_, devDep := manifest.DevDependencies[name]
+		return dep || devDep
+	}
+
+	config := &types.TSConfig{
+		CompilerOptions: types.TSCompilerOptions{
+			Target:                           "ES2022",
+			Lib:                              []string{"ES2022"},
+			Strict:                           true,
+			NoUncheckedIndexedAccess:         true,
+			NoImplicitOverride:               true,
+			NoFallthroughCasesInSwitch:       true,
+			ForceConsistentCasingInFileNames: true,
+			EsModuleInterop:                  true,
+			IsolatedModules:                  true,
+			ResolveJSONModule:                true,
+			SkipLibCheck:                     true,
+		},
+		Exclude: []string{"node_modules", "dist"},
+	}
+	options := &config.CompilerOptions
+
+	bundled := false
+	for _, name := range bundlers {
+		if hasDependency(name) {
+			bundled = true
+			config.Detected = append(config.Detected, "bundler: "+name)
+			break
+		}
+	}
+
+	if hasDependency("react") || hasDependency("preact") {
+		config.Detected = append(config.Detected, "react")
+		options.JSX = "react-jsx"
+		options.Lib = []string{"ES2022", "DOM", "DOM.Iterable"}
+	}
+
+	if hasDependency("@types/node") {
+		config.Detected = append(config.Detected, "node")
+		options.Types = []string{"node"}
+	}
+
+	switch {
+	case bundled:
+		// The bundler emits JavaScript, so tsc only type-checks
+		options.Module = "ESNext"
+		options.ModuleResolution = "Bundler"
+		options.NoEmit = true
+	case manifest.Type == "module":
+		config.Detected = append(config.Detected, "ES modules")
+		options.Module = "NodeNext"
+		options.ModuleResolution = "NodeNext"
+	default:
+		config.Detected = append(config.Detected, "CommonJS")
+		options.Module = "CommonJS"
+		options.ModuleResolution = "Node10"
+	}
+
+	if info, err := os.Stat(filepath.Join(projectRoot, "src")); err == nil && info.IsDir() {
+		config.Detected = append(config.Detected, "src directory")
+		config.Include = []string{"src"}
+		if !options.NoEmit {
+			options.RootDir = "src"
+		}
+	} else {
+		config.Include = []string{"**/*"}
+	}
+	if !options.NoEmit {
+		options.OutDir = "dist"
+		options.Declaration = true
+	}
+
+	return config, nil
+}
+
+// InitTSConfig writes the recommended tsconfig.json for a project, refusing to replace an
+// existing one unless Force is set
+func (tsc *TypeScriptCompiler) InitTSConfig(params types.InitTSConfigParams) (*types.InitTSConfigResult, error) {
+	projectRoot := params.ProjectRoot
+	if projectRoot == "" {
+		projectRoot = tsc.runner.projectRoot
+	}
+	if projectRoot == "" {
+		projectRoot = "."
+	}
+
+	config, err := tsc.RecommendTSConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tsconfig: %w", err)
+	}
+	content = append(content, '\n')
+
+	result := &types.InitTSConfigResult{
+		Path:     tsc.ConfigPath(projectRoot),
+		Detected: config.Detected,
+		Content:  string(content),
+	}
+	if result.Detected == nil {
+		result.Detected = []string{}
+	}
+
+	_, statErr := os.Stat(result.Path)
+	exists := statErr == nil
+	switch {
+	case params.DryRun:
+		result.Summary = fmt.Sprintf("Recommended configuration for %s (not written)", result.Path)
+	case exists && !params.Force:
+		return nil, fmt.Errorf("%s already exists; set force to overwrite it", result.Path)
+	default:
+		if err := os.WriteFile(result.Path, content, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write tsconfig: %w", err)
+		}
+		result.Written = true
+		result.Summary = fmt.Sprintf("Wrote %s", result.Path)
+		if exists {
+			result.Summary = fmt.Sprintf("Overwrote %s", result.Path)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// writeFakeBinary installs script as dir/node_modules/.bin/name, where the runner
+// prefers it over any other install. The script records its arguments, one per line,
+// in the file returned.
+func writeFakeBinary(t testing.TB, dir, name, script string) string {
+	t.Helper()
+	binDir := filepath.Join(dir, "node_modules", ".bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	argsFile := filepath.Join(binDir, name+".args")
+	content := "#!/bin/sh\nprintf '%s\\n' \"$@\" > '" + argsFile + "'\n" + script
+	if err := os.WriteFile(filepath.Join(binDir, name), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return argsFile
+}
+
+// readArgs returns the arguments a fake binary was last run with
+func readArgs(t testing.TB, argsFile string) []string {
+	t.Helper()
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("fake binary was not run: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// writeFile writes content to dir/name, creating parent directories
+func writeFile(t testing.TB, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// compositeFixture creates a solution tsconfig referencing two composite projects
+func compositeFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, root, "tsconfig.json", `{
+  "files": [],
+  "references": [{ "path": "./packages/core" }, { "path": "./packages/app" }]
+}`)
+	for _, pkg := range []string{"core", "app"} {
+		writeFile(t, root, "packages/"+pkg+"/tsconfig.json", `{
+  "compilerOptions": { "composite": true, "outDir": "dist" },
+  "include": ["src"]
+}`)
+		writeFile(t, root, "packages/"+pkg+"/src/index.ts", "export const answer: number = 42;\n")
+	}
+	return root
+}
+
+const buildOutput = `[12:00:00 PM] Projects in this build:
+    * packages/core/tsconfig.json
+    * packages/app/tsconfig.json
+    * tsconfig.json
+
+[12:00:00 PM] Project 'packages/core/tsconfig.json' is out of date because output file 'packages/core/dist/index.js' does not exist
+
+[12:00:00 PM] Building project '/repo/packages/core/tsconfig.json'...
+
+packages/core/src/index.ts(1,14): error TS2322: Type 'string' is not assignable to type 'number'.
+[12:00:01 PM] Project 'packages/app/tsconfig.json' can't be built because its dependency 'packages/core' has errors
+
+[12:00:01 PM] Skipping build of project '/repo/packages/app/tsconfig.json' because its dependency '/repo/packages/core' has errors
+
+error TS6305: Output file '/repo/packages/core/dist/index.d.ts' has not been built from source file '/repo/packages/core/src/index.ts'.
+`
+
+func TestIsCompositeProject(t *testing.T) {
+	tsc := NewTypeScriptCompiler()
+	root := compositeFixture(t)
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"references", filepath.Join(root, "tsconfig.json"), true},
+		{"composite", filepath.Join(root, "packages", "core", "tsconfig.json"), true},
+		{"plain", writeFile(t, t.TempDir(), "tsconfig.json", `{ "compilerOptions": { "strict": true } }`), false},
+		{"composite disabled", writeFile(t, t.TempDir(), "tsconfig.json", `{ "compilerOptions": { "composite": false } }`), false},
+		{"empty references", writeFile(t, t.TempDir(), "tsconfig.json", `{ "references": [] }`), false},
+		{"missing", filepath.Join(t.TempDir(), "tsconfig.json"), false},
+	}
+	for _, tt := range tests {
+		if got := tsc.isCompositeProject(tt.path); got != tt.want {
+			t.Errorf("%s: isCompositeProject = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseBuildOutput(t *testing.T) {
+	tsc := NewTypeScriptCompiler()
+
+	got := tsc.parseBuildOutput(buildOutput)
+	if want := []string{"/repo/packages/core/tsconfig.json"}; !slices.Equal(got, want) {
+		t.Errorf("parseBuildOutput = %q, want %q", got, want)
+	}
+	if got := tsc.parseBuildOutput("[12:00:00 PM] Project 'tsconfig.json' is up to date\n"); len(got) != 0 {
+		t.Errorf("parseBuildOutput of an up-to-date build = %q, want none", got)
+	}
+}
+
+func TestTypeCheckBuildMode(t *testing.T) {
+	root := compositeFixture(t)
+	argsFile := writeFakeBinary(t, root, "tsc", "cat <<'EOF'\n"+buildOutput+"EOF\nexit 1\n")
+
+	result, err := NewTypeScriptCompiler().TypeCheck(types.TypeCheckParams{ProjectRoot: root, Build: true})
+	if err != nil {
+		t.Fatalf("TypeCheck: %v", err)
+	}
+
+	args := readArgs(t, argsFile)
+	if !slices.Contains(args, "--build") || !slices.Contains(args, "--verbose") {
+		t.Errorf("tsc args = %q, want a verbose --build", args)
+	}
+	if !slices.Contains(args, "--noEmit") || slices.Contains(args, "--dry") {
+		t.Errorf("tsc args = %q, want a --noEmit build that writes nothing", args)
+	}
+	if args[len(args)-1] != filepath.Join(root, "tsconfig.json") {
+		t.Errorf("tsc args = %q, want the solution tsconfig last", args)
+	}
+
+	if result.Mode != "build" {
+		t.Errorf("Mode = %q, want build", result.Mode)
+	}
+	if result.Success || result.Passed {
+		t.Errorf("Success = %v, Passed = %v, want a failed build", result.Success, result.Passed)
+	}
+	if len(result.Errors) != 2 || result.ErrorCount != 2 {
+		t.Fatalf("Errors = %+v, ErrorCount = %d, want 2", result.Errors, result.ErrorCount)
+	}
+	if e := result.Errors[0]; e.Code != "TS2322" || e.Line != 1 || e.Column != 14 || e.File != filepath.Join("packages", "core", "src", "index.ts") {
+		t.Errorf("Errors[0] = %+v, want TS2322 at packages/core/src/index.ts(1,14)", e)
+	}
+	if e := result.Errors[1]; e.Code != "TS6305" || e.File != "" {
+		t.Errorf("Errors[1] = %+v, want the project-level TS6305", e)
+	}
+	if want := []string{"/repo/packages/core/tsconfig.json"}; !slices.Equal(result.OutdatedProjects, want) {
+		t.Errorf("OutdatedProjects = %q, want %q", result.OutdatedProjects, want)
+	}
+	if result.RawOutput != "" {
+		t.Errorf("RawOutput = %q, want none when diagnostics were parsed", result.RawOutput)
+	}
+}
+
+func TestTypeCheckBuildModeUpToDate(t *testing.T) {
+	root := compositeFixture(t)
+	writeFakeBinary(t, root, "tsc", "echo \"[12:00:00 PM] Project 'tsconfig.json' is up to date\"\n")
+
+	result, err := NewTypeScriptCompiler().TypeCheck(types.TypeCheckParams{ProjectRoot: root, Build: true})
+	if err != nil {
+		t.Fatalf("TypeCheck: %v", err)
+	}
+	if !result.Success || !result.Passed || len(result.Errors) != 0 || len(result.OutdatedProjects) != 0 {
+		t.Errorf("result = %+v, want a clean up-to-date build", result)
+	}
+}
+
+func TestTypeCheckBuildModeNonComposite(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "tsconfig.json", `{ "compilerOptions": { "strict": true } }`)
+	argsFile := writeFakeBinary(t, root, "tsc", "")
+
+	result, err := NewTypeScriptCompiler().TypeCheck(types.TypeCheckParams{ProjectRoot: root, Build: true})
+	if err != nil {
+		t.Fatalf("TypeCheck: %v", err)
+	}
+	if result.Mode != "noEmit" {
+		t.Errorf("Mode = %q, want noEmit for a project without references", result.Mode)
+	}
+	if args := readArgs(t, argsFile); slices.Contains(args, "--build") || !slices.Contains(args, "--noEmit") {
+		t.Errorf("tsc args = %q, want a --noEmit check", args)
+	}
+}
+
+func TestTypeCheckBuildModeRequiresProjectRoot(t *testing.T) {
+	file := writeFile(t, t.TempDir(), "index.ts", "export {};\n")
+	if _, err := NewTypeScriptCompiler().TypeCheck(types.TypeCheckParams{FilePath: file, Build: true}); err == nil {
+		t.Error("TypeCheck in build mode without a project_root returned no error")
+	}
+}
+
+// multilineOutput is tsc --pretty false output with elaborations and related information
+const multilineOutput = `src/user.ts(12,7): error TS2322: Type '{ id: string; name: string; }' is not assignable to type 'User'.
+  Types of property 'id' are incompatible.
+    Type 'string' is not assignable to type 'number'.
+src/user.ts(20,3): error TS2741: Property 'email' is missing in type '{ id: number; }' but required in type 'User'.
+  src/types.ts(4,3): 'email' is declared here.
+src/legacy.ts(3,1): warning TS6133: 'unused' is declared but its value is never read.
+error TS5083: Cannot read file '/repo/tsconfig.base.json'.
+src/index.ts(1,1): error TS2307: Cannot find module './missing' or its corresponding type declarations.
+`
+
+func TestParseTypeScriptOutputMultiline(t *testing.T) {
+	errs, warnings, counts := NewTypeScriptCompiler().parseTypeScriptOutput(multilineOutput)
+
+	if len(errs) != 4 || len(warnings) != 1 {
+		t.Fatalf("got %d errors and %d warnings, want 4 and 1: %+v %+v", len(errs), len(warnings), errs, warnings)
+	}
+
+	assignable := errs[0]
+	if assignable.Message != "Type '{ id: string; name: string; }' is not assignable to type 'User'." {
+		t.Errorf("Message = %q, want only the first line", assignable.Message)
+	}
+	wantDetails := []string{
+		"Types of property 'id' are incompatible.",
+		"Type 'string' is not assignable to type 'number'.",
+	}
+	if !slices.Equal(assignable.Details, wantDetails) {
+		t.Errorf("Details = %q, want %q", assignable.Details, wantDetails)
+	}
+
+	missing := errs[1]
+	if missing.Code != "TS2741" || missing.Line != 20 || missing.Column != 3 {
+		t.Errorf("errs[1] = %+v, want TS2741 at 20:3", missing)
+	}
+	if want := []string{"src/types.ts(4,3): 'email' is declared here."}; !slices.Equal(missing.Details, want) {
+		t.Errorf("Details = %q, want the related information %q", missing.Details, want)
+	}
+
+	if global := errs[2]; global.Code != "TS5083" || global.File != "" || len(global.Details) != 0 {
+		t.Errorf("errs[2] = %+v, want the project-level TS5083 without details", global)
+	}
+	if last := errs[3]; last.Code != "TS2307" || len(last.Details) != 0 {
+		t.Errorf("errs[3] = %+v, want TS2307 without details", last)
+	}
+	if warnings[0].Code != "TS6133" || len(warnings[0].Details) != 0 {
+		t.Errorf("warnings[0] = %+v, want TS6133 without details", warnings[0])
+	}
+
+	for code, want := range map[string]int{"TS2322": 1, "TS2741": 1, "TS6133": 1, "TS5083": 1, "TS2307": 1} {
+		if counts[code] != want {
+			t.Errorf("counts[%s] = %d, want %d", code, counts[code], want)
+		}
+	}
+	if len(counts) != 5 {
+		t.Errorf("counts = %v, want 5 codes; continuation lines must not be counted", counts)
+	}
+}
+
+func TestParseTypeScriptOutputIgnoresLeadingContinuation(t *testing.T) {
+	output := "  stray indented line\nsrc/a.ts(1,1): error TS1005: ';' expected.\n"
+	errs, _, _ := NewTypeScriptCompiler().parseTypeScriptOutput(output)
+	if len(errs) != 1 || len(errs[0].Details) != 0 {
+		t.Errorf("errors = %+v, want one error without details", errs)
+	}
+}
@@ -0,0 +1,307 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// ReadTSConfig parses a tsconfig file, which may contain comments and trailing commas,
+// and merges in the configs it extends. Compiler options set in the file override
+// inherited ones, and files, include and exclude are inherited only when unset.
+func ReadTSConfig(path string) (*types.ResolvedTSConfig, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tsconfig path: %w", err)
+	}
+	return readTSConfig(path, map[string]bool{})
+}
+
+// tsconfigFile is the raw content of a single tsconfig file
+type tsconfigFile struct {
+	Extends         json.RawMessage        `json:"extends"`
+	CompilerOptions map[string]interface{} `json:"compilerOptions"`
+	Files           []string               `json:"files"`
+	Include         []string               `json:"include"`
+	Exclude         []string               `json:"exclude"`
+}
+
+// readTSConfig reads path and its extends chain, using seen to detect cycles
+func readTSConfig(path string, seen map[string]bool) (*types.ResolvedTSConfig, error) {
+	if seen[path] {
+		return nil, fmt.Errorf("tsconfig extends cycle at %s", path)
+	}
+	seen[path] = true
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tsconfig: %w", err)
+	}
+	var file tsconfigFile
+	if err := json.Unmarshal(stripJSONC(content), &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// extends is a string, or since TypeScript 5.0 an array applied in order
+	var bases []string
+	if len(file.Extends) > 0 {
+		var single string
+		if err := json.Unmarshal(file.Extends, &single); err == nil {
+			bases = []string{single}
+		} else if err := json.Unmarshal(file.Extends, &bases); err != nil {
+			return nil, fmt.Errorf("invalid extends in %s: %w", path, err)
+		}
+	}
+
+	resolved := &types.ResolvedTSConfig{
+		Path:            path,
+		CompilerOptions: make(map[string]interface{}),
+	}
+	for _, base := range bases {
+		basePath, err := resolveExtends(filepath.Dir(path), base)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := readTSConfig(basePath, seen)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved.Extends = append(resolved.Extends, parent.Path)
+		resolved.Extends = append(resolved.Extends, parent.Extends...)
+		for name, value := range parent.CompilerOptions {
+			resolved.CompilerOptions[name] = value
+		}
+		if parent.Files != nil {
+			resolved.Files = parent.Files
+		}
+		if parent.Include != nil {
+			resolved.Include = parent.Include
+		}
+		if parent.Exclude != nil {
+			resolved.Exclude = parent.Exclude
+		}
+	}
+
+	for name, value := range file.CompilerOptions {
+		resolved.CompilerOptions[name] = value
+	}
+	if file.Files != nil {
+		resolved.Files = file.Files
+	}
+	if file.Include != nil {
+		resolved.Include = file.Include
+	}
+	if file.Exclude != nil {
+		resolved.Exclude = file.Exclude
+	}
+
+	return resolved, nil
+}
+
+// resolveExtends finds the file named by an extends entry: a path relative to dir, or a
+// package such as "@tsconfig/node20" or "@tsconfig/node20/tsconfig.json" in node_modules
+func resolveExtends(dir, name string) (string, error) {
+	var candidates []string
+	if strings.HasPrefix(name, ".") || filepath.IsAbs(name) {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, name)
+		}
+		candidates = []string{path, path + ".json"}
+	} else {
+		for current := dir; ; current = filepath.Dir(current) {
+			path := filepath.Join(current, "node_modules", filepath.FromSlash(name))
+			candidates = append(candidates, path, path+".json", filepath.Join(path, "tsconfig.json"))
+			if filepath.Dir(current) == current {
+				break
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("tsconfig in %s extends %q, which was not found", dir, name)
+}
+
+// stripJSONC removes comments and trailing commas from JSON with comments, leaving
+// string literals untouched
+func stripJSONC(content []byte) []byte {
+	out := make([]byte, 0, len(content))
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '"':
+			start := i
+			for i++; i < len(content) && content[i] != '"'; i++ {
+				if content[i] == '\\' {
+					i++
+				}
+			}
+			end := min(i+1, len(content))
+			out = append(out, content[start:end]...)
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(string(content[i+2:]), "*/")
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			trimmed := len(out)
+			for trimmed > 0 && strings.ContainsRune(" \t\r\n", rune(out[trimmed-1])) {
+				trimmed--
+			}
+			if trimmed > 0 && out[trimmed-1] == ',' {
+				out = append(out[:trimmed-1], out[trimmed:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// strictnessFlag describes a compiler flag audited by AuditTSConfig
+type strictnessFlag struct {
+	name string
+	// strictFamily flags default to the value of strict
+	strictFamily bool
+	priority     string
+	risk         string
+	impact       string
+}
+
+// strictnessFlags are the audited flags in the order they are recommended within a priority
+var strictnessFlags = []strictnessFlag{
+	{"strict", false, "high", "high: enables every strict-family check at once",
+		"Turns on the full set of type-safety checks that strict-mode TypeScript relies on"},
+	{"strictNullChecks", true, "high", "high: every possibly undefined value must be handled",
+		"Catches null and undefined dereferences, the most common runtime TypeError"},
+	{"noImplicitAny", true, "high", "medium: untyped parameters and variables need annotations",
+		"Stops values silently becoming any and escaping type checking"},
+	{"strictFunctionTypes", true, "medium", "low: mostly affects callback parameter variance",
+		"Checks function parameters contravariantly so unsafe callbacks are rejected"},
+	{"strictPropertyInitialization", true, "medium", "medium: class properties need initializers or definite assignment",
+		"Catches class fields read before they are assigned"},
+	{"useUnknownInCatchVariables", true, "medium", "low: catch clauses must narrow the error before use",
+		"Types caught errors as unknown instead of any"},
+	{"noUncheckedIndexedAccess", false, "medium", "high: every index access may be undefined",
+		"Catches out-of-range array and missing record key accesses"},
+	{"noImplicitReturns", false, "medium", "low: only functions with inconsistent returns are affected",
+		"Catches code paths that fall off the end of a function without returning"},
+	{"noFallthroughCasesInSwitch", false, "medium", "low: only non-empty cases without break are affected",
+		"Catches accidental fallthrough between switch cases"},
+	{"strictBindCallApply", true, "low", "low: rarely used APIs",
+		"Type checks arguments passed through bind, call and apply"},
+	{"noImplicitThis", true, "low", "low: only functions using an untyped this are affected",
+		"Catches this being implicitly typed as any"},
+	{"alwaysStrict", true, "low", "low: modules are already strict",
+		"Emits \"use strict\" and parses files in strict mode"},
+	{"noImplicitOverride", false, "low", "low: overriding members need the override keyword",
+		"Catches methods that silently stop overriding after a base class rename"},
+	{"exactOptionalPropertyTypes", false, "low", "medium: optional properties can no longer be assigned undefined explicitly",
+		"Distinguishes missing properties from properties set to undefined"},
+	{"noUnusedLocals", false, "low", "low: unused variables must be removed",
+		"Keeps dead code and leftover imports out of the codebase"},
+	{"noUnusedParameters", false, "low", "low: unused parameters must be removed or prefixed with _",
+		"Highlights parameters that no longer affect a function"},
+	{"forceConsistentCasingInFileNames", false, "low", "low: only mismatched import casing is affected",
+		"Prevents imports that break on case-sensitive file systems"},
+}
+
+// AuditTSConfig reports which strictness flags a project's tsconfig leaves off and
+// recommends changes in priority order
+func (tsc *TypeScriptCompiler) AuditTSConfig(params types.AuditTSConfigParams) (*types.TSConfigAudit, error) {
+	configPath := params.ConfigPath
+	if configPath == "" {
+		projectRoot := params.ProjectRoot
+		if projectRoot == "" {
+			projectRoot = tsc.runner.projectRoot
+		}
+		if projectRoot == "" {
+			projectRoot = "."
+		}
+		configPath = tsc.ConfigPath(projectRoot)
+	}
+
+	config, err := ReadTSConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	strict, _ := config.CompilerOptions["strict"].(bool)
+	audit := &types.TSConfigAudit{
+		ConfigPath: config.Path,
+		Extends:    config.Extends,
+	}
+
+	enabled := 0
+	for _, flag := range strictnessFlags {
+		state := types.StrictnessFlag{Name: flag.name, Source: "default"}
+		if value, ok := config.CompilerOptions[flag.name].(bool); ok {
+			state.Enabled = value
+			state.Source = "explicit"
+		} else if flag.strictFamily && strict {
+			state.Enabled = true
+			state.Source = "strict"
+		}
+		audit.Flags = append(audit.Flags, state)
+
+		if state.Enabled {
+			enabled++
+			continue
+		}
+		// Strict-family flags are covered by recommending strict, unless they are
+		// explicitly disabled and would stay off after enabling it
+		if flag.strictFamily && !strict && state.Source != "explicit" {
+			continue
+		}
+
+		change := fmt.Sprintf("Set \"%s\": true", flag.name)
+		if flag.strictFamily && state.Source == "explicit" {
+			change = fmt.Sprintf("Remove \"%s\": false so strict enables it", flag.name)
+		}
+		audit.Recommendations = append(audit.Recommendations, types.TSConfigRecommendation{
+			Flag:     flag.name,
+			Change:   change,
+			Priority: flag.priority,
+			Risk:     flag.risk,
+			Impact:   flag.impact,
+		})
+	}
+
+	// Grouping by priority keeps the flag order within each priority
+	recommendations := make([]types.TSConfigRecommendation, 0, len(audit.Recommendations))
+	for _, priority := range []string{"high", "medium", "low"} {
+		for _, rec := range audit.Recommendations {
+			if rec.Priority == priority {
+				recommendations = append(recommendations, rec)
+			}
+		}
+	}
+	audit.Recommendations = recommendations
+
+	audit.Score = enabled * 100 / len(strictnessFlags)
+	if len(audit.Recommendations) == 0 {
+		audit.Summary = fmt.Sprintf("All %d audited strictness flags are enabled", len(strictnessFlags))
+	} else {
+		audit.Summary = fmt.Sprintf("%d of %d strictness flags enabled (score %d); %d recommended change(s)",
+			enabled, len(strictnessFlags), audit.Score, len(audit.Recommendations))
+	}
+
+	return audit, nil
+}
@@ -1,64 +1,220 @@
 package typescript
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"os"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"mcp-typescript-assistant/pkg/types"
 )
 
+// PriorityWeights are the points deducted from a quality score of 100 for each improvement
+type PriorityWeights struct {
+	High   int
+	Medium int
+	Low    int
+}
+
+// DefaultPriorityWeights are the weights used unless SetPriorityWeights is called
+var DefaultPriorityWeights = PriorityWeights{High: 10, Medium: 3, Low: 1}
+
 // Analyzer provides TypeScript code analysis and improvement suggestions
 type Analyzer struct {
+	// mu guards the fields below, which tool handlers may update and read concurrently
+	mu         sync.RWMutex
 	guidelines map[string]*types.GuidelineSet
+	weights    PriorityWeights
+	// checks holds the built-in and registered checks, in the order they run
+	checks []registeredCheck
+	// enabledChecks and disabledChecks are the configured defaults for selectChecks
+	enabledChecks  []string
+	disabledChecks []string
+	// complexityThreshold is the default for requests without a ComplexityThreshold
+	complexityThreshold int
+	// namingRules are the naming rules requests' NamingRules apply on top of
+	namingRules map[string]string
 }
 
 // NewAnalyzer creates a new TypeScript analyzer
 func NewAnalyzer() *Analyzer {
 	return &Analyzer{
 		guidelines: make(map[string]*types.GuidelineSet),
+		weights:    DefaultPriorityWeights,
+		checks:     builtinChecks(),
+	}
+}
+
+// SetPriorityWeights changes the penalties used to compute quality scores
+func (a *Analyzer) SetPriorityWeights(weights PriorityWeights) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.weights = weights
+}
+
+// SetComplexityThreshold sets the complexity above which the "complexity" check flags
+// a function unless a request overrides it; zero restores the built-in default
+func (a *Analyzer) SetComplexityThreshold(threshold int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.complexityThreshold = threshold
+}
+
+// qualityScore returns 100 minus the weighted penalty of each improvement, clamped to [0, 100]
+func (a *Analyzer) qualityScore(improvements []types.Improvement) int {
+	a.mu.RLock()
+	weights := a.weights
+	a.mu.RUnlock()
+
+	score := 100
+	for _, improvement := range improvements {
+		switch improvement.Priority {
+		case "high":
+			score -= weights.High
+		case "medium":
+			score -= weights.Medium
+		case "low":
+			score -= weights.Low
+		}
 	}
+
+	return max(0, min(100, score))
 }
 
 // SuggestImprovements analyzes TypeScript code and suggests improvements
 func (a *Analyzer) SuggestImprovements(params types.SuggestImprovementsParams) (*types.ImprovementResult, error) {
+	if params.ProjectRoot != "" {
+		return a.suggestProjectImprovements(params)
+	}
+
+	selected, err := a.selectChecks(params)
+	if err != nil {
+		return nil, err
+	}
+	// Whitespace has nothing to improve, but claiming it follows best practices would mislead
+	if strings.TrimSpace(params.CodeSnippet) == "" {
+		return &types.ImprovementResult{
+			Improvements: []types.Improvement{},
+			Summary:      "No code provided",
+			Score:        100,
+		}, nil
+	}
+
+	improvements, ran := a.analyzeCode(params.CodeSnippet, params, selected)
+	improvements, suppressed := suppressImprovements(improvements, params.IgnoreRules)
+	if params.SortByPriority == nil || *params.SortByPriority {
+		sortByPriority(improvements)
+	}
+	summary := a.generateImprovementSummary(improvements)
+	if suppressed > 0 {
+		summary += fmt.Sprintf(" (%d suppressed)", suppressed)
+	}
+
+	result := &types.ImprovementResult{
+		Improvements: improvements,
+		Summary:      summary,
+		AppliedRules: a.appliedRules(ran),
+		TotalIssues:  len(improvements),
+		Score:        a.qualityScore(improvements),
+		Suppressed:   suppressed,
+	}
+
+	if maxIssues := maxIssuesOrDefault(params.MaxIssues); len(improvements) > maxIssues {
+		result.Improvements = improvements[:maxIssues]
+		result.Truncated = true
+		result.Note = fmt.Sprintf("Showing the first %d of %d improvements", maxIssues, len(improvements))
+	}
+
+	return result, nil
+}
+
+// maxIssuesOrDefault returns maxIssues, or types.DefaultMaxIssues when it isn't set
+func maxIssuesOrDefault(maxIssues int) int {
+	if maxIssues <= 0 {
+		return types.DefaultMaxIssues
+	}
+	return maxIssues
+}
+
+// analyzeCode runs the selected checks and loaded guidelines against a piece of code,
+// then deduplicates and filters the improvements according to params. It also returns
+// the names of the checks that applied to the code.
+func (a *Analyzer) analyzeCode(code string, params types.SuggestImprovementsParams, selected []registeredCheck) ([]types.Improvement, []string) {
 	var improvements []types.Improvement
-	var appliedRules []string
+	var ran []string
 
-	// Analyze the code snippet for common TypeScript issues
-	improvements = append(improvements, a.analyzeTypeAnnotations(params.CodeSnippet)...)
-	improvements = append(improvements, a.analyzeNamingConventions(params.CodeSnippet)...)
-	improvements = append(improvements, a.analyzeImportExports(params.CodeSnippet)...)
-	improvements = append(improvements, a.analyzeAsyncAwait(params.CodeSnippet)...)
-	improvements = append(improvements, a.analyzeTypeAssertions(params.CodeSnippet)...)
-	improvements = append(improvements, a.analyzeUtilityTypes(params.CodeSnippet)...)
+	for _, registered := range selected {
+		if registered.applies != nil && !registered.applies(code, params) {
+			continue
+		}
+		confidence := registered.confidence
+		if confidence == 0 {
+			confidence = defaultConfidence
+		}
+		for _, improvement := range registered.check.Analyze(code) {
+			if improvement.Confidence == 0 {
+				improvement.Confidence = confidence
+			}
+			improvements = append(improvements, improvement)
+		}
+		ran = append(ran, registered.check.Name())
+	}
 
 	// Apply custom guidelines if loaded
-	for _, guidelineSet := range a.guidelines {
-		guidelineImprovements := a.applyGuidelines(params.CodeSnippet, guidelineSet)
+	for _, guidelineSet := range a.GetLoadedGuidelines() {
+		guidelineImprovements := a.applyGuidelines(code, guidelineSet)
 		improvements = append(improvements, guidelineImprovements...)
-		appliedRules = append(appliedRules, guidelineSet.Name)
 	}
 
-	// Add standard TypeScript best practices
-	appliedRules = append(appliedRules, "typescript-standard-practices")
+	improvements = a.deduplicateImprovements(improvements)
+	improvements = a.filterImprovements(improvements, params.MinPriority, params.MinConfidence, params.Types)
+	improvements = applyInlineSuppressions(code, improvements)
 
-	summary := a.generateImprovementSummary(improvements)
+	for i := range improvements {
+		if improvements[i].Before != "" && improvements[i].After != "" {
+			improvements[i].Diff = lineDiff(improvements[i].Before, improvements[i].After)
+		}
+		improvements[i].ID = improvementID(improvements[i])
+	}
 
-	return &types.ImprovementResult{
-		Improvements: improvements,
-		Summary:      summary,
-		AppliedRules: appliedRules,
-	}, nil
+	return improvements, ran
+}
+
+// appliedRules lists the guideline sets and checks used during analysis
+func (a *Analyzer) appliedRules(ran []string) []string {
+	var appliedRules []string
+
+	for _, guidelineSet := range a.GetLoadedGuidelines() {
+		appliedRules = append(appliedRules, guidelineSet.Name)
+	}
+
+	return append(appliedRules, ran...)
 }
 
-// analyzeTypeAnnotations checks for missing or incorrect type annotations
-func (a *Analyzer) analyzeTypeAnnotations(code string) []types.Improvement {
+var (
+	// untypedDeclarationRegex matches a variable declared without a type annotation: the
+	// name is followed directly by '=', so `const x: number = 5` doesn't match
+	untypedDeclarationRegex = regexp.MustCompile(`\b(?:let|const|var)\s+[\w$]+\s*=`)
+	// namedFunctionRegex matches a function declaration up to its parameter list
+	namedFunctionRegex = regexp.MustCompile(`\bfunction\s*\*?\s*[\w$]+\s*(?:<[^>]*>)?\s*\(`)
+)
+
+// Analyze checks for missing or incorrect type annotations
+func (typeAnnotationsCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
+	stripped := stripStringsAndComments(code)
+
 	// Check for implicit any types
-	anyRegex := regexp.MustCompile(`let\s+\w+\s*=|const\s+\w+\s*=|var\s+\w+\s*=`)
-	if anyRegex.MatchString(code) {
+	if untypedDeclarationRegex.MatchString(stripped) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "type_annotation",
 			Description: "Consider adding explicit type annotations to variables",
@@ -68,8 +224,7 @@ func (a *Analyzer) analyzeTypeAnnotations(code string) []types.Improvement {
 	}
 
 	// Check for function parameters without types
-	paramRegex := regexp.MustCompile(`function\s+\w+\s*\([^)]*\w+[^:)]*\)`)
-	if paramRegex.MatchString(code) {
+	if hasUntypedParam(stripped) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "function_types",
 			Description: "Add type annotations to function parameters",
@@ -81,51 +236,102 @@ func (a *Analyzer) analyzeTypeAnnotations(code string) []types.Improvement {
 	return improvements
 }
 
-// analyzeNamingConventions checks naming conventions
-func (a *Analyzer) analyzeNamingConventions(code string) []types.Improvement {
+// hasUntypedParam reports whether a function declaration in code, which should have its
+// strings and comments stripped, has a parameter with neither a type annotation nor a
+// default value to infer one from
+func hasUntypedParam(code string) bool {
+	for _, match := range namedFunctionRegex.FindAllStringIndex(code, -1) {
+		closeParen := matchingClose(code, match[1]-1)
+		if closeParen < 0 {
+			continue
+		}
+		for _, param := range splitTopLevel(code[match[1]:closeParen]) {
+			// The annotation follows the name or destructuring pattern, outside its brackets
+			if !strings.Contains(param, ":") && !strings.Contains(param, "=") {
+				return true
+			}
+			if pattern := strings.TrimLeft(param, ". \t\r\n"); strings.HasPrefix(pattern, "{") || strings.HasPrefix(pattern, "[") {
+				if end := matchingClose(pattern, 0); end >= 0 {
+					if rest := strings.TrimSpace(pattern[end+1:]); !strings.HasPrefix(rest, ":") && !strings.HasPrefix(rest, "=") {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+var (
+	// exportedArrowRegex matches an exported arrow function up to its parameter list,
+	// capturing its name
+	exportedArrowRegex = regexp.MustCompile(`(?m)^[ \t]*export\s+const\s+([\w$]+)\s*=\s*(?:async\s*)?(?:<[^>]*>)?\s*\(`)
+	// exportedLineRegex matches a line that starts an exported declaration
+	exportedLineRegex = regexp.MustCompile(`^\s*export\b`)
+)
+
+// Analyze flags functions without a return type annotation: exported functions by
+// default, or every function, method and getter when all is set. Constructors and
+// setters can't have return types.
+func (c returnTypesCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
-	// Check for PascalCase interfaces/types
-	interfaceRegex := regexp.MustCompile(`interface\s+([a-z][a-zA-Z]*)`)
-	matches := interfaceRegex.FindAllStringSubmatch(code, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			improvements = append(improvements, types.Improvement{
-				Type:        "naming_convention",
-				Description: fmt.Sprintf("Interface '%s' should use PascalCase", match[1]),
-				Before:      match[1],
-				After:       strings.Title(match[1]),
-				Reasoning:   "TypeScript convention uses PascalCase for types and interfaces",
-				Priority:    "low",
-			})
+	stripped := stripStringsAndComments(code)
+	flag := func(name string, start int) {
+		line, column := lineColumnAt(code, start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "type_annotation",
+			Description: fmt.Sprintf("Add an explicit return type to %s", name),
+			Reasoning:   "An explicit return type documents the function's contract and lets the compiler catch accidental changes to it, such as a widened type or a missing return",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	checked := make(map[int]bool)
+	for _, span := range functionSpans(stripped) {
+		checked[span.start] = true
+		lineStart := strings.LastIndex(stripped[:span.start], "\n") + 1
+		prefix := stripped[lineStart:span.start]
+		if span.returnType != "" || span.name == "constructor" || strings.HasSuffix(strings.TrimRight(prefix, " \t"), "set") {
+			continue
 		}
+		if !c.all && !exportedLineRegex.MatchString(prefix) {
+			continue
+		}
+		flag(span.name, span.start)
 	}
 
-	// Check for camelCase functions/variables
-	varRegex := regexp.MustCompile(`(?:let|const|var)\s+([A-Z][a-zA-Z]*)`)
-	matches = varRegex.FindAllStringSubmatch(code, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			improvements = append(improvements, types.Improvement{
-				Type:        "naming_convention",
-				Description: fmt.Sprintf("Variable '%s' should use camelCase", match[1]),
-				Before:      match[1],
-				After:       strings.ToLower(match[1][:1]) + match[1][1:],
-				Reasoning:   "TypeScript convention uses camelCase for variables and functions",
-				Priority:    "low",
-			})
+	// Exported arrow functions with an expression body aren't function spans
+	for _, match := range exportedArrowRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		if checked[match[2]] {
+			continue
+		}
+		closeParen := matchingClose(stripped, match[1]-1)
+		if closeParen < 0 {
+			continue
+		}
+		if rest := strings.TrimLeft(stripped[closeParen+1:], " \t\r\n"); strings.HasPrefix(rest, "=>") {
+			flag(submatch(stripped, match, 1), match[2])
 		}
 	}
 
 	return improvements
 }
 
-// analyzeImportExports checks import/export best practices
-func (a *Analyzer) analyzeImportExports(code string) []types.Improvement {
+var (
+	// defaultExportRegex matches a default-exported declaration
+	defaultExportRegex = regexp.MustCompile(`export\s+default\s+(class|function|interface)`)
+	// relativeImportRegex matches an import from a sibling module
+	relativeImportRegex = regexp.MustCompile(`import.*from\s+['"]\.\/[^'"]*['"]`)
+)
+
+// Analyze checks import/export best practices
+func (importExportsCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
 	// Check for default exports that could be named exports
-	defaultExportRegex := regexp.MustCompile(`export\s+default\s+(class|function|interface)`)
 	if defaultExportRegex.MatchString(code) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "export_style",
@@ -136,8 +342,7 @@ func (a *Analyzer) analyzeImportExports(code string) []types.Improvement {
 	}
 
 	// Check for import without file extension (simplified check)
-	importRegex := regexp.MustCompile(`import.*from\s+['"]\.\/[^'"]*['"]`)
-	if importRegex.MatchString(code) && !strings.Contains(code, ".ts\"") && !strings.Contains(code, ".js\"") {
+	if relativeImportRegex.MatchString(code) && !strings.Contains(code, ".ts\"") && !strings.Contains(code, ".js\"") {
 		improvements = append(improvements, types.Improvement{
 			Type:        "import_style",
 			Description: "Consider adding explicit file extensions to relative imports",
@@ -149,12 +354,17 @@ func (a *Analyzer) analyzeImportExports(code string) []types.Improvement {
 	return improvements
 }
 
-// analyzeAsyncAwait checks async/await usage
-func (a *Analyzer) analyzeAsyncAwait(code string) []types.Improvement {
+var (
+	thenRegex          = regexp.MustCompile(`\.then\s*\(`)
+	asyncFunctionRegex = regexp.MustCompile(`async\s+function`)
+	tryRegex           = regexp.MustCompile(`try\s*{`)
+)
+
+// Analyze checks async/await usage
+func (asyncAwaitCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
 	// Check for Promise.then() that could be async/await
-	thenRegex := regexp.MustCompile(`\.then\s*\(`)
 	if thenRegex.MatchString(code) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "async_pattern",
@@ -165,9 +375,7 @@ func (a *Analyzer) analyzeAsyncAwait(code string) []types.Improvement {
 	}
 
 	// Check for missing error handling in async functions
-	asyncRegex := regexp.MustCompile(`async\s+function`)
-	tryRegex := regexp.MustCompile(`try\s*{`)
-	if asyncRegex.MatchString(code) && !tryRegex.MatchString(code) {
+	if asyncFunctionRegex.MatchString(code) && !tryRegex.MatchString(code) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "error_handling",
 			Description: "Add error handling to async functions",
@@ -179,42 +387,348 @@ func (a *Analyzer) analyzeAsyncAwait(code string) []types.Improvement {
 	return improvements
 }
 
-// analyzeTypeAssertions checks type assertion usage
-func (a *Analyzer) analyzeTypeAssertions(code string) []types.Improvement {
+var (
+	// asyncDeclarationRegexes capture the names of functions, methods and properties
+	// declared async or with a Promise return type
+	asyncDeclarationRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`\basync\s+function\s*\*?\s*([\w$]+)`),
+		regexp.MustCompile(`\b(?:const|let|var)\s+([\w$]+)\s*(?::[^=;]+)?=\s*async\b`),
+		regexp.MustCompile(`(?m)^\s*(?:(?:public|private|protected|static|override)\s+)*async\s+\*?\s*([\w$]+)\s*(?:<[^>]*>)?\s*\(`),
+		regexp.MustCompile(`([\w$]+)\s*:\s*async\b`),
+		regexp.MustCompile(`\bfunction\s+([\w$]+)\s*(?:<[^>]*>)?\s*\([^)]*\)\s*:\s*Promise\s*<`),
+	}
+	// statementCallRegex matches a call at the start of a line, capturing the callee
+	// and its last name segment
+	statementCallRegex = regexp.MustCompile(`(?m)^[ \t]*((?:[\w$]+\s*\.\s*)*([\w$]+))\s*\(`)
+)
+
+// promiseCallees are global functions that always return a promise
+var promiseCallees = map[string]bool{
+	"fetch":              true,
+	"Promise.all":        true,
+	"Promise.allSettled": true,
+	"Promise.any":        true,
+	"Promise.race":       true,
+}
+
+// Analyze flags floating promises: calls to async functions used as statements without
+// await, void or a .catch() handler, whose rejections go unhandled
+func (floatingPromisesCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+
+	asyncNames := make(map[string]bool)
+	for _, declarationRegex := range asyncDeclarationRegexes {
+		for _, match := range declarationRegex.FindAllStringSubmatch(stripped, -1) {
+			asyncNames[match[1]] = true
+		}
+	}
+
+	for _, match := range statementCallRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		callee := strings.Join(strings.Fields(submatch(stripped, match, 1)), "")
+		if !asyncNames[submatch(stripped, match, 2)] && !promiseCallees[callee] {
+			continue
+		}
+		if !isStatementStart(stripped, match[2]) {
+			continue
+		}
+
+		end, handled := promiseChainEnd(stripped, match[1]-1)
+		if end < 0 || handled {
+			continue
+		}
+		// A body or return type after the parameter list makes this a declaration
+		if rest := strings.TrimLeft(stripped[end:], " \t\r\n"); strings.HasPrefix(rest, "{") || strings.HasPrefix(rest, ":") {
+			continue
+		}
+
+		statement := strings.TrimSpace(code[match[2]:end])
+		line, column := lineColumnAt(code, match[2])
+		improvements = append(improvements, types.Improvement{
+			Type:        "error_handling",
+			Description: fmt.Sprintf("Await the promise returned by %s()", callee),
+			Before:      statement,
+			After:       "await " + statement,
+			Reasoning:   "A promise that is neither awaited nor given a .catch() handler rejects unnoticed and runs out of order; await it, add .catch(), or mark it intentional with void",
+			Priority:    "high",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// isStatementStart reports whether the expression at offset begins a statement, i.e. the
+// previous code ends a statement or block, or is an if/loop header or else
+func isStatementStart(code string, offset int) bool {
+	before := strings.TrimRight(code[:offset], " \t\r\n")
+	if before == "" || strings.HasSuffix(before, "else") || strings.HasSuffix(before, "do") {
+		return true
+	}
+	return strings.ContainsRune(";{})", rune(before[len(before)-1]))
+}
+
+// promiseChainEnd follows the call whose '(' is at open and any .method() calls chained
+// onto it, returning the offset just past the chain and whether it includes .catch().
+// It returns -1 when the parentheses are unbalanced.
+func promiseChainEnd(code string, open int) (int, bool) {
+	handled := false
+	for {
+		end := matchingClose(code, open)
+		if end < 0 {
+			return -1, false
+		}
+		end++
+
+		rest := strings.TrimLeft(code[end:], " \t\r\n")
+		access := strings.TrimPrefix(rest, "?.")
+		if access == rest {
+			access = strings.TrimPrefix(rest, ".")
+		}
+		if access == rest {
+			return end, handled
+		}
+		n := 0
+		for n < len(access) && isIdentifierByte(access[n]) {
+			n++
+		}
+		method := access[:n]
+		next := strings.TrimLeft(access[n:], " \t\r\n")
+		if method == "" || !strings.HasPrefix(next, "(") {
+			return end, handled
+		}
+		if method == "catch" {
+			handled = true
+		}
+		open = len(code) - len(next)
+	}
+}
+
+// matchingClose returns the index of the bracket closing the '(', '[' or '{' at open, or -1
+func matchingClose(code string, open int) int {
+	closer := map[byte]byte{'(': ')', '[': ']', '{': '}'}[code[open]]
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case code[open]:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var (
+	asAnyRegex        = regexp.MustCompile(`as\s+any`)
+	// angleBracketRegex matches a type in angle brackets, such as <Foo>, <Foo[]> or
+	// <Array<Foo>>, which may be an assertion or a type argument list
+	angleBracketRegex = regexp.MustCompile(`<([\w$.]+(?:<[\w$.,\s\[\]]*>)?(?:\[\])*)>`)
+	// assertionContextRegex matches the text an angle-bracket assertion can follow: an
+	// operator, an opening bracket or a keyword taking an expression
+	assertionContextRegex = regexp.MustCompile(`(?:^|[=(,:\[!&|?{};+\-*%]|=>|\b(?:return|typeof|await|yield|case|throw|in|of|void|delete))$`)
+)
+
+// Analyze checks type assertion usage
+func (typeAssertionsCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
 	// Check for 'as any' assertions
-	asAnyRegex := regexp.MustCompile(`as\s+any`)
 	if asAnyRegex.MatchString(code) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "type_safety",
 			Description: "Avoid using 'as any' type assertions",
 			Reasoning:   "Type assertions bypass TypeScript's type checking and reduce type safety",
 			Priority:    "high",
+			Confidence:  0.95,
 		})
 	}
 
 	// Check for angle bracket assertions (prefer 'as' syntax)
-	angleBracketRegex := regexp.MustCompile(`<\w+>`)
-	if angleBracketRegex.MatchString(code) {
+	if hasAngleBracketAssertion(code) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "assertion_style",
 			Description: "Use 'as' syntax instead of angle bracket assertions",
 			Reasoning:   "'as' syntax is preferred and works better with JSX",
 			Priority:    "low",
+			Confidence:  0.8,
+		})
+	}
+
+	return improvements
+}
+
+var (
+	// weakTypeRegex matches the types that accept almost anything
+	weakTypeRegex = regexp.MustCompile(`\b(?:Function|Object)\b|\{\s*\}`)
+	// genericArgumentRegex matches the text from a type argument list's '<' up to a
+	// position inside it
+	genericArgumentRegex = regexp.MustCompile(`[\w$]<[^<>(){};=]*$`)
+	// typeBodyRegex matches the text before the brace opening an interface, class or
+	// type alias body
+	typeBodyRegex = regexp.MustCompile(`(?:\binterface\s+[\w$]+[^{;]*|\bclass\b[^{;]*|\btype\s+[\w$]+\s*(?:<[^>]*>)?\s*=\s*)$`)
+)
+
+// weakTypeReplacements are what weak_types suggests instead of each weak type
+var weakTypeReplacements = map[string]struct{ after, reasoning string }{
+	"Function": {
+		after:     "(...args: unknown[]) => unknown",
+		reasoning: "'Function' accepts any callable, including classes, and calls through it are unchecked; a specific signature types the arguments and the result",
+	},
+	"Object": {
+		after:     "Record<string, unknown>",
+		reasoning: "'Object' accepts every value except null and undefined, primitives included; Record<string, unknown> or object describe objects",
+	},
+	"{}": {
+		after:     "Record<string, unknown>",
+		reasoning: "'{}' means any non-nullish value rather than an empty object; use Record<string, unknown> for objects, object for any non-primitive or unknown for any value",
+	},
+}
+
+// Analyze flags the weak types Function, Object and {} where they are used as types,
+// like @typescript-eslint/ban-types. Object literals, destructuring and the Object and
+// Function globals used as values aren't type positions and are left alone.
+func (weakTypesCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+	for _, match := range weakTypeRegex.FindAllStringIndex(stripped, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && (stripped[start-1] == '.' || stripped[start-1] == '$') {
+			continue
+		}
+		if next := strings.TrimLeft(stripped[end:], " \t"); strings.HasPrefix(next, ".") || strings.HasPrefix(next, "(") {
+			continue
+		}
+		if !isTypePosition(stripped, start) {
+			continue
+		}
+
+		weakType := stripped[start:end]
+		if weakType != "Function" && weakType != "Object" {
+			weakType = "{}"
+		}
+		replacement := weakTypeReplacements[weakType]
+		line, column := lineColumnAt(code, start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "type_safety",
+			Description: fmt.Sprintf("Replace the weak type '%s' with a specific type", weakType),
+			Before:      weakType,
+			After:       replacement.after,
+			Reasoning:   replacement.reasoning,
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
 		})
 	}
 
 	return improvements
 }
 
-// analyzeUtilityTypes suggests utility type usage
-func (a *Analyzer) analyzeUtilityTypes(code string) []types.Improvement {
+// isTypePosition reports whether the type-like text starting at offset in code, which
+// should have its strings and comments stripped, is used as a type: annotated after a
+// ':', in a union, intersection or type argument list, aliased or asserted with 'as'
+func isTypePosition(code string, offset int) bool {
+	before := strings.TrimRight(code[:offset], " \t\r\n")
+	switch {
+	case strings.HasSuffix(before, ":"):
+		return !strings.HasSuffix(before, "?:") && isAnnotationColon(code, len(before)-1)
+	case strings.HasSuffix(before, "|"):
+		return !strings.HasSuffix(before, "||")
+	case strings.HasSuffix(before, "&"):
+		return !strings.HasSuffix(before, "&&")
+	case strings.HasSuffix(before, "="):
+		return typeAliasRegex.MatchString(before[strings.LastIndex(before, "\n")+1:])
+	case strings.HasSuffix(before, "as"):
+		return len(before) == 2 || !isIdentifierByte(before[len(before)-3])
+	}
+	return genericArgumentRegex.MatchString(before[max(0, len(before)-200):])
+}
+
+// isAnnotationColon reports whether the ':' at offset in code starts a type annotation,
+// telling object literal keys apart from the members of interfaces, classes and object
+// types by the brace around them
+func isAnnotationColon(code string, offset int) bool {
+	before := code[:offset+1]
+	depth := 0
+	for i := offset - 1; i >= 0; i-- {
+		switch code[i] {
+		case ')', ']', '}':
+			depth++
+		case '(', '[':
+			if depth == 0 {
+				return isTypeAnnotation(before)
+			}
+			depth--
+		case '{':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			return isTypeBody(code, i) || !objectKeyRegex.MatchString(before[max(0, len(before)-200):]) && isTypeAnnotation(before)
+		}
+	}
+	return isTypeAnnotation(before)
+}
+
+// isTypeBody reports whether the brace at offset in code opens the body of an interface,
+// class or type alias, or an object type annotating something
+func isTypeBody(code string, offset int) bool {
+	before := strings.TrimRight(code[:offset], " \t\r\n")
+	if typeBodyRegex.MatchString(before[max(0, len(before)-200):]) {
+		return true
+	}
+	return strings.HasSuffix(before, ":") && isAnnotationColon(code, len(before)-1)
+}
+
+// hasAngleBracketAssertion reports whether code contains an angle-bracket type assertion
+// such as <Foo>bar. Type arguments follow an identifier, generic arrow functions and
+// function types have a parameter list followed by '=>' or a return type, and JSX
+// elements have a closing tag, so none of them count.
+func hasAngleBracketAssertion(code string) bool {
+	stripped := stripStringsAndComments(code)
+	for _, match := range angleBracketRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		before := strings.TrimRight(stripped[:match[0]], " \t\r\n")
+		if !assertionContextRegex.MatchString(before[max(0, len(before)-20):]) {
+			continue
+		}
+
+		// The asserted expression follows; strings are blank in stripped, so look at code
+		rest := strings.TrimLeft(code[match[1]:], " \t\r\n")
+		if rest == "" || !isIdentifierByte(rest[0]) && !strings.ContainsRune("([{'\"`", rune(rest[0])) {
+			continue
+		}
+		if rest[0] == '(' {
+			open := len(code) - len(rest)
+			if closeParen := matchingClose(stripped, open); closeParen >= 0 {
+				after := strings.TrimLeft(stripped[closeParen+1:], " \t\r\n")
+				if strings.HasPrefix(after, "=>") || strings.HasPrefix(after, ":") {
+					continue
+				}
+			}
+		}
+		if strings.Contains(stripped[match[1]:], "</"+submatch(stripped, match, 1)) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// optionalPropertyRegex matches an object type whose first property is optional
+var optionalPropertyRegex = regexp.MustCompile(`{\s*\w+\?\s*:`)
+
+// Analyze suggests utility type usage
+func (utilityTypesCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
 	// Check for manual partial type definitions
-	partialRegex := regexp.MustCompile(`{\s*\w+\?\s*:`)
-	if partialRegex.MatchString(code) {
+	if optionalPropertyRegex.MatchString(code) {
 		improvements = append(improvements, types.Improvement{
 			Type:        "utility_types",
 			Description: "Consider using Partial<T> utility type",
@@ -236,60 +750,1866 @@ func (a *Analyzer) analyzeUtilityTypes(code string) []types.Improvement {
 	return improvements
 }
 
-// applyGuidelines applies custom guidelines to the code analysis
-func (a *Analyzer) applyGuidelines(code string, guidelineSet *types.GuidelineSet) []types.Improvement {
+// nonNullAssertionThreshold is the number of non-null assertions above which the finding becomes high priority
+const nonNullAssertionThreshold = 5
+
+// nonNullRegex matches a postfix '!' after an identifier, call or index expression
+// that is not part of '!=' or '!=='
+var nonNullRegex = regexp.MustCompile(`[\w$][\w$.]*(?:\(\)|\[\w*\])?!(?:[^=]|$)`)
+
+// Analyze checks for non-null assertion operator usage
+func (nonNullAssertionsCheck) Analyze(code string) []types.Improvement {
 	var improvements []types.Improvement
 
-	for _, guideline := range guidelineSet.Guidelines {
-		// Simple pattern matching for guidelines
-		// In a real implementation, this would be more sophisticated
-		for _, rule := range guideline.Rules {
-			if strings.Contains(code, rule) {
-				improvements = append(improvements, types.Improvement{
-					Type:         "guideline",
-					Description:  guideline.Description,
-					Reasoning:    fmt.Sprintf("According to %s guidelines", guidelineSet.Name),
-					Priority:     guideline.Priority,
-					GuidelineRef: guideline.ID,
-				})
+	stripped := stripStringsAndComments(code)
+
+	matches := nonNullRegex.FindAllStringIndex(stripped, -1)
+	if len(matches) == 0 {
+		return improvements
+	}
+
+	first := code[matches[0][0]:matches[0][1]]
+	expression := first[:strings.LastIndex(first, "!")+1]
+	line, column := lineColumnAt(code, matches[0][0])
+
+	// Property access through an assertion maps directly onto optional chaining
+	after := ""
+	if strings.HasSuffix(first, "!.") {
+		expression += "."
+		after = strings.TrimSuffix(expression, "!.") + "?."
+	}
+
+	priority := "medium"
+	if len(matches) > nonNullAssertionThreshold {
+		priority = "high"
+	}
+
+	improvements = append(improvements, types.Improvement{
+		Type:        "type_safety",
+		Description: fmt.Sprintf("Avoid non-null assertions (found %d)", len(matches)),
+		Before:      expression,
+		After:       after,
+		Reasoning:   "Non-null assertions silence the compiler without checking for null; use an explicit null check or optional chaining instead",
+		Priority:    priority,
+		Line:        line,
+		Column:      column,
+		Occurrences: len(matches),
+	})
+
+	return improvements
+}
+
+// equalityRegex matches `left == right` and `left != right`; the caller rules out
+// `===`, `!==`, `<=`, `>=` and `=>`
+var equalityRegex = regexp.MustCompile("([\\w$.\\])]+)\\s*(==|!=)\\s*([\\w$.\\[(]+|[\"'`])")
+
+// Analyze checks for loose equality operators
+func (equalityCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+
+	for _, match := range equalityRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		if match[4] > 0 && strings.ContainsAny(stripped[match[4]-1:match[4]], "=!<>") {
+			continue
+		}
+		if match[5] < len(stripped) && stripped[match[5]] == '=' {
+			continue
+		}
+
+		// Extend a string operand to its closing quote
+		end := match[1]
+		if quote := stripped[match[6]]; quote == '"' || quote == '\'' || quote == '`' {
+			if closing := strings.IndexByte(stripped[end:], quote); closing >= 0 {
+				end += closing + 1
 			}
 		}
+
+		operator := submatch(stripped, match, 2)
+		before := code[match[0]:end]
+		line, column := lineColumnAt(code, match[0])
+		improvements = append(improvements, types.Improvement{
+			Type:        "equality",
+			Description: fmt.Sprintf("Use strict equality instead of '%s'", operator),
+			Before:      before,
+			After:       strings.Replace(before, operator, operator+"=", 1),
+			Reasoning:   "Loose equality performs type coercion and can produce surprising results; '===' and '!==' compare without coercion",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
 	}
 
 	return improvements
 }
 
-// generateImprovementSummary creates a summary of all improvements
-func (a *Analyzer) generateImprovementSummary(improvements []types.Improvement) string {
-	if len(improvements) == 0 {
-		return "No improvements suggested. Code follows TypeScript best practices."
-	}
+// varRegex matches a var declaration, capturing the first declared name unless it is a
+// destructuring pattern. The leading group rules out identifiers such as $var.
+var varRegex = regexp.MustCompile(`(^|[^\w$.])var\s+([\w$]+)?`)
 
-	highPriority := 0
-	mediumPriority := 0
-	lowPriority := 0
+// Analyze flags var declarations, suggesting const when a statement declares a single
+// variable that is initialized and never reassigned, and let otherwise
+func (varCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
 
-	for _, improvement := range improvements {
-		switch improvement.Priority {
-		case "high":
-			highPriority++
-		case "medium":
-			mediumPriority++
-		case "low":
-			lowPriority++
+	stripped := stripStringsAndComments(code)
+
+	for _, match := range varRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		start := match[3]
+		if strings.HasSuffix(strings.TrimRight(stripped[:start], " \t"), "declare") {
+			continue
 		}
+
+		name := submatch(stripped, match, 2)
+		keyword := "let"
+		if name != "" && isInitialized(stripped, match[1]) && isSingleDeclarator(stripped, match[1]) &&
+			!isReassigned(stripped, name, match[1]) {
+			keyword = "const"
+		}
+
+		before := "var"
+		if name != "" {
+			before = "var " + name
+		}
+		line, column := lineColumnAt(code, start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "modernization",
+			Description: fmt.Sprintf("Use '%s' instead of 'var'", keyword),
+			Before:      before,
+			After:       keyword + strings.TrimPrefix(before, "var"),
+			Reasoning:   "var is function-scoped and hoisted, which leaks variables out of blocks; let and const are block-scoped, and const also prevents reassignment",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
 	}
 
-	return fmt.Sprintf("Found %d improvement suggestions: %d high priority, %d medium priority, %d low priority",
-		len(improvements), highPriority, mediumPriority, lowPriority)
+	return improvements
+}
+
+// isInitialized reports whether the declaration whose name ends at offset has an initializer
+func isInitialized(code string, offset int) bool {
+	rest := strings.TrimLeft(code[offset:], " \t")
+	if strings.HasPrefix(rest, ":") {
+		// Skip a type annotation up to the initializer or the end of the statement
+		end := strings.IndexAny(rest, "=;\n")
+		if end < 0 {
+			return false
+		}
+		rest = rest[end:]
+	}
+	return strings.HasPrefix(rest, "=") && !strings.HasPrefix(rest, "==")
 }
 
-// LoadGuidelines loads custom guidelines from a guideline set
-func (a *Analyzer) LoadGuidelines(guidelineSet *types.GuidelineSet) {
-	a.guidelines[guidelineSet.Name] = guidelineSet
+// isSingleDeclarator reports whether the var statement whose first name ends at offset
+// declares only that name. Rewriting `var a = 1, b;` to const would leave b without
+// the initializer const requires.
+func isSingleDeclarator(code string, offset int) bool {
+	depth := 0
+	inType := true
+	for i := offset; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return true
+			}
+			depth--
+		case '<', '>':
+			// Type arguments such as Map<string, number> only occur before the initializer
+			if inType && (c == '<' || code[i-1] != '=') {
+				if c == '<' {
+					depth++
+				} else if depth > 0 {
+					depth--
+				}
+			}
+		case '=':
+			if depth == 0 {
+				inType = false
+			}
+		case ',':
+			if depth == 0 {
+				return false
+			}
+		case ';':
+			if depth == 0 {
+				return true
+			}
+		case '\n':
+			// Without a semicolon, the statement continues only after a trailing operator
+			// or before a leading one
+			if depth == 0 {
+				before := strings.TrimRight(code[offset:i], " \t\r")
+				after := strings.TrimLeft(code[i:], " \t\r\n")
+				if !strings.HasSuffix(before, ",") && !strings.HasSuffix(before, "=") && !strings.HasPrefix(after, ",") {
+					return true
+				}
+			}
+		}
+	}
+	return true
 }
 
-// GetLoadedGuidelines returns all loaded guidelines
-func (a *Analyzer) GetLoadedGuidelines() map[string]*types.GuidelineSet {
-	return a.guidelines
+// isReassigned reports whether name is assigned, incremented or decremented anywhere in
+// code other than its declaration ending at declared. Like countReferences it scans for
+// whole-identifier occurrences, then inspects their surroundings: assignment operators,
+// ++ and --, destructuring assignments such as `[a, name] = pair` and for-in/of heads
+// such as `for (name of items)`.
+func isReassigned(code, name string, declared int) bool {
+	for offset := 0; ; {
+		index := strings.Index(code[offset:], name)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(name)
+		offset = end
+		if start > 0 && (isIdentifierByte(code[start-1]) || code[start-1] == '.') ||
+			end < len(code) && isIdentifierByte(code[end]) || end == declared {
+			continue
+		}
+
+		before := strings.TrimRight(code[:start], " \t\r\n")
+		after := strings.TrimLeft(code[end:], " \t\r\n")
+		if isAssignmentOperator(after) || strings.HasPrefix(after, "++") || strings.HasPrefix(after, "--") ||
+			strings.HasSuffix(before, "++") || strings.HasSuffix(before, "--") ||
+			isForInOfTarget(before, after) || isDestructuringTarget(code, start) {
+			return true
+		}
+	}
+}
+
+// assignmentOperators are the compound assignment operators, longest first
+var assignmentOperators = []string{">>>=", "**=", "<<=", ">>=", "??=", "&&=", "||=", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^="}
+
+// isAssignmentOperator reports whether code starts with an assignment operator, as
+// opposed to a comparison or an arrow
+func isAssignmentOperator(code string) bool {
+	if strings.HasPrefix(code, "=") {
+		return !strings.HasPrefix(code, "==") && !strings.HasPrefix(code, "=>")
+	}
+	for _, operator := range assignmentOperators {
+		if strings.HasPrefix(code, operator) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForInOfTarget reports whether an identifier between before and after is the
+// target of a for-in or for-of loop without a declaration, as in `for (name of items)`
+func isForInOfTarget(before, after string) bool {
+	if !strings.HasSuffix(before, "(") {
+		return false
+	}
+	head := strings.TrimRight(strings.TrimSuffix(before, "("), " \t")
+	head = strings.TrimRight(strings.TrimSuffix(head, "await"), " \t")
+	if !strings.HasSuffix(head, "for") || len(head) > 3 && isIdentifierByte(head[len(head)-4]) {
+		return false
+	}
+	for _, keyword := range []string{"of", "in"} {
+		if strings.HasPrefix(after, keyword) && (len(after) == len(keyword) || !isIdentifierByte(after[len(keyword)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDestructuringTarget reports whether the identifier at start sits in an array or
+// object pattern that is assigned to, as in `[a, name] = pair` or `({ name } = obj)`.
+// Enclosing brackets are followed outwards while they are elements of a larger pattern.
+func isDestructuringTarget(code string, start int) bool {
+	for {
+		open := enclosingBracket(code, start)
+		if open < 0 || code[open] == '(' {
+			return false
+		}
+		closing := matchingBracket(code, open)
+		if closing < 0 {
+			return false
+		}
+		after := strings.TrimLeft(code[closing+1:], " \t\r\n")
+		if strings.HasPrefix(after, "=") {
+			return isAssignmentOperator(after)
+		}
+		if !strings.HasPrefix(after, ",") && !strings.HasPrefix(after, "]") && !strings.HasPrefix(after, "}") {
+			return false
+		}
+		start = open
+	}
+}
+
+// enclosingBracket returns the offset of the innermost unclosed bracket before offset,
+// or -1 at the top level
+func enclosingBracket(code string, offset int) int {
+	depth := 0
+	for i := offset - 1; i >= 0; i-- {
+		switch code[i] {
+		case ')', ']', '}':
+			depth++
+		case '(', '[', '{':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// matchingBracket returns the offset of the bracket closing the one at open, or -1
+func matchingBracket(code string, open int) int {
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var (
+	// enumRegex matches an enum declaration up to its opening brace, capturing any
+	// export, declare or const modifiers and the name
+	enumRegex = regexp.MustCompile(`(?:^|[^\w$.])((export\s+)?(declare\s+)?(const\s+)?enum\s+([\w$]+)\s*)\{`)
+	// enumMemberRegex matches an enum member and its optional initializer
+	enumMemberRegex = regexp.MustCompile(`^\s*([\w$]+|"[^"]*"|'[^']*')\s*(?:=\s*(.+?))?\s*$`)
+	// enumLiteralRegex matches the initializers enumsCheck can carry over to an object
+	enumLiteralRegex = regexp.MustCompile(`^(?:-?\d+|"[^"]*"|'[^']*')$`)
+)
+
+// Analyze flags enum declarations. Enums that only need their values at compile time
+// can become a const enum; enums used as runtime objects (iterated with Object.keys or
+// reverse mapped) or holding strings are better written as an `as const` object with a
+// union type of its values.
+func (enumsCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+
+	for _, match := range enumRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		if match[6] >= 0 || match[8] >= 0 {
+			// Ambient and const enums have no runtime cost
+			continue
+		}
+
+		open := match[1] - 1
+		end := strings.IndexByte(stripped[open:], '}')
+		if end < 0 {
+			continue
+		}
+		end += open + 1
+
+		name := submatch(stripped, match, 5)
+		exported := match[4] >= 0
+		members, stringValued := enumMembers(code[open+1 : end-1])
+		runtimeUse := usesEnumAtRuntime(stripped, name)
+
+		before := code[match[2]:end]
+		line, column := lineColumnAt(code, match[2])
+		improvement := types.Improvement{
+			Type:     "enum_usage",
+			Before:   before,
+			Priority: "low",
+			Line:     line,
+			Column:   column,
+		}
+
+		if stringValued || runtimeUse {
+			improvement.Description = fmt.Sprintf("Replace enum %s with an 'as const' object and a union type", name)
+			improvement.Reasoning = "Enums compile to an object wrapped in an IIFE that bundlers can't tree-shake, and numeric enums add a reverse mapping; an 'as const' object is plain JavaScript, and its union type accepts the literal values directly"
+			improvement.Priority = "medium"
+			if members != nil {
+				improvement.After = asConstEnum(name, members, exported)
+			}
+		} else {
+			improvement.Description = fmt.Sprintf("Declare enum %s as a const enum", name)
+			improvement.Reasoning = "A regular enum emits a runtime object with a reverse mapping; a const enum is inlined at each use and emits no code, as long as the enum isn't iterated or indexed dynamically"
+			improvement.After = strings.Replace(before, "enum", "const enum", 1)
+		}
+
+		improvements = append(improvements, improvement)
+	}
+
+	return improvements
+}
+
+// enumMember is a member of an enum declaration and its value as a literal
+type enumMember struct {
+	name  string
+	value string
+}
+
+// enumMembers parses the members of an enum body, filling in the implicit numeric
+// values, and reports whether any member holds a string. The members are nil when an
+// initializer isn't a number or string literal.
+func enumMembers(body string) ([]enumMember, bool) {
+	stripped := stripStringsAndComments(body)
+
+	var members []enumMember
+	stringValued, resolvable := false, true
+	next := 0
+
+	for start := 0; start < len(body); {
+		end := strings.IndexByte(stripped[start:], ',')
+		if end < 0 {
+			end = len(body)
+		} else {
+			end += start
+		}
+		part, original := stripped[start:end], body[start:end]
+		start = end + 1
+
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		// Match the stripped text so comments are ignored, then read the original text
+		// at the same offsets so string contents survive
+		match := enumMemberRegex.FindStringSubmatchIndex(part)
+		if match == nil {
+			resolvable = false
+			continue
+		}
+
+		member := enumMember{name: original[match[2]:match[3]]}
+		if match[4] < 0 {
+			member.value = strconv.Itoa(next)
+			next++
+		} else {
+			member.value = original[match[4]:match[5]]
+			if !enumLiteralRegex.MatchString(member.value) {
+				resolvable = false
+			} else if n, err := strconv.Atoi(member.value); err == nil {
+				next = n + 1
+			} else {
+				stringValued = true
+			}
+		}
+		members = append(members, member)
+	}
+
+	if !resolvable {
+		return nil, stringValued
+	}
+	return members, stringValued
+}
+
+// usesEnumAtRuntime reports whether code iterates an enum with Object.keys/values/entries
+// or indexes it dynamically, which needs the runtime object a const enum doesn't emit
+func usesEnumAtRuntime(code, name string) bool {
+	// Like countReferences, scan for whole-identifier occurrences and inspect their
+	// surroundings rather than compiling a pattern per enum
+	for offset := 0; ; {
+		index := strings.Index(code[offset:], name)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(name)
+		offset = end
+		if start > 0 && (isIdentifierByte(code[start-1]) || code[start-1] == '.') ||
+			end < len(code) && isIdentifierByte(code[end]) {
+			continue
+		}
+
+		after := strings.TrimLeft(code[end:], " \t\r\n")
+		if strings.HasPrefix(after, "[") {
+			return true
+		}
+		before := strings.TrimRight(code[:start], " \t\r\n")
+		if !strings.HasPrefix(after, ")") || !strings.HasSuffix(before, "(") {
+			continue
+		}
+		callee := strings.TrimRight(strings.TrimSuffix(before, "("), " \t\r\n")
+		for _, method := range []string{"Object.keys", "Object.values", "Object.entries"} {
+			if strings.HasSuffix(callee, method) &&
+				(len(callee) == len(method) || !isIdentifierByte(callee[len(callee)-len(method)-1]) && callee[len(callee)-len(method)-1] != '.') {
+				return true
+			}
+		}
+	}
+}
+
+// asConstEnum rewrites enum members as an `as const` object with a union type of the
+// same name
+func asConstEnum(name string, members []enumMember, exported bool) string {
+	prefix := ""
+	if exported {
+		prefix = "export "
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sconst %s = {\n", prefix, name)
+	for _, member := range members {
+		fmt.Fprintf(&b, "  %s: %s,\n", member.name, member.value)
+	}
+	b.WriteString("} as const;\n")
+	fmt.Fprintf(&b, "%stype %s = (typeof %s)[keyof typeof %s];", prefix, name, name, name)
+	return b.String()
+}
+
+var (
+	switchRegex  = regexp.MustCompile(`\bswitch\s*\(`)
+	caseRegex    = regexp.MustCompile(`(?m)^([ \t]*)case\b`)
+	defaultRegex = regexp.MustCompile(`\bdefault\s*:`)
+	neverRegex   = regexp.MustCompile(`:\s*never\b|\bassertNever\s*\(`)
+)
+
+// Analyze flags switch statements with neither a default case nor an exhaustiveness
+// guard, which silently ignore values added to the union or enum being switched on
+func (exhaustiveSwitchCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+
+	for _, match := range switchRegex.FindAllStringIndex(stripped, -1) {
+		closeParen := matchingClose(stripped, match[1]-1)
+		if closeParen < 0 {
+			continue
+		}
+		subject := strings.TrimSpace(code[match[1]:closeParen])
+
+		block := blockAfter(stripped, closeParen+1)
+		if !strings.HasPrefix(block, "{") || !strings.HasSuffix(block, "}") {
+			continue
+		}
+		topLevel := switchTopLevel(block)
+		if !strings.Contains(topLevel, "case") || defaultRegex.MatchString(topLevel) || neverRegex.MatchString(block) {
+			continue
+		}
+
+		end := closeParen + 1 + strings.Index(stripped[closeParen+1:], "{") + len(block)
+		before := code[match[0]:end]
+		closing := len(before) - 1
+		lineStart := strings.LastIndex(before[:closing], "\n") + 1
+		guard := "const _exhaustive: never = " + subject + ";"
+		throw := "throw new Error(`Unhandled case: ${_exhaustive}`);"
+
+		var after string
+		if lineStart > 0 && strings.TrimSpace(before[lineStart:closing]) == "" {
+			// Put the default case on its own lines, indented like the first case
+			indent := ""
+			if caseLine := caseRegex.FindStringSubmatch(topLevel); caseLine != nil {
+				indent = caseLine[1]
+			}
+			after = before[:lineStart] +
+				fmt.Sprintf("%sdefault: {\n%s  %s\n%s  %s\n%s}\n", indent, indent, guard, indent, throw, indent) +
+				before[lineStart:]
+		} else {
+			after = strings.TrimRight(before[:closing], " \t") + fmt.Sprintf(" default: { %s %s } }", guard, throw)
+		}
+
+		line, column := lineColumnAt(code, match[0])
+		improvements = append(improvements, types.Improvement{
+			Type:        "type_safety",
+			Description: fmt.Sprintf("Add an exhaustiveness check to the switch on %s", subject),
+			Before:      before,
+			After:       after,
+			Reasoning:   "Without a default case, a value added to the union or enum later falls through the switch unnoticed; assigning it to never in the default case makes the compiler report every unhandled case",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// switchTopLevel returns the parts of a switch block outside nested braces, where its
+// case and default labels are
+func switchTopLevel(block string) string {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(block); i++ {
+		switch block[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		default:
+			if depth == 1 {
+				b.WriteByte(block[i])
+			}
+		}
+	}
+	return b.String()
+}
+
+// defaultComplexityThreshold is the estimated cyclomatic complexity above which a
+// function is flagged when no threshold is configured
+const defaultComplexityThreshold = 10
+
+// decisionPointRegex matches the branches counted towards cyclomatic complexity:
+// conditionals, loops, cases, catch clauses, logical operators and ternaries. A '?' only
+// counts when it isn't optional chaining or an optional member; the caller blanks out
+// nullish coalescing.
+var decisionPointRegex = regexp.MustCompile(`\b(?:if|for|while|case|catch)\b|&&|\|\||\?[^.?:]`)
+
+// Analyze estimates the cyclomatic complexity of each function as one plus its decision
+// points, not counting nested functions, and flags those above the threshold
+func (c complexityCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	threshold := c.threshold
+	if threshold <= 0 {
+		threshold = defaultComplexityThreshold
+	}
+
+	stripped := stripStringsAndComments(code)
+	spans := functionSpans(stripped)
+	for _, span := range spans {
+		body := strings.ReplaceAll(span.body(stripped, spans), "??", "  ")
+		complexity := 1 + len(decisionPointRegex.FindAllStringIndex(body, -1))
+		if complexity <= threshold {
+			continue
+		}
+
+		line, column := lineColumnAt(code, span.start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "maintainability",
+			Description: fmt.Sprintf("Function %s has an estimated cyclomatic complexity of %d (threshold %d)", span.name, complexity, threshold),
+			Reasoning:   "Every branch adds a path to understand and test; extract independent branches into helper functions, return early, or replace conditionals with lookup tables",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// Defaults for the function size checks when no limit is configured
+const (
+	defaultMaxFunctionLines = 50
+	defaultMaxParams        = 4
+)
+
+// Analyze flags functions with more non-blank, non-comment lines than the limit. Twice
+// the limit makes the finding medium priority.
+func (c functionLengthCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	maxLines := c.maxLines
+	if maxLines <= 0 {
+		maxLines = defaultMaxFunctionLines
+	}
+
+	stripped := stripStringsAndComments(code)
+	for _, span := range functionSpans(stripped) {
+		lines := 0
+		for _, line := range strings.Split(stripped[span.start:span.bodyEnd+1], "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines++
+			}
+		}
+		if lines <= maxLines {
+			continue
+		}
+
+		priority := "low"
+		if lines > 2*maxLines {
+			priority = "medium"
+		}
+		line, column := lineColumnAt(code, span.start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "maintainability",
+			Description: fmt.Sprintf("Function %s is %d lines long (limit %d)", span.name, lines, maxLines),
+			Reasoning:   "Long functions do several things at once; extract cohesive steps into well-named helper functions so each can be read and tested on its own",
+			Priority:    priority,
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// Analyze flags functions with more parameters than the limit, suggesting a parameter object
+func (c parameterCountCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	maxParams := c.maxParams
+	if maxParams <= 0 {
+		maxParams = defaultMaxParams
+	}
+
+	stripped := stripStringsAndComments(code)
+	for _, span := range functionSpans(stripped) {
+		params := splitTopLevel(span.params)
+		// A `this` parameter only declares the receiver's type
+		if len(params) > 0 && strings.HasPrefix(params[0], "this") && strings.HasPrefix(strings.TrimSpace(params[0][len("this"):]), ":") {
+			params = params[1:]
+		}
+		if len(params) <= maxParams {
+			continue
+		}
+
+		line, column := lineColumnAt(code, span.start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "maintainability",
+			Description: fmt.Sprintf("Function %s takes %d parameters (limit %d)", span.name, len(params), maxParams),
+			Reasoning:   "Long parameter lists are easy to call with arguments in the wrong order; group related parameters into a single options object with a named interface",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// splitTopLevel splits a parameter list at the commas outside brackets and generics,
+// dropping empty entries such as the one after a trailing comma
+func splitTopLevel(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(', '[', '{', '<':
+				depth++
+				continue
+			case ')', ']', '}':
+				depth--
+				continue
+			case '>':
+				// '=>' in a function type doesn't close a generic
+				if i == 0 || list[i-1] != '=' {
+					depth--
+				}
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if part := strings.TrimSpace(list[start:i]); part != "" {
+			parts = append(parts, part)
+		}
+		start = i + 1
+	}
+	return parts
+}
+
+// consoleRegex matches a call to any console method consoleCheck can flag
+var consoleRegex = regexp.MustCompile(`\bconsole\.(log|debug|info|error|warn)\s*\(`)
+
+// Analyze checks for leftover console calls. By default only debugging output
+// (log, debug, info) is flagged; allowConsole true skips the check and false also flags error and warn.
+func (c consoleCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	if c.allowConsole != nil && *c.allowConsole {
+		return improvements
+	}
+
+	methods := []string{"log", "debug", "info"}
+	if c.allowConsole != nil {
+		methods = append(methods, "error", "warn")
+	}
+
+	// One pass finds every console call; the matches are then grouped by method
+	stripped := stripStringsAndComments(code)
+	calls := make(map[string][][]int)
+	for _, match := range consoleRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		method := submatch(stripped, match, 1)
+		calls[method] = append(calls[method], match)
+	}
+
+	for _, method := range methods {
+		matches := calls[method]
+		if len(matches) == 0 {
+			continue
+		}
+
+		line, column := lineColumnAt(code, matches[0][0])
+		improvements = append(improvements, types.Improvement{
+			Type:        "code_cleanliness",
+			Description: fmt.Sprintf("Remove console.%s calls or replace them with a proper logger", method),
+			Before:      fmt.Sprintf("console.%s(", method),
+			Reasoning:   "Console output left in production code is noisy and can leak internal details",
+			Priority:    "low",
+			Line:        line,
+			Column:      column,
+			Occurrences: len(matches),
+		})
+	}
+
+	return improvements
+}
+
+var (
+	// importClauseRegex matches the bindings of an import statement, e.g. `React, { useState as useS }`
+	importClauseRegex = regexp.MustCompile(`(?m)^\s*import\s+(?:type\s+)?([^;]*?)\s+from\b`)
+	// localDeclarationRegex matches the keyword of a const or let declaration, including
+	// one in a for loop header
+	localDeclarationRegex = regexp.MustCompile(`(?:^|[^\w$.])(?:const|let)\s+`)
+	// bindingNameRegex matches the identifier at the start of a declarator
+	bindingNameRegex = regexp.MustCompile(`^[\p{L}_$][\p{L}\p{N}_$]*`)
+	// templateExpressionRegex matches ${...} expressions, which stripStringsAndComments blanks out
+	templateExpressionRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+// Analyze flags imports and const/let bindings that are never referenced in the code.
+// A snippet can't show cross-file usage, so only bindings declared inside a function
+// body are checked, and names starting with an underscore are never flagged. Every
+// declarator of a declaration is checked, including destructured names and for loop
+// variables. Scopes aren't tracked, so a name used by another function in the snippet
+// counts as used.
+func (unusedCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+
+	// References inside template literal expressions are blanked out with the string
+	searchSpace := stripped
+	for _, matches := range templateExpressionRegex.FindAllStringSubmatch(code, -1) {
+		searchSpace += "\n" + matches[1]
+	}
+
+	isUnused := func(name string) bool {
+		if strings.HasPrefix(name, "_") {
+			return false
+		}
+		return countReferences(searchSpace, name) <= 1
+	}
+
+	for _, match := range importClauseRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		for _, name := range importBindings(submatch(stripped, match, 1)) {
+			// The classic JSX transform uses React implicitly
+			if !isUnused(name) || (name == "React" && ContainsJSX(code)) {
+				continue
+			}
+			line, column := lineColumnAt(code, match[0])
+			improvements = append(improvements, types.Improvement{
+				Type:        "code_cleanliness",
+				Description: fmt.Sprintf("Remove unused import '%s'", name),
+				Before:      strings.TrimSpace(lineAt(code, line)),
+				Reasoning:   "Unused imports add noise and can keep otherwise unneeded modules in the bundle",
+				Priority:    "low",
+				Line:        line,
+				Column:      column,
+			})
+		}
+	}
+
+	spans := functionSpans(stripped)
+	for _, match := range localDeclarationRegex.FindAllStringIndex(stripped, -1) {
+		start := match[1]
+		if !slices.ContainsFunc(spans, func(span functionSpan) bool { return span.bodyStart < start && start < span.bodyEnd }) {
+			continue
+		}
+
+		list := declarationList(stripped, start)
+		for _, name := range declaredNames(list) {
+			if !isUnused(name) {
+				continue
+			}
+			line, column := lineColumnAt(code, start+max(0, identifierIndex(list, name)))
+			improvements = append(improvements, types.Improvement{
+				Type:        "code_cleanliness",
+				Description: fmt.Sprintf("Remove unused variable '%s'", name),
+				Before:      strings.TrimSpace(lineAt(code, line)),
+				Reasoning:   "The variable is never read in this code; it may be left over from a refactor",
+				Priority:    "low",
+				Line:        line,
+				Column:      column,
+			})
+		}
+	}
+
+	return improvements
+}
+
+// declarationList returns the declarators of the const or let declaration whose first
+// declarator starts at start: up to the ';' or line break that ends the statement, or
+// the ')' that closes a for loop header
+func declarationList(code string, start int) string {
+	depth := 0
+	for i := start; i < len(code); i++ {
+		switch code[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth < 0 {
+				return code[start:i]
+			}
+		case ';':
+			if depth == 0 {
+				return code[start:i]
+			}
+		case '\n':
+			// A trailing comma continues the declaration on the next line
+			if depth == 0 && !strings.HasSuffix(strings.TrimRight(code[start:i], " \t\r"), ",") {
+				return code[start:i]
+			}
+		}
+	}
+	return code[start:]
+}
+
+// declaredNames returns the names bound by a list of declarators such as
+// `a = 1, { b, c: d, ...e } = obj, [f, , g = 2] = list` or `x of items`
+func declaredNames(list string) []string {
+	var names []string
+	for _, declarator := range splitTopLevel(list) {
+		names = append(names, bindingNames(declarator)...)
+	}
+	return names
+}
+
+// bindingNames returns the names bound by the binding at the start of text: an
+// identifier, or an object or array destructuring pattern
+func bindingNames(text string) []string {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "...")
+	if text == "" || text[0] != '{' && text[0] != '[' {
+		if name := bindingNameRegex.FindString(text); name != "" {
+			return []string{name}
+		}
+		return nil
+	}
+
+	closing := matchingClose(text, 0)
+	if closing < 0 {
+		return nil
+	}
+	var names []string
+	for _, element := range splitTopLevel(text[1:closing]) {
+		// In an object pattern, `key: target` binds target
+		if text[0] == '{' {
+			if colon := topLevelIndex(element, ':'); colon >= 0 {
+				element = element[colon+1:]
+			}
+		}
+		names = append(names, bindingNames(element)...)
+	}
+	return names
+}
+
+// topLevelIndex returns the index of the first c in text outside brackets, or -1
+func topLevelIndex(text string, c byte) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case c:
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// identifierIndex returns the offset of the first occurrence of name in code as a whole
+// identifier, or -1
+func identifierIndex(code, name string) int {
+	for offset := 0; ; {
+		index := strings.Index(code[offset:], name)
+		if index < 0 {
+			return -1
+		}
+		start := offset + index
+		end := start + len(name)
+		if (start == 0 || !isIdentifierByte(code[start-1])) && (end == len(code) || !isIdentifierByte(code[end])) {
+			return start
+		}
+		offset = end
+	}
+}
+
+// countReferences counts the occurrences of name in code as a whole identifier that
+// is not a property access. It replaces a per-name regex, which dominated analysis time.
+func countReferences(code, name string) int {
+	count := 0
+	for offset := 0; ; {
+		index := strings.Index(code[offset:], name)
+		if index < 0 {
+			return count
+		}
+		start := offset + index
+		end := start + len(name)
+		if (start == 0 || !isIdentifierByte(code[start-1]) && code[start-1] != '.') &&
+			(end == len(code) || !isIdentifierByte(code[end])) {
+			count++
+		}
+		offset = end
+	}
+}
+
+// isIdentifierByte reports whether b can be part of an ASCII identifier
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '$' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// importBindings returns the local names bound by an import clause such as
+// `React, { useState, type FC, memo as m }` or `* as path`
+func importBindings(clause string) []string {
+	var names []string
+
+	if open := strings.Index(clause, "{"); open >= 0 {
+		closing := strings.Index(clause[open:], "}")
+		if closing < 0 {
+			return nil
+		}
+		for _, specifier := range strings.Split(clause[open+1:open+closing], ",") {
+			fields := strings.Fields(specifier)
+			if len(fields) > 0 && fields[0] == "type" {
+				fields = fields[1:]
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			names = append(names, fields[len(fields)-1])
+		}
+		clause = clause[:open] + clause[open+closing+1:]
+	}
+
+	for _, part := range strings.Split(clause, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		names = append(names, fields[len(fields)-1])
+	}
+
+	return names
+}
+
+// lineAt returns the text of the 1-based line of code
+func lineAt(code string, line int) string {
+	lines := strings.Split(code, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
+
+// jsxRegex matches a JSX closing tag or self-closing element, which plain generics never produce
+var jsxRegex = regexp.MustCompile(`</[A-Za-z][\w.]*\s*>|<[A-Za-z][\w.]*(?:\s+[^<>]*)?/>`)
+
+// ContainsJSX reports whether code appears to contain JSX markup
+func ContainsJSX(code string) bool {
+	return jsxRegex.MatchString(stripStringsAndComments(code))
+}
+
+var (
+	// mapElementRegex matches a JSX element returned from a .map() callback
+	mapElementRegex = regexp.MustCompile(`\.map\s*\(\s*\(?[\w\s,{}]*\)?\s*=>\s*\(?\s*<([A-Za-z][\w.]*)((?:=>|[^<>])*)>`)
+	// inlineHandlerRegex matches an arrow function passed directly as a JSX prop
+	inlineHandlerRegex = regexp.MustCompile(`\s([A-Za-z][\w]*)=\{\s*(?:async\s*)?\(?[\w\s,]*\)?\s*=>`)
+	// anyPropsRegex matches component props typed as any
+	anyPropsRegex = regexp.MustCompile(`(?:FC|FunctionComponent|Component|PropsWithChildren)<\s*any\s*[,>]|\(\s*(?:props|\{[^{}]*\})\s*:\s*any\b`)
+)
+
+// Analyze checks React/JSX-specific patterns
+func (reactCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+
+	// Check for list items rendered with .map() without a key prop
+	for _, match := range mapElementRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		if strings.Contains(submatch(stripped, match, 2), "key=") {
+			continue
+		}
+		line, column := lineColumnAt(code, match[0])
+		improvements = append(improvements, types.Improvement{
+			Type:        "react",
+			Description: fmt.Sprintf("Add a 'key' prop to <%s> rendered in .map()", submatch(code, match, 1)),
+			Before:      code[match[0]:match[1]],
+			Reasoning:   "React uses keys to track list items between renders; missing keys cause warnings and incorrect updates",
+			Priority:    "high",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	// Check for inline arrow functions passed as JSX props
+	if matches := inlineHandlerRegex.FindAllStringSubmatchIndex(stripped, -1); len(matches) > 0 {
+		line, column := lineColumnAt(code, matches[0][2])
+		improvements = append(improvements, types.Improvement{
+			Type:        "performance",
+			Description: fmt.Sprintf("Avoid inline arrow functions in JSX props such as '%s'", submatch(code, matches[0], 1)),
+			Reasoning:   "Inline functions create a new reference on every render, defeating memoization of child components; consider useCallback",
+			Priority:    "low",
+			Line:        line,
+			Column:      column,
+			Occurrences: len(matches),
+		})
+	}
+
+	// Check for untyped component props
+	for _, match := range anyPropsRegex.FindAllStringIndex(stripped, -1) {
+		line, column := lineColumnAt(code, match[0])
+		improvements = append(improvements, types.Improvement{
+			Type:        "type_safety",
+			Description: "Define a props interface instead of typing component props as 'any'",
+			Before:      code[match[0]:match[1]],
+			Reasoning:   "Typed props catch invalid usages of the component at compile time and document its API",
+			Priority:    "medium",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+var (
+	// guardRegex matches `function isFoo(x: unknown): boolean {` and `const isFoo = (x: unknown): boolean => {`
+	guardRegex      = regexp.MustCompile(`(?:function\s+(is[A-Z]\w*)\s*|(?:const|let)\s+(is[A-Z]\w*)\s*=\s*)\(\s*(\w+)(?:\s*:\s*[^)]*)?\)\s*:\s*boolean`)
+	instanceofRegex = regexp.MustCompile(`instanceof\s+(\w+)`)
+	typeofRegex     = regexp.MustCompile(`typeof\s+\w+\s*===?\s*['"](\w+)['"]`)
+)
+
+// Analyze finds boolean type guards that could return a type predicate
+func (typePredicatesCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	for _, match := range guardRegex.FindAllStringSubmatchIndex(code, -1) {
+		name := submatch(code, match, 1)
+		if name == "" {
+			name = submatch(code, match, 2)
+		}
+		param := submatch(code, match, 3)
+
+		body := blockAfter(code, match[1])
+		if !strings.Contains(body, "typeof") && !strings.Contains(body, "instanceof") {
+			continue
+		}
+
+		// Prefer the narrowed type from the guard body, falling back to the function name
+		predicateType := strings.TrimPrefix(name, "is")
+		if instanceofMatch := instanceofRegex.FindStringSubmatch(body); len(instanceofMatch) > 1 {
+			predicateType = instanceofMatch[1]
+		} else if typeofMatch := typeofRegex.FindStringSubmatch(body); len(typeofMatch) > 1 {
+			predicateType = typeofMatch[1]
+		}
+
+		signature := code[match[0]:match[1]]
+		line, column := lineColumnAt(code, match[0])
+		improvements = append(improvements, types.Improvement{
+			Type:        "typing",
+			Description: fmt.Sprintf("Type guard '%s' could return a type predicate", name),
+			Before:      signature,
+			After:       strings.TrimSuffix(signature, "boolean") + param + " is " + predicateType,
+			Reasoning:   fmt.Sprintf("Returning '%s is %s' lets TypeScript narrow '%s' at the call site", param, predicateType, param),
+			Priority:    "low",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// defaultLargeArrayThreshold is the inline array size flagged by largeDataCheck when none is configured
+const defaultLargeArrayThreshold = 100
+
+// iterationRegex matches an array method called directly on a closing bracket
+var iterationRegex = regexp.MustCompile(`\]\s*\.(map|filter|reduce|forEach|flatMap|some|every|find)\s*\(`)
+
+// Analyze flags large inline array literals that are iterated synchronously
+func (c largeDataCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	threshold := c.threshold
+	if threshold <= 0 {
+		threshold = defaultLargeArrayThreshold
+	}
+
+	for _, match := range iterationRegex.FindAllStringSubmatchIndex(code, -1) {
+		start := matchingOpenBracket(code, match[0])
+		if start < 0 || !isArrayLiteralStart(code, start) {
+			continue
+		}
+
+		elements := countArrayElements(code[start+1 : match[0]])
+		if elements <= threshold {
+			continue
+		}
+
+		line, column := lineColumnAt(code, start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "performance",
+			Description: fmt.Sprintf("Inline array with %d elements is processed synchronously with .%s()", elements, submatch(code, match, 1)),
+			Reasoning:   "Large inline data blocks the event loop when iterated in one pass; consider loading it from a file, streaming it, or processing it in chunks",
+			Priority:    "low",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// matchingOpenBracket returns the index of the '[' matching the ']' at end, or -1
+func matchingOpenBracket(code string, end int) int {
+	depth := 0
+	for i := end; i >= 0; i-- {
+		switch code[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isArrayLiteralStart reports whether the '[' at index opens an array literal rather than an index expression
+func isArrayLiteralStart(code string, index int) bool {
+	before := strings.TrimRight(code[:index], " \t\r\n")
+	if before == "" {
+		return true
+	}
+	last := before[len(before)-1]
+	return !(last == ')' || last == ']' || last == '_' || last == '$' ||
+		(last >= 'a' && last <= 'z') || (last >= 'A' && last <= 'Z') || (last >= '0' && last <= '9')) ||
+		strings.HasSuffix(before, "return")
+}
+
+// countArrayElements counts the top-level elements in the contents of an array literal
+func countArrayElements(contents string) int {
+	if strings.TrimSpace(contents) == "" {
+		return 0
+	}
+
+	count := 1
+	depth := 0
+	for i := 0; i < len(contents); i++ {
+		switch contents[i] {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				count++
+			}
+		}
+	}
+
+	// Allow a trailing comma
+	if strings.HasSuffix(strings.TrimSpace(contents), ",") {
+		count--
+	}
+
+	return count
+}
+
+var (
+	// numberRegex matches a numeric literal that isn't part of an identifier or property
+	numberRegex = regexp.MustCompile(`(?i)(?:^|[^\w$.])((?:0x[\da-f_]+|0b[01_]+|0o[0-7_]+|\d[\d_]*(?:\.\d[\d_]*)?(?:e[+-]?\d+)?|\.\d[\d_]*)n?)\b`)
+	// namedConstantRegex matches the start of a const or readonly declaration up to its '='
+	namedConstantRegex = regexp.MustCompile(`(?:\bconst|\breadonly)\s+[\w$]+\s*(?::[^=;]+)?=\s*-?\s*$`)
+	// typeAliasRegex matches a type alias declaration up to its '='
+	typeAliasRegex = regexp.MustCompile(`\btype\s+[\w$]+\s*(?:<[^>]*>)?\s*=`)
+	// annotatedNameRegex matches a declared variable or parameter name ahead of a ':'
+	annotatedNameRegex = regexp.MustCompile(`\b(?:const|let|var|readonly)\s+[\w$]+$|[(,]\s*[\w$]+$`)
+	// objectKeyRegex matches an object literal key and its ':'
+	objectKeyRegex = regexp.MustCompile(`[{,]\s*(?:[\w$]+|"[^"]*"|'[^']*')\s*:$`)
+	// constLiteralRegex matches a const declaration up to the bracket of an array or
+	// object literal initializer
+	constLiteralRegex = regexp.MustCompile(`\bconst\s+[\w$]+\s*(?::[^=;]+)?=\s*[\[{]`)
+)
+
+// Analyze flags numeric literals other than 0, 1, -1 and the allowed numbers, skipping
+// named constant declarations, enum members, the array and object literals a const is
+// initialized with (such as lookup tables), object literal values, array indexes and
+// type positions
+func (c magicNumbersCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	stripped := stripStringsAndComments(code)
+	bodies := append(enumBodies(stripped), constLiteralBodies(stripped)...)
+
+	for _, match := range numberRegex.FindAllStringSubmatchIndex(stripped, -1) {
+		start, end := match[2], match[3]
+		literal := stripped[start:end]
+
+		value, ok := numericValue(literal)
+		if !ok {
+			continue
+		}
+		before := strings.TrimRight(stripped[:start], " \t")
+		if strings.HasSuffix(before, "-") && isUnaryMinus(before[:len(before)-1]) {
+			value = -value
+			start = len(before) - 1
+			literal = "-" + literal
+			before = strings.TrimRight(stripped[:start], " \t")
+		}
+		if value == 0 || value == 1 || value == -1 || slices.Contains(c.allowed, value) {
+			continue
+		}
+		if isMagicNumberExempt(stripped, before, start, end, bodies) {
+			continue
+		}
+
+		line, column := lineColumnAt(code, start)
+		improvements = append(improvements, types.Improvement{
+			Type:        "code_cleanliness",
+			Description: fmt.Sprintf("Replace magic number %s with a named constant", literal),
+			Before:      code[start:end],
+			Reasoning:   "A named constant documents what the number means and keeps every use in sync when it changes",
+			Priority:    "low",
+			Line:        line,
+			Column:      column,
+		})
+	}
+
+	return improvements
+}
+
+// numericValue parses a TypeScript numeric literal, including separators, hex, binary,
+// octal and bigint forms
+func numericValue(literal string) (float64, bool) {
+	literal = strings.TrimSuffix(strings.ReplaceAll(strings.ToLower(literal), "_", ""), "n")
+	for prefix, base := range map[string]int{"0x": 16, "0b": 2, "0o": 8} {
+		if digits, ok := strings.CutPrefix(literal, prefix); ok {
+			n, err := strconv.ParseInt(digits, base, 64)
+			return float64(n), err == nil
+		}
+	}
+	value, err := strconv.ParseFloat(literal, 64)
+	return value, err == nil
+}
+
+// isUnaryMinus reports whether a '-' preceded by code negates the following literal
+// rather than subtracting from an operand
+func isUnaryMinus(code string) bool {
+	code = strings.TrimRight(code, " \t\r\n")
+	if code == "" {
+		return true
+	}
+	last := code[len(code)-1]
+	return !(isIdentifierByte(last) || last == ')' || last == ']')
+}
+
+// isMagicNumberExempt reports whether the literal at start:end is a named constant
+// initializer, inside one of the exempt bodies (enums and constant literals), an object
+// literal value, an array index or in a type position. before is the trimmed code ahead
+// of the literal.
+func isMagicNumberExempt(code, before string, start, end int, bodies [][2]int) bool {
+	lineStart := strings.LastIndex(code[:start], "\n") + 1
+	if namedConstantRegex.MatchString(code[lineStart:start]) || typeAliasRegex.MatchString(code[lineStart:start]) {
+		return true
+	}
+	for _, body := range bodies {
+		if start > body[0] && start < body[1] {
+			return true
+		}
+	}
+
+	// Index expressions such as items[2]
+	after := strings.TrimLeft(code[end:], " \t")
+	if strings.HasSuffix(before, "[") && strings.HasPrefix(after, "]") && !isArrayLiteralStart(code, len(before)-1) {
+		return true
+	}
+
+	// Object literal values, like ESLint's no-magic-numbers without detectObjects
+	if strings.HasSuffix(before, ":") && objectKeyRegex.MatchString(before[max(0, len(before)-200):]) {
+		return true
+	}
+
+	// Type annotations, literal union members and generic arguments
+	return strings.HasSuffix(before, ":") && !strings.HasSuffix(before, "?:") && isTypeAnnotation(before) ||
+		strings.HasSuffix(before, "|") && !strings.HasSuffix(before, "||") ||
+		strings.HasSuffix(before, "<") && strings.HasPrefix(after, ">")
+}
+
+// isTypeAnnotation reports whether the ':' ending before annotates a variable or
+// parameter rather than separating an object property or a ternary branch
+func isTypeAnnotation(before string) bool {
+	before = strings.TrimRight(strings.TrimSuffix(before, ":"), " \t")
+	if strings.HasSuffix(before, "?") || strings.HasSuffix(before, ")") {
+		// Optional parameters and return types
+		return true
+	}
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return annotatedNameRegex.MatchString(before[lineStart:])
+}
+
+// enumBodies returns the start and end offsets of every enum body in code
+func enumBodies(code string) [][2]int {
+	var bodies [][2]int
+	for _, match := range enumRegex.FindAllStringIndex(code, -1) {
+		open := match[1] - 1
+		if end := strings.IndexByte(code[open:], '}'); end >= 0 {
+			bodies = append(bodies, [2]int{open, open + end})
+		}
+	}
+	return bodies
+}
+
+// constLiteralBodies returns the start and end offsets of every array or object literal
+// that initializes a const in code
+func constLiteralBodies(code string) [][2]int {
+	var bodies [][2]int
+	for _, match := range constLiteralRegex.FindAllStringIndex(code, -1) {
+		open := match[1] - 1
+		if end := matchingClose(code, open); end >= 0 {
+			bodies = append(bodies, [2]int{open, end})
+		}
+	}
+	return bodies
+}
+
+// patternCache holds compiled guideline patterns by source; invalid patterns are stored as nil
+var patternCache sync.Map
+
+// compilePattern compiles a guideline pattern once, returning nil if it is invalid
+func compilePattern(pattern string) *regexp.Regexp {
+	if cached, ok := patternCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		compiled = nil
+	}
+	patternCache.Store(pattern, compiled)
+	return compiled
+}
+
+// guidelineConfidence is the Confidence of guideline matches, which are only as precise
+// as the rules and patterns they come from
+const guidelineConfidence = 0.8
+
+// applyGuidelines applies custom guidelines to the code analysis
+func (a *Analyzer) applyGuidelines(code string, guidelineSet *types.GuidelineSet) []types.Improvement {
+	var improvements []types.Improvement
+
+	for _, guideline := range guidelineSet.Guidelines {
+		// Simple pattern matching for guidelines
+		// In a real implementation, this would be more sophisticated
+		for _, rule := range guideline.Rules {
+			if rule == "" {
+				continue
+			}
+			if index := strings.Index(code, rule); index >= 0 {
+				line, column := lineColumnAt(code, index)
+				improvements = append(improvements, types.Improvement{
+					Type:         "guideline",
+					Description:  guideline.Description,
+					Before:       code[index : index+len(rule)],
+					Reasoning:    fmt.Sprintf("According to %s guidelines", guidelineSet.Name),
+					Priority:     guideline.Priority,
+					GuidelineRef: guideline.ID,
+					Confidence:   guidelineConfidence,
+					MatchedRule:  rule,
+					Line:         line,
+					Column:       column,
+				})
+			}
+		}
+
+		// Patterns are regular expressions authored in structured guideline files
+		for _, pattern := range guideline.Patterns {
+			patternRegex := compilePattern(pattern)
+			if patternRegex == nil {
+				continue
+			}
+			if match := patternRegex.FindStringIndex(code); match != nil && match[1] > match[0] {
+				line, column := lineColumnAt(code, match[0])
+				improvements = append(improvements, types.Improvement{
+					Type:         "guideline",
+					Description:  guideline.Description,
+					Before:       code[match[0]:match[1]],
+					Reasoning:    fmt.Sprintf("According to %s guidelines", guidelineSet.Name),
+					Priority:     guideline.Priority,
+					GuidelineRef: guideline.ID,
+					Confidence:   guidelineConfidence,
+					MatchedRule:  pattern,
+					Line:         line,
+					Column:       column,
+				})
+			}
+		}
+	}
+
+	return improvements
+}
+
+// deduplicateImprovements collapses improvements with the same type, description and
+// location into a single entry, recording how many times each one occurred
+func (a *Analyzer) deduplicateImprovements(improvements []types.Improvement) []types.Improvement {
+	type improvementKey struct {
+		improvementType string
+		description     string
+		line            int
+		column          int
+	}
+
+	var deduplicated []types.Improvement
+	seen := make(map[improvementKey]int)
+
+	for _, improvement := range improvements {
+		key := improvementKey{improvement.Type, improvement.Description, improvement.Line, improvement.Column}
+		if improvement.Occurrences == 0 {
+			improvement.Occurrences = 1
+		}
+		if index, ok := seen[key]; ok {
+			deduplicated[index].Occurrences += improvement.Occurrences
+			continue
+		}
+		seen[key] = len(deduplicated)
+		deduplicated = append(deduplicated, improvement)
+	}
+
+	return deduplicated
+}
+
+// priorityRank orders improvement priorities from least to most important
+var priorityRank = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// validatePriority returns an error unless priority is empty or a known priority
+func validatePriority(priority string) error {
+	if priority == "" || priorityRank[strings.ToLower(priority)] > 0 {
+		return nil
+	}
+	return fmt.Errorf("unknown min_priority %q (available: high, medium, low)", priority)
+}
+
+// improvementID derives a short deterministic ID from the improvement's type, description,
+// before text and location. IDs are unique within a file, so the same code always yields
+// the same IDs and clients pair them with the file path.
+func improvementID(improvement types.Improvement) string {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%d:%d", improvement.Type, improvement.Description, improvement.Before, improvement.Line, improvement.Column)
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:6])
+}
+
+// sortByPriority orders improvements from high to low priority, then by type.
+// The sort is stable, so improvements of the same priority and type keep their order.
+func sortByPriority(improvements []types.Improvement) {
+	sort.SliceStable(improvements, func(i, j int) bool {
+		rankI := priorityRank[strings.ToLower(improvements[i].Priority)]
+		rankJ := priorityRank[strings.ToLower(improvements[j].Priority)]
+		if rankI != rankJ {
+			return rankI > rankJ
+		}
+		return improvements[i].Type < improvements[j].Type
+	})
+}
+
+// filterImprovements drops improvements below minPriority or minConfidence or outside the
+// requested types
+func (a *Analyzer) filterImprovements(improvements []types.Improvement, minPriority string, minConfidence float64, improvementTypes []string) []types.Improvement {
+	minRank := priorityRank[strings.ToLower(minPriority)]
+	if minRank == 0 && minConfidence <= 0 && len(improvementTypes) == 0 {
+		return improvements
+	}
+
+	allowedTypes := make(map[string]bool, len(improvementTypes))
+	for _, improvementType := range improvementTypes {
+		allowedTypes[improvementType] = true
+	}
+
+	var filtered []types.Improvement
+	for _, improvement := range improvements {
+		if priorityRank[improvement.Priority] < minRank || improvement.Confidence < minConfidence {
+			continue
+		}
+		if len(allowedTypes) > 0 && !allowedTypes[improvement.Type] {
+			continue
+		}
+		filtered = append(filtered, improvement)
+	}
+
+	return filtered
+}
+
+// stripStringsAndComments blanks out string literals and comments with spaces so that
+// pattern checks don't match inside them. Offsets and newlines are preserved.
+func stripStringsAndComments(code string) string {
+	stripped := []byte(code)
+
+	for i := 0; i < len(stripped); i++ {
+		switch {
+		case strings.HasPrefix(code[i:], "//"):
+			for ; i < len(stripped) && stripped[i] != '\n'; i++ {
+				stripped[i] = ' '
+			}
+		case strings.HasPrefix(code[i:], "/*"):
+			end := strings.Index(code[i+2:], "*/")
+			stop := len(stripped)
+			if end >= 0 {
+				stop = i + 2 + end + 2
+			}
+			for ; i < stop; i++ {
+				if stripped[i] != '\n' {
+					stripped[i] = ' '
+				}
+			}
+			i--
+		case code[i] == '"' || code[i] == '\'' || code[i] == '`':
+			quote := code[i]
+			for i++; i < len(stripped) && code[i] != quote; i++ {
+				if code[i] == '\\' && i+1 < len(stripped) {
+					stripped[i] = ' '
+					i++
+				}
+				if stripped[i] != '\n' {
+					stripped[i] = ' '
+				}
+			}
+		}
+	}
+
+	return string(stripped)
+}
+
+// submatch returns the text of capture group n from a FindStringSubmatchIndex result
+func submatch(code string, match []int, n int) string {
+	if match[2*n] < 0 {
+		return ""
+	}
+	return code[match[2*n]:match[2*n+1]]
+}
+
+// blockAfter returns the brace-delimited block or arrow expression that starts after offset
+func blockAfter(code string, offset int) string {
+	rest := code[offset:]
+	start := strings.IndexAny(rest, "{;\n")
+	if start < 0 {
+		return rest
+	}
+	if rest[start] != '{' {
+		// Expression-bodied arrow function: take the rest of the line
+		return rest[:start]
+	}
+
+	depth := 0
+	for i := start; i < len(rest); i++ {
+		switch rest[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return rest[start : i+1]
+			}
+		}
+	}
+	return rest[start:]
+}
+
+// toPascalCase upper-cases the first letter of an identifier
+func toPascalCase(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	if first == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// toCamelCase lower-cases the leading letter of an identifier, treating a leading
+// acronym as one word so that "URLParser" becomes "urlParser" and "URL" becomes "url"
+func toCamelCase(name string) string {
+	runes := []rune(name)
+
+	upperRun := 0
+	for upperRun < len(runes) && unicode.IsUpper(runes[upperRun]) {
+		upperRun++
+	}
+
+	// Keep the last capital of an acronym when it starts the next word
+	if upperRun > 1 && upperRun < len(runes) && unicode.IsLower(runes[upperRun]) {
+		upperRun--
+	}
+	if upperRun == 0 {
+		upperRun = 1
+	}
+
+	for i := 0; i < upperRun && i < len(runes); i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// lineDiff renders a unified-style diff between before and after, marking removed
+// lines with '-', added lines with '+' and unchanged lines with ' '. Lines are aligned
+// using their longest common subsequence.
+func lineDiff(before, after string) string {
+	oldLines := strings.Split(before, "\n")
+	newLines := strings.Split(after, "\n")
+
+	// lcs[i][j] is the LCS length of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			diff.WriteString(" " + oldLines[i] + "\n")
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff.WriteString("-" + oldLines[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+" + newLines[j] + "\n")
+			j++
+		}
+	}
+
+	return diff.String()
+}
+
+// lineColumnAt converts a byte offset in code into a 1-based line and column
+func lineColumnAt(code string, offset int) (int, int) {
+	prefix := code[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	column := offset - strings.LastIndex(prefix, "\n")
+	return line, column
+}
+
+// generateImprovementSummary creates a summary of all improvements
+func (a *Analyzer) generateImprovementSummary(improvements []types.Improvement) string {
+	if len(improvements) == 0 {
+		return "No improvements suggested. Code follows TypeScript best practices."
+	}
+
+	highPriority := 0
+	mediumPriority := 0
+	lowPriority := 0
+
+	for _, improvement := range improvements {
+		switch improvement.Priority {
+		case "high":
+			highPriority++
+		case "medium":
+			mediumPriority++
+		case "low":
+			lowPriority++
+		}
+	}
+
+	return fmt.Sprintf("Found %d improvement suggestions: %d high priority, %d medium priority, %d low priority (score %d/100)",
+		len(improvements), highPriority, mediumPriority, lowPriority, a.qualityScore(improvements))
+}
+
+// ApplyImprovement replaces the improvement's Before text with its After text in filePath.
+// When Before occurs more than once, imp.Line selects the occurrence to replace. An error
+// is returned if Before no longer matches, e.g. because the file changed since analysis.
+// With backup set, the original is copied to filePath.bak just before the file is
+// written, so a failed match leaves any previous backup alone, and the backup path is
+// returned.
+func (a *Analyzer) ApplyImprovement(filePath string, imp types.Improvement, backup bool) (string, error) {
+	if imp.Before == "" {
+		return "", fmt.Errorf("improvement has no 'before' text to replace")
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to access file: %w", err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	code := string(content)
+
+	var offsets []int
+	for start := 0; ; {
+		index := strings.Index(code[start:], imp.Before)
+		if index < 0 {
+			break
+		}
+		offsets = append(offsets, start+index)
+		start += index + len(imp.Before)
+	}
+
+	if len(offsets) == 0 {
+		return "", fmt.Errorf("'before' text no longer matches %s; re-run the analysis", filePath)
+	}
+
+	offset := -1
+	if imp.Line > 0 {
+		for _, candidate := range offsets {
+			startLine, _ := lineColumnAt(code, candidate)
+			endLine := startLine + strings.Count(imp.Before, "\n")
+			if startLine <= imp.Line && imp.Line <= endLine {
+				offset = candidate
+				break
+			}
+		}
+		if offset < 0 {
+			return "", fmt.Errorf("'before' text not found on line %d of %s; re-run the analysis", imp.Line, filePath)
+		}
+	} else if len(offsets) > 1 {
+		return "", fmt.Errorf("'before' text appears %d times in %s; specify the line to change", len(offsets), filePath)
+	} else {
+		offset = offsets[0]
+	}
+
+	backupPath := ""
+	if backup {
+		backupPath = filePath + ".bak"
+		if err := os.WriteFile(backupPath, content, info.Mode().Perm()); err != nil {
+			return "", fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+
+	updated := code[:offset] + imp.After + code[offset+len(imp.Before):]
+	if err := os.WriteFile(filePath, []byte(updated), info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// LoadGuidelines loads custom guidelines from a guideline set
+func (a *Analyzer) LoadGuidelines(guidelineSet *types.GuidelineSet) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.guidelines[guidelineSet.Name] = guidelineSet
+}
+
+// GetLoadedGuidelines returns a copy of the loaded guidelines keyed by set name
+func (a *Analyzer) GetLoadedGuidelines() map[string]*types.GuidelineSet {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	guidelines := make(map[string]*types.GuidelineSet, len(a.guidelines))
+	for name, guidelineSet := range a.guidelines {
+		guidelines[name] = guidelineSet
+	}
+	return guidelines
 }
\ No newline at end of file
@@ -0,0 +1,521 @@
+package typescript
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// improvementsOfType returns the improvements of the given type
+func improvementsOfType(improvements []types.Improvement, improvementType string) []types.Improvement {
+	var matched []types.Improvement
+	for _, imp := range improvements {
+		if imp.Type == improvementType {
+			matched = append(matched, imp)
+		}
+	}
+	return matched
+}
+
+func TestTypePredicatesFlagsBooleanGuard(t *testing.T) {
+	code := `class ApiError extends Error {}
+
+function isApiError(value: unknown): boolean {
+  return value instanceof ApiError;
+}
+
+const isText = (value: unknown): boolean => {
+  return typeof value === "string";
+};
+`
+	improvements := typePredicatesCheck{}.Analyze(code)
+	if len(improvements) != 2 {
+		t.Fatalf("Analyze = %+v, want 2 improvements", improvements)
+	}
+
+	guard := improvements[0]
+	if guard.Type != "typing" || guard.Priority != "low" {
+		t.Errorf("Type = %q, Priority = %q, want typing and low", guard.Type, guard.Priority)
+	}
+	if guard.Line != 3 {
+		t.Errorf("Line = %d, want 3", guard.Line)
+	}
+	if want := "function isApiError(value: unknown): value is ApiError"; guard.After != want {
+		t.Errorf("After = %q, want %q", guard.After, want)
+	}
+	if want := "const isText = (value: unknown): value is string"; improvements[1].After != want {
+		t.Errorf("After = %q, want %q", improvements[1].After, want)
+	}
+}
+
+func TestTypePredicatesSkipsPredicatesAndPlainBooleans(t *testing.T) {
+	code := `function isApiError(value: unknown): value is ApiError {
+  return value instanceof ApiError;
+}
+
+function isEnabled(flags: Flags): boolean {
+  return flags.enabled && !flags.disabled;
+}
+`
+	if improvements := (typePredicatesCheck{}).Analyze(code); len(improvements) != 0 {
+		t.Errorf("Analyze = %+v, want none", improvements)
+	}
+}
+
+func TestTypePredicatesIsOptIn(t *testing.T) {
+	code := `function isDate(value: unknown): boolean {
+  return value instanceof Date;
+}
+`
+	analyzer := NewAnalyzer()
+
+	result, err := analyzer.SuggestImprovements(types.SuggestImprovementsParams{CodeSnippet: code})
+	if err != nil {
+		t.Fatalf("SuggestImprovements: %v", err)
+	}
+	for _, imp := range result.Improvements {
+		if strings.Contains(imp.Description, "type predicate") {
+			t.Errorf("type_predicates ran without being enabled: %+v", imp)
+		}
+	}
+
+	result, err = analyzer.SuggestImprovements(types.SuggestImprovementsParams{CodeSnippet: code, OptionalChecks: []string{"type_predicates"}})
+	if err != nil {
+		t.Fatalf("SuggestImprovements: %v", err)
+	}
+	var found bool
+	for _, imp := range improvementsOfType(result.Improvements, "typing") {
+		found = found || imp.After == "function isDate(value: unknown): value is Date"
+	}
+	if !found {
+		t.Errorf("Improvements = %+v, want the isDate predicate", result.Improvements)
+	}
+}
+
+// inlineArray returns an array literal of n object elements
+func inlineArray(n int) string {
+	elements := make([]string, n)
+	for i := range elements {
+		elements[i] = fmt.Sprintf("{ id: %d, tags: [\"a\", \"b\"] }", i)
+	}
+	return "[\n  " + strings.Join(elements, ",\n  ") + ",\n]"
+}
+
+func TestLargeDataFlagsLargeInlineArray(t *testing.T) {
+	code := "const ids = " + inlineArray(150) + ".map((row) => row.id);\n"
+
+	improvements := largeDataCheck{}.Analyze(code)
+	if len(improvements) != 1 {
+		t.Fatalf("Analyze = %+v, want 1 improvement", improvements)
+	}
+	imp := improvements[0]
+	if imp.Type != "performance" || imp.Priority != "low" {
+		t.Errorf("Type = %q, Priority = %q, want performance and low", imp.Type, imp.Priority)
+	}
+	if !strings.Contains(imp.Description, "150 elements") || !strings.Contains(imp.Description, ".map()") {
+		t.Errorf("Description = %q, want the element count and method", imp.Description)
+	}
+	if imp.Line != 1 || imp.Column != 13 {
+		t.Errorf("position = %d:%d, want 1:13", imp.Line, imp.Column)
+	}
+}
+
+func TestLargeDataSkipsSmallArraysAndIndexing(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"small array", "const ids = " + inlineArray(10) + ".map((row) => row.id);\n"},
+		{"at the threshold", "const ids = " + inlineArray(100) + ".filter(Boolean);\n"},
+		{"not iterated", "const rows = " + inlineArray(150) + ";\n"},
+		{"index expression", "const rows = " + inlineArray(150) + ";\nconst ids = rows[0].tags.map((tag) => tag);\n"},
+	}
+	for _, tt := range tests {
+		if improvements := (largeDataCheck{}).Analyze(tt.code); len(improvements) != 0 {
+			t.Errorf("%s: Analyze = %+v, want none", tt.name, improvements)
+		}
+	}
+}
+
+func TestLargeDataThreshold(t *testing.T) {
+	code := "export const total = " + inlineArray(20) + ".reduce((sum, row) => sum + row.id, 0);\n"
+	analyzer := NewAnalyzer()
+
+	result, err := analyzer.SuggestImprovements(types.SuggestImprovementsParams{CodeSnippet: code, OptionalChecks: []string{"large_data"}})
+	if err != nil {
+		t.Fatalf("SuggestImprovements: %v", err)
+	}
+	if got := improvementsOfType(result.Improvements, "performance"); len(got) != 0 {
+		t.Errorf("Improvements = %+v, want none below the default threshold", got)
+	}
+
+	result, err = analyzer.SuggestImprovements(types.SuggestImprovementsParams{CodeSnippet: code, OptionalChecks: []string{"large_data"}, LargeArrayThreshold: 5})
+	if err != nil {
+		t.Fatalf("SuggestImprovements: %v", err)
+	}
+	if got := improvementsOfType(result.Improvements, "performance"); len(got) != 1 {
+		t.Errorf("Improvements = %+v, want the 20 element array flagged", got)
+	}
+}
+
+func TestToPascalCase(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"userProfile", "UserProfile"},
+		{"éspecial", "Éspecial"},
+		{"ñandú", "Ñandú"},
+		{"straße", "Straße"},
+		{"URLParser", "URLParser"},
+		{"_private", "_private"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := toPascalCase(tt.name); got != tt.want {
+			t.Errorf("toPascalCase(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestToCamelCase(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"UserProfile", "userProfile"},
+		{"Éspecial", "éspecial"},
+		{"ÉtatCourant", "étatCourant"},
+		{"URLParser", "urlParser"},
+		{"HTTPServerError", "httpServerError"},
+		{"URL", "url"},
+		{"IO", "io"},
+		{"ÀÉParser", "àéParser"},
+		{"already", "already"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := toCamelCase(tt.name); got != tt.want {
+			t.Errorf("toCamelCase(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNamingConventionsSuggestsUnicodeNames(t *testing.T) {
+	code := "interface éspecial {}\nlet ÉtatCourant = 1;\nlet URLParser = 2;\n"
+
+	suggestions := make(map[string]string)
+	for _, imp := range (namingConventionsCheck{rules: DefaultNamingRules}).Analyze(code) {
+		suggestions[imp.Before] = imp.After
+	}
+	want := map[string]string{
+		"éspecial":    "Éspecial",
+		"ÉtatCourant": "étatCourant",
+		"URLParser":   "urlParser",
+	}
+	for before, after := range want {
+		if suggestions[before] != after {
+			t.Errorf("suggestion for %q = %q, want %q", before, suggestions[before], after)
+		}
+	}
+}
+
+// guidelineSet returns a set named name with one rule matching "console.log"
+func guidelineSet(name string) *types.GuidelineSet {
+	return &types.GuidelineSet{
+		Name: name,
+		Guidelines: []types.Guideline{{
+			ID:          name + "_1",
+			Title:       "No console output",
+			Description: "Use the logger instead of console.log",
+			Priority:    "medium",
+			Rules:       []string{"console.log"},
+		}},
+	}
+}
+
+// TestGuidelinesConcurrentAccess is meant to run with -race: tool handlers load and
+// read guidelines concurrently
+func TestGuidelinesConcurrentAccess(t *testing.T) {
+	analyzer := NewAnalyzer()
+	const writers, readers, rounds = 4, 4, 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				analyzer.LoadGuidelines(guidelineSet(fmt.Sprintf("set-%d-%d", w, i)))
+			}
+		}()
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				for name, set := range analyzer.GetLoadedGuidelines() {
+					if set.Name != name {
+						t.Errorf("guideline set %q stored under %q", set.Name, name)
+					}
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds/5; i++ {
+				if _, err := analyzer.SuggestImprovements(types.SuggestImprovementsParams{CodeSnippet: "console.log(value);\n"}); err != nil {
+					t.Errorf("SuggestImprovements: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if loaded := analyzer.GetLoadedGuidelines(); len(loaded) != writers*rounds {
+		t.Errorf("loaded %d guideline sets, want %d", len(loaded), writers*rounds)
+	}
+}
+
+func TestGetLoadedGuidelinesReturnsCopy(t *testing.T) {
+	analyzer := NewAnalyzer()
+	analyzer.LoadGuidelines(guidelineSet("team"))
+
+	loaded := analyzer.GetLoadedGuidelines()
+	delete(loaded, "team")
+	loaded["injected"] = guidelineSet("injected")
+
+	current := analyzer.GetLoadedGuidelines()
+	if _, ok := current["team"]; !ok || len(current) != 1 {
+		t.Errorf("GetLoadedGuidelines = %v, want only team after changing a returned map", current)
+	}
+}
+
+// benchmarkSnippet returns a module of about 1000 lines of interfaces, classes, async
+// code and type guards, with something for most checks to report
+func benchmarkSnippet() string {
+	var b strings.Builder
+	b.WriteString("import { readFile } from \"fs/promises\";\nimport * as path from \"path\";\n\n")
+	for i := 0; strings.Count(b.String(), "\n") < 1000; i++ {
+		fmt.Fprintf(&b, `export interface Record%[1]d {
+  id: number;
+  name: string;
+  payload: any;
+}
+
+export class Service%[1]d {
+  private cache = new Map<string, Record%[1]d>();
+
+  async load(id: string) {
+    const data = await readFile(path.join("data", id + ".json"), "utf8");
+    const record = JSON.parse(data) as Record%[1]d;
+    if (record.id == %[1]d) {
+      console.log("loaded", record.name);
+    }
+    for (var i = 0; i < 42; i++) {
+      if (i %% 2 === 0 && record.payload) {
+        this.cache.set(record.name + i, record!);
+      }
+    }
+    return record;
+  }
+}
+
+function isRecord%[1]d(value: unknown): boolean {
+  return value instanceof Service%[1]d;
+}
+
+`, i)
+	}
+	return b.String()
+}
+
+func BenchmarkSuggestImprovements(b *testing.B) {
+	code := benchmarkSnippet()
+	analyzer := NewAnalyzer()
+	params := types.SuggestImprovementsParams{CodeSnippet: code}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(code)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := analyzer.SuggestImprovements(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSuggestImprovementsAllChecks(b *testing.B) {
+	code := benchmarkSnippet()
+	analyzer := NewAnalyzer()
+	params := types.SuggestImprovementsParams{
+		CodeSnippet:    code,
+		OptionalChecks: []string{"type_predicates", "large_data", "magic_numbers"},
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(code)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := analyzer.SuggestImprovements(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestBenchmarkSnippet(t *testing.T) {
+	code := benchmarkSnippet()
+	if lines := strings.Count(code, "\n"); lines < 1000 || lines > 1100 {
+		t.Fatalf("benchmark snippet has %d lines, want about 1000", lines)
+	}
+	result, err := NewAnalyzer().SuggestImprovements(types.SuggestImprovementsParams{CodeSnippet: code})
+	if err != nil {
+		t.Fatalf("SuggestImprovements: %v", err)
+	}
+	if result.TotalIssues == 0 {
+		t.Error("the benchmark snippet triggers no checks")
+	}
+}
+
+func TestTypeAnnotationsAnnotatedDeclarations(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"const", "const retries: number = 5;\n"},
+		{"let", "let names: string[] = [];\n"},
+		{"generic", "const cache: Map<string, User> = new Map();\n"},
+		{"union", "let status: 'idle' | 'busy' = 'idle';\n"},
+		{"in a string", "const message: string = \"let x = 1\";\n"},
+		{"in a comment", "// const x = 1\nconst y: number = 2;\n"},
+		{"typed parameters", "function add(a: number, b = 2, { id }: User, ...rest: number[]): number {\n  return a + b + id + rest.length;\n}\n"},
+	}
+	for _, tt := range tests {
+		if improvements := (typeAnnotationsCheck{}).Analyze(tt.code); len(improvements) != 0 {
+			t.Errorf("%s: Analyze = %+v, want none", tt.name, improvements)
+		}
+	}
+}
+
+func TestTypeAnnotationsUnannotatedDeclarations(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		wantType string
+	}{
+		{"const", "const retries = 5;\n", "type_annotation"},
+		{"let", "let names = [];\n", "type_annotation"},
+		{"var", "var total = 0;\n", "type_annotation"},
+		{"next to an annotated one", "const a: number = 1;\nconst b = 2;\n", "type_annotation"},
+		{"untyped parameter", "function add(a: number, b): number {\n  return a + b;\n}\n", "function_types"},
+		{"untyped destructuring", "function load({ id, name }) {\n  return id + name;\n}\n", "function_types"},
+	}
+	for _, tt := range tests {
+		improvements := (typeAnnotationsCheck{}).Analyze(tt.code)
+		if len(improvements) != 1 || improvements[0].Type != tt.wantType {
+			t.Errorf("%s: Analyze = %+v, want one %s improvement", tt.name, improvements, tt.wantType)
+		}
+	}
+}
+
+// hasAssertionStyle reports whether improvements include the angle-bracket assertion advice
+func hasAssertionStyle(improvements []types.Improvement) bool {
+	return len(improvementsOfType(improvements, "assertion_style")) > 0
+}
+
+func TestTypeAssertionsFlagsAngleBrackets(t *testing.T) {
+	tests := []string{
+		"const x = <Foo>bar;\n",
+		"const list = <User[]>JSON.parse(data);\n",
+		"return <Array<Item>>items;\n",
+		"handle(<Event>event, <string>\"name\");\n",
+		"const value = <number>(input * 2);\n",
+	}
+	for _, code := range tests {
+		if !hasAssertionStyle((typeAssertionsCheck{}).Analyze(code)) {
+			t.Errorf("Analyze(%q) did not flag the angle-bracket assertion", code)
+		}
+	}
+}
+
+func TestTypeAssertionsSkipsGenericsAndJSX(t *testing.T) {
+	tests := []string{
+		"const values: Array<number> = [];\n",
+		"const lookup = new Map<string, User>();\n",
+		"const ids = useState<number>(0);\n",
+		"function first<T>(items: T[]): T {\n  return items[0];\n}\n",
+		"const identity = <T>(value: T): T => value;\n",
+		"const view = <Component />;\n",
+		"const view = <Layout>content</Layout>;\n",
+		"const message = \"use <Foo>bar to assert\";\n",
+		"const x = bar as Foo;\n",
+	}
+	for _, code := range tests {
+		if improvements := (typeAssertionsCheck{}).Analyze(code); hasAssertionStyle(improvements) {
+			t.Errorf("Analyze(%q) = %+v, want no angle-bracket advice", code, improvements)
+		}
+	}
+}
+
+func TestVarSuggestsKeyword(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"never reassigned", "var total = 0;\nconsole.log(total);\n", "const"},
+		{"annotated", "var lookup: Map<string, number> = new Map();\n", "const"},
+		{"compared", "var total = 0;\nif (total == 1 || total === 2) {}\n", "const"},
+		{"uninitialized", "var total;\ntotal = 1;\n", "let"},
+		{"assigned", "var total = 0;\ntotal = 1;\n", "let"},
+		{"compound assignment", "var total = 0;\ntotal += 2;\n", "let"},
+		{"dollar postfix increment", "var $count = 0;\n$count++;\n", "let"},
+		{"dollar prefix decrement", "var $count = 0;\n--$count;\n", "let"},
+		{"multiple declarators", "var total = 0, i;\n", "let"},
+		{"multiple declarators on two lines", "var total = 0,\n  i = 1;\n", "let"},
+		{"array destructuring", "var n = 0;\n[n] = pair;\n", "let"},
+		{"nested array destructuring", "var n = 0;\n[a, [n]] = pairs;\n", "let"},
+		{"object destructuring", "var n = 0;\n({ n } = point);\n", "let"},
+		{"renamed object destructuring", "var n = 0;\n({ x: n, y } = point);\n", "let"},
+		{"for of", "var n = 0;\nfor (n of items) {}\n", "let"},
+		{"for in", "var key = \"\";\nfor (key in record) {}\n", "let"},
+	}
+	for _, tt := range tests {
+		improvements := (varCheck{}).Analyze(tt.code)
+		if len(improvements) != 1 {
+			t.Errorf("%s: Analyze = %+v, want one improvement", tt.name, improvements)
+			continue
+		}
+		if want := "Use '" + tt.want + "' instead of 'var'"; improvements[0].Description != want {
+			t.Errorf("%s: Description = %q, want %q", tt.name, improvements[0].Description, want)
+		}
+	}
+}
+
+func TestVarIgnoresOtherIdentifiers(t *testing.T) {
+	code := "var count = 0;\nobj.count = 1;\ncountAll++;\nconst [count2] = pair;\nfoo({ count });\nfor (const x of count) {}\n"
+	improvements := (varCheck{}).Analyze(code)
+	if len(improvements) != 1 || improvements[0].After != "const count" {
+		t.Errorf("Analyze = %+v, want const count", improvements)
+	}
+}
+
+func TestUsesEnumAtRuntime(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"const names = Object.keys(Color);", true},
+		{"for (const [k, v] of Object.entries( Color )) {}", true},
+		{"const label = Color[key];", true},
+		{"lookup[Color[key]];", true},
+		{"const $Color = 1; $Color[0];", false},
+		{"const c = Color.Red;", false},
+		{"const names = Object.keys(ColorNames);", false},
+		{"const names = MyObject.keys(Color);", false},
+		{"theme.Color[key];", false},
+	}
+	for _, tt := range tests {
+		if got := usesEnumAtRuntime(tt.code, "Color"); got != tt.want {
+			t.Errorf("usesEnumAtRuntime(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package typescript
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcp-typescript-assistant/internal/paths"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// AnalyzerCheck is a named analysis that suggest-improvements runs over a piece of code.
+// Implementations must be safe for concurrent use.
+type AnalyzerCheck interface {
+	// Name identifies the check in EnabledChecks, DisabledChecks and AppliedRules
+	Name() string
+	// Analyze returns the improvements the check suggests for code
+	Analyze(code string) []types.Improvement
+}
+
+// configurableCheck is implemented by checks whose behavior depends on the request
+type configurableCheck interface {
+	withParams(params types.SuggestImprovementsParams) AnalyzerCheck
+}
+
+// registeredCheck is an AnalyzerCheck and how the analyzer schedules it
+type registeredCheck struct {
+	check AnalyzerCheck
+	// optional checks only run when enabled through OptionalChecks or EnabledChecks
+	optional bool
+	// applies reports whether the check is relevant to code; nil means always
+	applies func(code string, params types.SuggestImprovementsParams) bool
+	// confidence is how reliable the check's matches are, from 0 to 1, for the
+	// improvements it returns without a Confidence of their own
+	confidence float64
+}
+
+// defaultConfidence is the Confidence of improvements from registered checks that don't
+// set one
+const defaultConfidence = 0.5
+
+// Built-in checks
+type (
+	typeAnnotationsCheck   struct{}
+	namingConventionsCheck struct{ rules map[string]string }
+	importExportsCheck     struct{}
+	asyncAwaitCheck        struct{}
+	floatingPromisesCheck  struct{}
+	typeAssertionsCheck    struct{}
+	weakTypesCheck         struct{}
+	utilityTypesCheck      struct{}
+	nonNullAssertionsCheck struct{}
+	equalityCheck          struct{}
+	varCheck               struct{}
+	enumsCheck             struct{}
+	exhaustiveSwitchCheck  struct{}
+	complexityCheck        struct{ threshold int }
+	returnTypesCheck       struct{ all bool }
+	functionLengthCheck    struct{ maxLines int }
+	parameterCountCheck    struct{ maxParams int }
+	unusedCheck            struct{}
+	reactCheck             struct{}
+	typePredicatesCheck    struct{}
+	consoleCheck           struct{ allowConsole *bool }
+	largeDataCheck         struct{ threshold int }
+	magicNumbersCheck      struct{ allowed []float64 }
+)
+
+func (typeAnnotationsCheck) Name() string   { return "type_annotations" }
+func (namingConventionsCheck) Name() string { return "naming_conventions" }
+func (importExportsCheck) Name() string     { return "imports_exports" }
+func (asyncAwaitCheck) Name() string        { return "async_await" }
+func (floatingPromisesCheck) Name() string  { return "floating_promises" }
+func (typeAssertionsCheck) Name() string    { return "type_assertions" }
+func (weakTypesCheck) Name() string         { return "weak_types" }
+func (utilityTypesCheck) Name() string      { return "utility_types" }
+func (nonNullAssertionsCheck) Name() string { return "non_null_assertions" }
+func (equalityCheck) Name() string          { return "equality" }
+func (varCheck) Name() string               { return "var_declarations" }
+func (enumsCheck) Name() string             { return "enums" }
+func (exhaustiveSwitchCheck) Name() string  { return "exhaustive_switch" }
+func (complexityCheck) Name() string        { return "complexity" }
+func (returnTypesCheck) Name() string       { return "return_types" }
+func (functionLengthCheck) Name() string    { return "function_length" }
+func (parameterCountCheck) Name() string    { return "parameter_count" }
+func (consoleCheck) Name() string           { return "console" }
+func (unusedCheck) Name() string            { return "unused" }
+func (reactCheck) Name() string             { return "react" }
+func (typePredicatesCheck) Name() string    { return "type_predicates" }
+func (largeDataCheck) Name() string         { return "large_data" }
+func (magicNumbersCheck) Name() string      { return "magic_numbers" }
+
+func (consoleCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return consoleCheck{allowConsole: params.AllowConsole}
+}
+
+func (largeDataCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return largeDataCheck{threshold: params.LargeArrayThreshold}
+}
+
+func (complexityCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return complexityCheck{threshold: params.ComplexityThreshold}
+}
+
+func (returnTypesCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return returnTypesCheck{all: params.ReturnTypeScope == "all"}
+}
+
+func (functionLengthCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return functionLengthCheck{maxLines: params.MaxFunctionLines}
+}
+
+func (parameterCountCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return parameterCountCheck{maxParams: params.MaxParams}
+}
+
+func (magicNumbersCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return magicNumbersCheck{allowed: params.AllowedNumbers}
+}
+
+// builtinChecks returns the built-in checks, in the order they run
+func builtinChecks() []registeredCheck {
+	return []registeredCheck{
+		{check: typeAnnotationsCheck{}, applies: isTypeScriptCode, confidence: 0.5},
+		{check: returnTypesCheck{}, applies: isTypeScriptCode, confidence: 0.8},
+		{check: namingConventionsCheck{}, confidence: 0.9},
+		{check: importExportsCheck{}, confidence: 0.6},
+		{check: asyncAwaitCheck{}, confidence: 0.6},
+		{check: floatingPromisesCheck{}, confidence: 0.7},
+		{check: typeAssertionsCheck{}, applies: isTypeScriptCode, confidence: 0.9},
+		{check: weakTypesCheck{}, applies: isTypeScriptCode, confidence: 0.85},
+		{check: utilityTypesCheck{}, applies: isTypeScriptCode, confidence: 0.4},
+		{check: nonNullAssertionsCheck{}, applies: isTypeScriptCode, confidence: 0.95},
+		{check: equalityCheck{}, confidence: 0.95},
+		{check: varCheck{}, confidence: 0.95},
+		{check: enumsCheck{}, applies: isTypeScriptCode, confidence: 0.9},
+		{check: exhaustiveSwitchCheck{}, applies: isTypeScriptCode, confidence: 0.8},
+		{check: complexityCheck{}, confidence: 0.7},
+		{check: functionLengthCheck{}, confidence: 0.85},
+		{check: parameterCountCheck{}, confidence: 0.85},
+		{check: consoleCheck{}, confidence: 0.95},
+		{check: unusedCheck{}, confidence: 0.6},
+		{check: reactCheck{}, applies: isReactCode, confidence: 0.7},
+		{check: typePredicatesCheck{}, optional: true, applies: isTypeScriptCode, confidence: 0.6},
+		{check: largeDataCheck{}, optional: true, confidence: 0.7},
+		{check: magicNumbersCheck{}, optional: true, confidence: 0.7},
+	}
+}
+
+// isTypeScriptCode reports whether the TypeScript-only checks, about types, enums and
+// assertions, should run on code: everything but JavaScript
+func isTypeScriptCode(code string, params types.SuggestImprovementsParams) bool {
+	return !paths.IsJavaScript(params.Language)
+}
+
+// isReactCode reports whether the React checks should run on code
+func isReactCode(code string, params types.SuggestImprovementsParams) bool {
+	return params.Language == "tsx" || params.Language == "jsx" || ContainsJSX(code)
+}
+
+// RegisterCheck adds a custom check that runs after the built-in checks on every
+// analysis, unless disabled by name
+func (a *Analyzer) RegisterCheck(check AnalyzerCheck) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, registered := range a.checks {
+		if registered.check.Name() == check.Name() {
+			return fmt.Errorf("a check named %q is already registered", check.Name())
+		}
+	}
+	a.checks = append(a.checks, registeredCheck{check: check})
+	return nil
+}
+
+// CheckNames returns the names of every registered check, including optional ones
+func (a *Analyzer) CheckNames() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.checkNames()
+}
+
+// checkNames lists the registered check names; the caller must hold a.mu
+func (a *Analyzer) checkNames() []string {
+	names := make([]string, len(a.checks))
+	for i, registered := range a.checks {
+		names[i] = registered.check.Name()
+	}
+	return names
+}
+
+// SetCheckDefaults sets the checks enabled and disabled for every analysis unless a
+// request overrides them. An empty enabled list runs every non-optional check.
+func (a *Analyzer) SetCheckDefaults(enabled, disabled []string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := validateCheckNames(a.checkNames(), slices.Concat(enabled, disabled)); err != nil {
+		return err
+	}
+	a.enabledChecks = enabled
+	a.disabledChecks = disabled
+	return nil
+}
+
+// selectChecks returns the checks to run for params, configured for the request.
+// EnabledChecks replaces the default set, OptionalChecks adds to it and DisabledChecks
+// (from params or the analyzer defaults) removes from it.
+func (a *Analyzer) selectChecks(params types.SuggestImprovementsParams) ([]registeredCheck, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if err := validateCheckNames(a.checkNames(), slices.Concat(params.EnabledChecks, params.DisabledChecks, params.OptionalChecks)); err != nil {
+		return nil, err
+	}
+	if err := validatePriority(params.MinPriority); err != nil {
+		return nil, err
+	}
+
+	enabled := a.enabledChecks
+	if len(params.EnabledChecks) > 0 {
+		enabled = params.EnabledChecks
+	}
+	disabled := slices.Concat(a.disabledChecks, params.DisabledChecks)
+	if params.ComplexityThreshold <= 0 {
+		params.ComplexityThreshold = a.complexityThreshold
+	}
+	namingRules := a.namingRules
+	if namingRules == nil {
+		namingRules = DefaultNamingRules
+	}
+	namingRules, err := mergeNamingRules(namingRules, params.NamingRules)
+	if err != nil {
+		return nil, err
+	}
+	params.NamingRules = namingRules
+
+	var selected []registeredCheck
+	for _, registered := range a.checks {
+		name := registered.check.Name()
+		run := !registered.optional
+		if len(enabled) > 0 {
+			run = slices.Contains(enabled, name)
+		}
+		if slices.Contains(params.OptionalChecks, name) {
+			run = true
+		}
+		if !run || slices.Contains(disabled, name) {
+			continue
+		}
+
+		if configurable, ok := registered.check.(configurableCheck); ok {
+			registered.check = configurable.withParams(params)
+		}
+		selected = append(selected, registered)
+	}
+
+	return selected, nil
+}
+
+// validateCheckNames returns an error naming the first name not in known
+func validateCheckNames(known, names []string) error {
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			return fmt.Errorf("unknown check %q (available: %s)", name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package typescript
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// functionSpan is a function, method or arrow function with a block body, located by
+// byte offsets into the code it was found in
+type functionSpan struct {
+	name string
+	// start is where the declaration begins, for reporting its position
+	start int
+	// params is the text between the parameter list's parentheses
+	params string
+	// returnType is the return type annotation, if any
+	returnType string
+	// bodyStart and bodyEnd are the offsets of the body's braces
+	bodyStart, bodyEnd int
+}
+
+var (
+	// functionStartRegexes match the text up to the '(' of a parameter list, capturing the
+	// function's name: function declarations and expressions, arrow functions assigned to
+	// a variable or property, and class or object methods
+	functionStartRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`\bfunction\s*\*?\s*([\w$]*)\s*(?:<[^>]*>)?\s*\(`),
+		regexp.MustCompile(`\b(?:const|let|var)\s+([\w$]+)\s*(?::[^=;]+)?=\s*(?:async\s*)?(?:<[^>]*>)?\s*\(`),
+		regexp.MustCompile(`(?:^|[{,]\s*)([\w$]+)\s*:\s*(?:async\s*)?(?:<[^>]*>)?\s*\(`),
+		regexp.MustCompile(`(?m)^[ \t]*(?:(?:public|private|protected|static|override|abstract|async|get|set)\s+)*\*?\s*([\w$]+)\s*(?:<[^>]*>)?\s*\(`),
+	}
+	// functionNameRegex captures the variable or property a function expression is assigned to
+	functionNameRegex = regexp.MustCompile(`([\w$]+)\s*(?::[^=;]+)?[=:]\s*(?:async\s*)?$`)
+)
+
+// controlKeywords look like method declarations at the start of a line but aren't
+var controlKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "return": true,
+	"function": true, "typeof": true, "await": true, "new": true, "super": true, "with": true,
+}
+
+// functionSpans finds the functions with a block body in code, which should already have
+// its strings and comments stripped, ordered by position
+func functionSpans(code string) []functionSpan {
+	seen := make(map[int]bool)
+	var spans []functionSpan
+
+	for _, startRegex := range functionStartRegexes {
+		for _, match := range startRegex.FindAllStringSubmatchIndex(code, -1) {
+			name := submatch(code, match, 1)
+			open := match[1] - 1
+			if controlKeywords[name] || seen[open] {
+				continue
+			}
+
+			bodyStart := functionBodyStart(code, open)
+			if bodyStart < 0 {
+				continue
+			}
+			bodyEnd := matchingClose(code, bodyStart)
+			if bodyEnd < 0 {
+				continue
+			}
+
+			if name == "" {
+				if assigned := functionNameRegex.FindStringSubmatch(code[max(0, match[0]-200):match[0]]); assigned != nil {
+					name = assigned[1]
+				} else {
+					name = "anonymous function"
+				}
+			}
+
+			closeParen := matchingClose(code, open)
+			seen[open] = true
+			spans = append(spans, functionSpan{
+				name:       name,
+				start:      match[2],
+				params:     code[open+1 : closeParen],
+				returnType: returnTypeBetween(code[closeParen+1 : bodyStart]),
+				bodyStart:  bodyStart,
+				bodyEnd:    bodyEnd,
+			})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans
+}
+
+// functionBodyStart returns the offset of the '{' opening the body of the function whose
+// parameter list opens at open, skipping a return type annotation and an arrow, or -1 when
+// the parameter list isn't followed by a block body
+func functionBodyStart(code string, open int) int {
+	closeParen := matchingClose(code, open)
+	if closeParen < 0 {
+		return -1
+	}
+
+	offset := closeParen + 1
+	rest := strings.TrimLeft(code[offset:], " \t\r\n")
+	if strings.HasPrefix(rest, ":") {
+		// Return type: the body starts at the next '{' or '=>'. Object literal return
+		// types aren't told apart from the body.
+		end := strings.IndexAny(rest, "{;=")
+		if end < 0 {
+			return -1
+		}
+		offset += len(code[offset:]) - len(rest) + end
+		rest = code[offset:]
+	}
+	if arrow, ok := strings.CutPrefix(rest, "=>"); ok {
+		rest = strings.TrimLeft(arrow, " \t\r\n")
+	}
+	if !strings.HasPrefix(rest, "{") {
+		return -1
+	}
+	return len(code) - len(rest)
+}
+
+// returnTypeBetween returns the return type annotation in the text between a parameter
+// list and the function body or arrow, e.g. ": Promise<void> =>"
+func returnTypeBetween(between string) string {
+	between = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(between), "=>"))
+	if annotation, ok := strings.CutPrefix(between, ":"); ok {
+		return strings.TrimSpace(annotation)
+	}
+	return ""
+}
+
+// body returns the function's body with the bodies of the given nested functions blanked
+// out, so a function is only measured by its own code
+func (f functionSpan) body(code string, spans []functionSpan) string {
+	body := []byte(code[f.bodyStart : f.bodyEnd+1])
+	for _, nested := range spans {
+		if nested.bodyStart <= f.bodyStart || nested.bodyEnd >= f.bodyEnd {
+			continue
+		}
+		for i := nested.bodyStart + 1; i < nested.bodyEnd; i++ {
+			if body[i-f.bodyStart] != '\n' {
+				body[i-f.bodyStart] = ' '
+			}
+		}
+	}
+	return string(body)
+}
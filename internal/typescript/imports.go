@@ -0,0 +1,217 @@
+package typescript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+var (
+	// staticImportRegex matches import and re-export statements, including multi-line ones
+	staticImportRegex = regexp.MustCompile(`(?m)^\s*(?:import|export)\s+(type\s+)?(?:[^'";]*?\s+from\s+)?['"]([^'"\n]+)['"]`)
+	// dynamicImportRegex matches import("x") and require("x") calls
+	dynamicImportRegex = regexp.MustCompile(`(?:\bimport|\brequire)\s*\(\s*['"]([^'"\n]+)['"]\s*\)`)
+	// commentRegex matches line and block comments, which may contain example imports
+	commentRegex = regexp.MustCompile(`/\*(?s:.*?)\*/|(?m)^\s*//[^\n]*`)
+)
+
+// moduleExtensions are tried in order when resolving an extensionless relative import
+var moduleExtensions = []string{".ts", ".tsx", ".mts", ".cts", ".d.ts"}
+
+// BuildImportGraph parses the imports of every TypeScript file under params.ProjectRoot
+// and returns the module dependency graph with any circular dependencies
+func (a *Analyzer) BuildImportGraph(params types.ImportGraphParams) (*types.ImportGraph, error) {
+	files, truncated, err := a.collectProjectFiles(params.ProjectRoot, params.Include, params.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &types.ImportGraph{
+		Nodes:     []string{},
+		Edges:     []types.ImportEdge{},
+		Truncated: truncated,
+	}
+
+	known := make(map[string]bool, len(files))
+	for _, file := range files {
+		known[file] = true
+	}
+
+	external := make(map[string]bool)
+	runtimeEdges := make(map[string][]string)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		from := relativeModule(params.ProjectRoot, file)
+		graph.Nodes = append(graph.Nodes, from)
+
+		seen := make(map[string]bool)
+		for _, spec := range parseImports(string(content)) {
+			if !strings.HasPrefix(spec.path, ".") {
+				external[spec.path] = true
+				continue
+			}
+
+			target := resolveImport(file, spec.path, known)
+			if target == "" {
+				continue
+			}
+			to := relativeModule(params.ProjectRoot, target)
+
+			key := to
+			if spec.typeOnly {
+				key += " type"
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			graph.Edges = append(graph.Edges, types.ImportEdge{From: from, To: to, TypeOnly: spec.typeOnly})
+			// Type-only imports are erased at compile time, so they can't cause runtime cycles
+			if !spec.typeOnly {
+				runtimeEdges[from] = append(runtimeEdges[from], to)
+			}
+		}
+	}
+
+	sort.Strings(graph.Nodes)
+	for name := range external {
+		graph.External = append(graph.External, name)
+	}
+	sort.Strings(graph.External)
+	graph.Cycles = findCycles(graph.Nodes, runtimeEdges)
+
+	return graph, nil
+}
+
+// importSpec is a module specifier found in a source file
+type importSpec struct {
+	path     string
+	typeOnly bool
+}
+
+// parseImports returns the module specifiers imported or re-exported by code
+func parseImports(code string) []importSpec {
+	code = commentRegex.ReplaceAllString(code, "")
+
+	var specs []importSpec
+	for _, matches := range staticImportRegex.FindAllStringSubmatch(code, -1) {
+		specs = append(specs, importSpec{path: matches[2], typeOnly: matches[1] != ""})
+	}
+	for _, matches := range dynamicImportRegex.FindAllStringSubmatch(code, -1) {
+		specs = append(specs, importSpec{path: matches[1]})
+	}
+	return specs
+}
+
+// resolveImport resolves a relative specifier imported by file to one of the known
+// project files, or returns an empty string when it points outside the analyzed set
+func resolveImport(file, specifier string, known map[string]bool) string {
+	base := filepath.Join(filepath.Dir(file), filepath.FromSlash(specifier))
+
+	candidates := []string{base}
+	// ESM-style imports name the emitted .js file rather than the .ts source
+	for jsExt, tsExts := range map[string][]string{".js": {".ts", ".tsx"}, ".mjs": {".mts"}, ".cjs": {".cts"}, ".jsx": {".tsx"}} {
+		if trimmed, ok := strings.CutSuffix(base, jsExt); ok {
+			for _, tsExt := range tsExts {
+				candidates = append(candidates, trimmed+tsExt)
+			}
+		}
+	}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, base+ext)
+	}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, filepath.Join(base, "index"+ext))
+	}
+
+	for _, candidate := range candidates {
+		if known[candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// relativeModule returns file relative to root with forward slashes
+func relativeModule(root, file string) string {
+	relPath, err := filepath.Rel(root, file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// findCycles returns the strongly connected components of the graph that contain a
+// cycle, using Tarjan's algorithm. Each cycle and the list of cycles are sorted.
+func findCycles(nodes []string, edges map[string][]string) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	cycles := [][]string{}
+	next := 0
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = next
+		lowLink[node] = next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfLoop := false
+		for _, target := range edges[node] {
+			if target == node {
+				selfLoop = true
+			}
+			if _, visited := index[target]; !visited {
+				visit(target)
+				lowLink[node] = min(lowLink[node], lowLink[target])
+			} else if onStack[target] {
+				lowLink[node] = min(lowLink[node], index[target])
+			}
+		}
+
+		if lowLink[node] != index[node] {
+			return
+		}
+
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := index[node]; !visited {
+			visit(node)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
@@ -0,0 +1,53 @@
+package typescript
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// wordRegex matches identifier-like words and individual punctuation marks,
+// roughly mirroring how tokenizers split source code
+var wordRegex = regexp.MustCompile(`\w+|[^\w\s]`)
+
+// EstimateSize estimates the size of a file or code snippet for LLM context budgeting
+func (a *Analyzer) EstimateSize(params types.EstimateSizeParams) (*types.SizeEstimate, error) {
+	content := params.CodeSnippet
+	if params.FilePath != "" {
+		data, err := os.ReadFile(params.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		content = string(data)
+	}
+
+	return EstimateContentSize(content), nil
+}
+
+// EstimateContentSize computes byte, line and approximate token counts for content.
+// The token count blends the common chars/4 rule of thumb with a word-based count,
+// which tracks real tokenizers more closely for punctuation-heavy code.
+func EstimateContentSize(content string) *types.SizeEstimate {
+	estimate := &types.SizeEstimate{
+		Bytes: len(content),
+	}
+
+	if content == "" {
+		return estimate
+	}
+
+	estimate.Lines = strings.Count(content, "\n") + 1
+	if strings.HasSuffix(content, "\n") {
+		estimate.Lines--
+	}
+
+	charEstimate := float64(len(content)) / 4
+	wordEstimate := float64(len(wordRegex.FindAllStringIndex(content, -1))) * 1.3
+	estimate.ApproxTokens = int(math.Ceil((charEstimate + wordEstimate) / 2))
+
+	return estimate
+}
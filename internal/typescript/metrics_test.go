@@ -0,0 +1,89 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+const sizeSnippet = `import { readFile } from "fs/promises";
+
+export async function loadUser(id: string): Promise<User> {
+  const data = await readFile(` + "`users/${id}.json`" + `, "utf8");
+  return JSON.parse(data) as User;
+}
+`
+
+func TestEstimateContentSizeKnownSnippet(t *testing.T) {
+	estimate := EstimateContentSize(sizeSnippet)
+
+	if estimate.Bytes != len(sizeSnippet) {
+		t.Errorf("Bytes = %d, want %d", estimate.Bytes, len(sizeSnippet))
+	}
+	if estimate.Lines != 6 {
+		t.Errorf("Lines = %d, want 6", estimate.Lines)
+	}
+	// Real tokenizers land between roughly 2 and 8 bytes per token for source code
+	if low, high := len(sizeSnippet)/8, len(sizeSnippet)/2; estimate.ApproxTokens < low || estimate.ApproxTokens > high {
+		t.Errorf("ApproxTokens = %d, want between %d and %d", estimate.ApproxTokens, low, high)
+	}
+}
+
+func TestEstimateContentSizeEmpty(t *testing.T) {
+	estimate := EstimateContentSize("")
+	if *estimate != (types.SizeEstimate{}) {
+		t.Errorf("EstimateContentSize(\"\") = %+v, want zero", *estimate)
+	}
+}
+
+func TestEstimateContentSizeLines(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{"const a = 1;", 1},
+		{"const a = 1;\n", 1},
+		{"const a = 1;\nconst b = 2;", 2},
+		{"\n\n", 2},
+	}
+	for _, tt := range tests {
+		if got := EstimateContentSize(tt.content).Lines; got != tt.want {
+			t.Errorf("EstimateContentSize(%q).Lines = %d, want %d", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateContentSizeMonotonic(t *testing.T) {
+	previous := EstimateContentSize("")
+	content := ""
+	for i := 0; i < 20; i++ {
+		content += sizeSnippet
+		estimate := EstimateContentSize(content)
+		if estimate.Bytes <= previous.Bytes || estimate.Lines <= previous.Lines || estimate.ApproxTokens <= previous.ApproxTokens {
+			t.Fatalf("estimate did not grow after %d copies: %+v, previous %+v", i+1, *estimate, *previous)
+		}
+		previous = estimate
+	}
+}
+
+func TestEstimateSizeReadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.ts")
+	if err := os.WriteFile(path, []byte(sizeSnippet), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analyzer := NewAnalyzer()
+	estimate, err := analyzer.EstimateSize(types.EstimateSizeParams{FilePath: path, CodeSnippet: "ignored"})
+	if err != nil {
+		t.Fatalf("EstimateSize: %v", err)
+	}
+	if want := EstimateContentSize(sizeSnippet); *estimate != *want {
+		t.Errorf("EstimateSize = %+v, want %+v", *estimate, *want)
+	}
+
+	if _, err := analyzer.EstimateSize(types.EstimateSizeParams{FilePath: filepath.Join(t.TempDir(), "missing.ts")}); err == nil {
+		t.Error("EstimateSize of a missing file returned no error")
+	}
+}
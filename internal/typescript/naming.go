@@ -0,0 +1,141 @@
+package typescript
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// namingKind is a kind of declaration that naming rules apply to
+type namingKind struct {
+	// label names the kind in improvement descriptions
+	label string
+	// regex matches a declaration of the kind, capturing its name
+	regex *regexp.Regexp
+}
+
+// namingKinds are the declaration kinds NamingRules accepts, by key
+var namingKinds = map[string]namingKind{
+	"interface":      {"Interface", regexp.MustCompile(`\binterface\s+([\p{L}\p{N}_$]+)`)},
+	"type":           {"Type", regexp.MustCompile(`\btype\s+([\p{L}\p{N}_$]+)\s*(?:<[^>]*>)?\s*=`)},
+	"enum":           {"Enum", regexp.MustCompile(`\benum\s+([\p{L}\p{N}_$]+)`)},
+	"class":          {"Class", regexp.MustCompile(`\bclass\s+([\p{L}\p{N}_$]+)`)},
+	"function":       {"Function", regexp.MustCompile(`\bfunction\s*\*?\s*([\p{L}\p{N}_$]+)`)},
+	"const":          {"Variable", regexp.MustCompile(`\bconst\s+([\p{L}\p{N}_$]+)`)},
+	"variable":       {"Variable", regexp.MustCompile(`\b(?:let|var)\s+([\p{L}\p{N}_$]+)`)},
+	"private_member": {"Private member", regexp.MustCompile(`(?m)\bprivate\s+(?:(?:static|readonly)\s+)*([\p{L}\p{N}_$]+)|^[ \t]*(?:static\s+)?(#[\p{L}\p{N}_$]+)`)},
+}
+
+// namingConvention is a well-known naming rule that names can be rewritten to follow
+type namingConvention struct {
+	name      string
+	fix       func(string) string
+	reasoning string
+}
+
+// namingConventions are the conventions the default rules use, by pattern
+var namingConventions = map[string]namingConvention{
+	`^[^\p{Ll}]`: {"PascalCase", toPascalCase, "TypeScript convention uses PascalCase for types and interfaces"},
+	`^[^\p{Lu}]`: {"camelCase", toCamelCase, "TypeScript convention uses camelCase for variables and functions"},
+}
+
+// DefaultNamingRules are the naming rules used unless the configuration or a request
+// overrides them: interfaces start with an uppercase letter and variables don't
+var DefaultNamingRules = map[string]string{
+	"interface": `^[^\p{Ll}]`,
+	"const":     `^[^\p{Lu}]`,
+	"variable":  `^[^\p{Lu}]`,
+}
+
+// SetNamingRules sets the naming rules "naming_conventions" enforces, keyed by
+// declaration kind, on top of DefaultNamingRules. An empty pattern turns a default rule
+// off. It returns an error for unknown kinds and invalid patterns.
+func (a *Analyzer) SetNamingRules(rules map[string]string) error {
+	merged, err := mergeNamingRules(DefaultNamingRules, rules)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.namingRules = merged
+	return nil
+}
+
+// mergeNamingRules returns base with the rules in overrides applied, dropping rules
+// overridden with an empty pattern
+func mergeNamingRules(base, overrides map[string]string) (map[string]string, error) {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = make(map[string]string, len(overrides))
+	}
+	for kind, pattern := range overrides {
+		if _, ok := namingKinds[kind]; !ok {
+			return nil, fmt.Errorf("unknown naming rule kind %q (available: %s)", kind, strings.Join(slices.Sorted(maps.Keys(namingKinds)), ", "))
+		}
+		if pattern == "" {
+			delete(merged, kind)
+			continue
+		}
+		if compilePattern(pattern) == nil {
+			return nil, fmt.Errorf("invalid naming rule pattern for %s: %q", kind, pattern)
+		}
+		merged[kind] = pattern
+	}
+	return merged, nil
+}
+
+func (namingConventionsCheck) withParams(params types.SuggestImprovementsParams) AnalyzerCheck {
+	return namingConventionsCheck{rules: params.NamingRules}
+}
+
+// Analyze flags declared names that don't match the naming rule for their kind
+func (c namingConventionsCheck) Analyze(code string) []types.Improvement {
+	var improvements []types.Improvement
+
+	rules := c.rules
+	if rules == nil {
+		rules = DefaultNamingRules
+	}
+	stripped := stripStringsAndComments(code)
+	for _, kind := range slices.Sorted(maps.Keys(rules)) {
+		pattern := compilePattern(rules[kind])
+		if pattern == nil {
+			continue
+		}
+		declaration := namingKinds[kind]
+		for _, match := range declaration.regex.FindAllStringSubmatchIndex(stripped, -1) {
+			name := submatch(stripped, match, 1)
+			start := match[2]
+			if name == "" {
+				name, start = submatch(stripped, match, 2), match[4]
+			}
+			if kind == "const" && name == "enum" || pattern.MatchString(name) {
+				continue
+			}
+
+			line, column := lineColumnAt(code, start)
+			improvement := types.Improvement{
+				Type:        "naming_convention",
+				Description: fmt.Sprintf("%s '%s' should match the naming rule %s", declaration.label, name, rules[kind]),
+				Before:      name,
+				Reasoning:   fmt.Sprintf("The configured naming rule for %s names is %s", strings.ReplaceAll(kind, "_", " "), rules[kind]),
+				Priority:    "low",
+				Line:        line,
+				Column:      column,
+			}
+			if convention, ok := namingConventions[rules[kind]]; ok {
+				improvement.Description = fmt.Sprintf("%s '%s' should use %s", declaration.label, name, convention.name)
+				improvement.After = convention.fix(name)
+				improvement.Reasoning = convention.reasoning
+			}
+			improvements = append(improvements, improvement)
+		}
+	}
+
+	return improvements
+}
@@ -0,0 +1,260 @@
+package typescript
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mcp-typescript-assistant/internal/paths"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// MaxProjectFiles caps how many files a single project-wide analysis will read
+const MaxProjectFiles = 500
+
+// suggestProjectImprovements analyzes every TypeScript file under params.ProjectRoot
+func (a *Analyzer) suggestProjectImprovements(params types.SuggestImprovementsParams) (*types.ImprovementResult, error) {
+	selected, err := a.selectChecks(params)
+	if err != nil {
+		return nil, err
+	}
+
+	files, truncated, err := a.collectProjectFiles(params.ProjectRoot, params.Include, params.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	if params.ChangedOnly {
+		files = onlyChangedFiles(files, params.ChangedFiles)
+	}
+
+	var fileResults []types.FileImprovements
+	var allImprovements []types.Improvement
+	maxIssues := maxIssuesOrDefault(params.MaxIssues)
+	returned := 0
+	suppressed := 0
+	ignoreRules := append(ReadIgnoreFile(params.ProjectRoot), params.IgnoreRules...)
+	ran := make(map[string]bool)
+
+	for i, file := range files {
+		if params.Progress != nil {
+			params.Progress(i, len(files))
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		fileParams := params
+		fileParams.Language = paths.LanguageFor(file)
+
+		improvements, fileRan := a.analyzeCode(string(content), fileParams, selected)
+		for _, name := range fileRan {
+			ran[name] = true
+		}
+		improvements, fileSuppressed := suppressImprovements(improvements, ignoreRules)
+		suppressed += fileSuppressed
+		if params.SortByPriority == nil || *params.SortByPriority {
+			sortByPriority(improvements)
+		}
+		if len(improvements) == 0 {
+			continue
+		}
+
+		relPath, err := filepath.Rel(params.ProjectRoot, file)
+		if err != nil {
+			relPath = file
+		}
+
+		allImprovements = append(allImprovements, improvements...)
+
+		// Keep counting once the limit is reached so the summary covers every file
+		if returned >= maxIssues {
+			continue
+		}
+		if remaining := maxIssues - returned; len(improvements) > remaining {
+			improvements = improvements[:remaining]
+		}
+		returned += len(improvements)
+
+		fileResults = append(fileResults, types.FileImprovements{
+			FilePath:     relPath,
+			Improvements: improvements,
+		})
+	}
+
+	if params.Progress != nil {
+		params.Progress(len(files), len(files))
+	}
+
+	summary := fmt.Sprintf("Analyzed %d files. %s", len(files), a.generateImprovementSummary(allImprovements))
+	if suppressed > 0 {
+		summary += fmt.Sprintf(" (%d suppressed)", suppressed)
+	}
+
+	result := &types.ImprovementResult{
+		Improvements: []types.Improvement{},
+		Summary:      summary,
+		AppliedRules: a.appliedRules(ranInOrder(selected, ran)),
+		Files:        fileResults,
+		Truncated:    truncated || len(allImprovements) > maxIssues,
+		TotalIssues:  len(allImprovements),
+		Score:        a.qualityScore(allImprovements),
+		Suppressed:   suppressed,
+	}
+
+	var notes []string
+	if truncated {
+		notes = append(notes, fmt.Sprintf("Project contains more than %d TypeScript files; only the first %d were analyzed", MaxProjectFiles, MaxProjectFiles))
+	}
+	if len(allImprovements) > maxIssues {
+		notes = append(notes, fmt.Sprintf("Showing the first %d of %d improvements", maxIssues, len(allImprovements)))
+	}
+	result.Note = strings.Join(notes, ". ")
+
+	if params.ChangedOnly {
+		result.ChangedFiles = make([]string, 0, len(files))
+		for _, file := range files {
+			relPath, err := filepath.Rel(params.ProjectRoot, file)
+			if err != nil {
+				relPath = file
+			}
+			result.ChangedFiles = append(result.ChangedFiles, filepath.ToSlash(relPath))
+		}
+	}
+
+	return result, nil
+}
+
+// onlyChangedFiles keeps the project files that are in changed, a list of absolute paths
+func onlyChangedFiles(files, changed []string) []string {
+	isChanged := make(map[string]bool, len(changed))
+	for _, file := range changed {
+		isChanged[file] = true
+	}
+
+	var kept []string
+	for _, file := range files {
+		if absFile, err := filepath.Abs(file); err == nil && isChanged[absFile] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// ranInOrder returns the names of the selected checks that ran on at least one file
+func ranInOrder(selected []registeredCheck, ran map[string]bool) []string {
+	var names []string
+	for _, registered := range selected {
+		if name := registered.check.Name(); ran[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// MatchProjectFiles returns the TypeScript files under root matching any of the include
+// globs, skipping node_modules and .gitignore'd paths. The bool reports whether the
+// list was cut off at MaxProjectFiles.
+func (a *Analyzer) MatchProjectFiles(root string, include []string) ([]string, bool, error) {
+	return a.collectProjectFiles(root, include, nil)
+}
+
+// collectProjectFiles walks root and returns the TypeScript files to analyze,
+// honoring include/exclude globs and the root .gitignore
+func (a *Analyzer) collectProjectFiles(root string, include, exclude []string) ([]string, bool, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to access project root: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, false, fmt.Errorf("project root %s is not a directory", root)
+	}
+
+	ignorePatterns := a.readGitignore(filepath.Join(root, ".gitignore"))
+
+	var files []string
+	truncated := false
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil || relPath == "." {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if entry.IsDir() {
+			if entry.Name() == "node_modules" || entry.Name() == ".git" || matchesIgnore(ignorePatterns, relPath, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !paths.IsTypeScriptFile(path) || matchesIgnore(ignorePatterns, relPath, false) {
+			return nil
+		}
+		if len(include) > 0 && !paths.MatchAny(include, relPath) {
+			return nil
+		}
+		if paths.MatchAny(exclude, relPath) {
+			return nil
+		}
+
+		if len(files) >= MaxProjectFiles {
+			truncated = true
+			return filepath.SkipAll
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to walk project: %w", err)
+	}
+
+	return files, truncated, nil
+}
+
+// readGitignore reads the patterns from a .gitignore file, ignoring comments and negations
+func (a *Analyzer) readGitignore(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns
+}
+
+// matchesIgnore reports whether relPath matches any .gitignore pattern
+func matchesIgnore(patterns []string, relPath string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if !isDir {
+				continue
+			}
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		pattern = strings.TrimPrefix(pattern, "/")
+		if paths.MatchGlob(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package typescript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"mcp-typescript-assistant/internal/paths"
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// RenameService computes the edits that rename a symbol, such as the TypeScript
+// language service
+type RenameService interface {
+	RenameLocations(params types.RenameSymbolParams) (*types.RenameResult, error)
+}
+
+// identifierRegex matches a JavaScript identifier; keywords are rejected by the language service
+var identifierRegex = regexp.MustCompile(`^[\p{L}\p{Nl}_$][\p{L}\p{Nl}\p{Mn}\p{Mc}\p{Nd}\p{Pc}_$]*$`)
+
+// RenameSymbol computes the edits that rename a symbol across its project with service
+// and writes them when params.Apply is set. Edit paths are relative to the file's directory.
+func RenameSymbol(service RenameService, params types.RenameSymbolParams) (*types.RenameResult, error) {
+	if params.FilePath == "" {
+		return nil, fmt.Errorf("file_path is required")
+	}
+	if !identifierRegex.MatchString(params.NewName) {
+		return nil, fmt.Errorf("new_name %q is not a valid identifier", params.NewName)
+	}
+
+	result, err := service.RenameLocations(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Apply {
+		if err := checkEditRoots(result.Edits, params.AllowedRoots); err != nil {
+			return nil, err
+		}
+		if err := applyEdits(result.Edits); err != nil {
+			return nil, err
+		}
+		result.Applied = true
+	}
+
+	dir := filepath.Dir(params.FilePath)
+	files := make(map[string]bool)
+	for i := range result.Edits {
+		result.Edits[i].File = relativePath(dir, result.Edits[i].File)
+		files[result.Edits[i].File] = true
+	}
+	result.Files = make([]string, 0, len(files))
+	for file := range files {
+		result.Files = append(result.Files, file)
+	}
+	sort.Strings(result.Files)
+	if result.Edits == nil {
+		result.Edits = []types.TextEdit{}
+	}
+
+	verb := "Would rename"
+	if result.Applied {
+		verb = "Renamed"
+	}
+	result.Summary = fmt.Sprintf("%s %s to %s: %d edit(s) in %d file(s)",
+		verb, result.SymbolName, result.NewName, len(result.Edits), len(result.Files))
+
+	return result, nil
+}
+
+// checkEditRoots returns an error if any edit targets a file outside roots, such as a
+// dependency in node_modules, so that a rename is applied everywhere or nowhere
+func checkEditRoots(edits []types.TextEdit, roots []string) error {
+	if len(roots) == 0 {
+		return nil
+	}
+
+	for _, edit := range edits {
+		file, err := paths.Resolve(edit.File)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", edit.File, err)
+		}
+		if !slices.ContainsFunc(roots, func(root string) bool { return paths.Within(root, file) }) {
+			return fmt.Errorf("refusing to rename: %s is outside the allowed roots", edit.File)
+		}
+	}
+	return nil
+}
+
+// applyEdits writes edits to their files. Every file is read and edited in memory
+// before any is written, so an edit that no longer fits leaves all files untouched.
+func applyEdits(edits []types.TextEdit) error {
+	byFile := make(map[string][]types.TextEdit)
+	for _, edit := range edits {
+		byFile[edit.File] = append(byFile[edit.File], edit)
+	}
+
+	updated := make(map[string][]byte, len(byFile))
+	modes := make(map[string]os.FileMode, len(byFile))
+	for file, fileEdits := range byFile {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to access file: %w", err)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+
+		edited, err := applyFileEdits(string(content), fileEdits)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		updated[file] = []byte(edited)
+		modes[file] = info.Mode().Perm()
+	}
+
+	for file, content := range updated {
+		if err := os.WriteFile(file, content, modes[file]); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyFileEdits applies edits to code from the end backwards so earlier offsets stay valid
+func applyFileEdits(code string, edits []types.TextEdit) (string, error) {
+	type span struct {
+		start, end int
+		text       string
+	}
+
+	lineStarts := []int{0}
+	for i := 0; i < len(code); i++ {
+		if code[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	spans := make([]span, 0, len(edits))
+	for _, edit := range edits {
+		start, err := byteOffset(code, lineStarts, edit.Line, edit.Column)
+		if err != nil {
+			return "", err
+		}
+		end, err := byteOffset(code, lineStarts, edit.EndLine, edit.EndColumn)
+		if err != nil {
+			return "", err
+		}
+		if end < start {
+			return "", fmt.Errorf("edit at line %d ends before it starts", edit.Line)
+		}
+		spans = append(spans, span{start: start, end: end, text: edit.NewText})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
+	for i := 1; i < len(spans); i++ {
+		if spans[i].end > spans[i-1].start {
+			return "", fmt.Errorf("overlapping edits at offset %d", spans[i].start)
+		}
+	}
+
+	for _, s := range spans {
+		code = code[:s.start] + s.text + code[s.end:]
+	}
+	return code, nil
+}
+
+// byteOffset converts a 1-based line and UTF-16 column to a byte offset in code
+func byteOffset(code string, lineStarts []int, line, column int) (int, error) {
+	if line < 1 || line > len(lineStarts) || column < 1 {
+		return 0, fmt.Errorf("position %d:%d is outside the file; re-run the rename", line, column)
+	}
+
+	offset := lineStarts[line-1]
+	for units := column - 1; units > 0; {
+		if offset >= len(code) || code[offset] == '\n' {
+			return 0, fmt.Errorf("position %d:%d is outside the file; re-run the rename", line, column)
+		}
+		r, size := utf8.DecodeRuneInString(code[offset:])
+		units -= utf16.RuneLen(r)
+		offset += size
+	}
+	return offset, nil
+}
+
+// relativePath returns path relative to dir with forward slashes, or path unchanged
+// if it can't be made relative
+func relativePath(dir, path string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(absDir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
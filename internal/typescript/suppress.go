@@ -0,0 +1,120 @@
+package typescript
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"mcp-typescript-assistant/pkg/types"
+)
+
+// IgnoreFileName is the per-project file listing improvement types or IDs to suppress
+const IgnoreFileName = ".tsassistant-ignore"
+
+// ReadIgnoreFile returns the rules listed in root's ignore file, one per line, skipping
+// blank lines and # comments. A missing file yields no rules.
+func ReadIgnoreFile(root string) []string {
+	if root == "" {
+		return nil
+	}
+
+	file, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, line)
+	}
+
+	return rules
+}
+
+// suppressImprovements drops improvements whose type, ID or matched guideline rule is
+// listed in ignoreRules and returns the remaining improvements with the number dropped
+func suppressImprovements(improvements []types.Improvement, ignoreRules []string) ([]types.Improvement, int) {
+	if len(ignoreRules) == 0 {
+		return improvements, 0
+	}
+
+	ignored := make(map[string]bool, len(ignoreRules))
+	for _, rule := range ignoreRules {
+		ignored[strings.ToLower(strings.TrimSpace(rule))] = true
+	}
+
+	kept := improvements[:0]
+	for _, improvement := range improvements {
+		if ignored[strings.ToLower(improvement.Type)] || ignored[strings.ToLower(improvement.ID)] ||
+			(improvement.MatchedRule != "" && ignored[strings.ToLower(improvement.MatchedRule)]) {
+			continue
+		}
+		kept = append(kept, improvement)
+	}
+
+	return kept, len(improvements) - len(kept)
+}
+
+// disableCommentRegex matches inline suppression comments such as
+// `// ts-assistant-disable-next-line type_annotation, naming`
+var disableCommentRegex = regexp.MustCompile(`//\s*ts-assistant-(disable-next-line|disable-line)\b([^\n]*)`)
+
+// parseInlineSuppressions maps line numbers to the improvement types disabled on them by
+// ts-assistant-disable-line and ts-assistant-disable-next-line comments. An empty rule
+// list disables every type on that line.
+func parseInlineSuppressions(code string) map[int][]string {
+	suppressions := make(map[int][]string)
+
+	for _, match := range disableCommentRegex.FindAllStringSubmatchIndex(code, -1) {
+		line, _ := lineColumnAt(code, match[0])
+		if submatch(code, match, 1) == "disable-next-line" {
+			line++
+		}
+
+		// Anything after "--" is a free-form explanation, as in ESLint
+		rules, _, _ := strings.Cut(submatch(code, match, 2), "--")
+		suppressions[line] = append(suppressions[line], strings.FieldsFunc(rules, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+		if len(suppressions[line]) == 0 {
+			suppressions[line] = []string{"*"}
+		}
+	}
+
+	return suppressions
+}
+
+// applyInlineSuppressions drops improvements disabled by inline comments in code
+func applyInlineSuppressions(code string, improvements []types.Improvement) []types.Improvement {
+	if !strings.Contains(code, "ts-assistant-disable") {
+		return improvements
+	}
+	suppressions := parseInlineSuppressions(code)
+
+	kept := improvements[:0]
+	for _, improvement := range improvements {
+		if isSuppressedOnLine(suppressions[improvement.Line], improvement) {
+			continue
+		}
+		kept = append(kept, improvement)
+	}
+	return kept
+}
+
+// isSuppressedOnLine reports whether rules disable improvement
+func isSuppressedOnLine(rules []string, improvement types.Improvement) bool {
+	for _, rule := range rules {
+		if rule == "*" || strings.EqualFold(rule, improvement.Type) {
+			return true
+		}
+	}
+	return false
+}
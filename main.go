@@ -2,22 +2,45 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"mcp-typescript-assistant/internal/server"
+	"mcp-typescript-assistant/internal/tools"
 )
 
 // main entry point for the TypeScript MCP server
 func main() {
-	// Create context
-	ctx := context.Background()
-
 	// Create and run the server
-	mcpServer := server.NewTypeScriptMCPServer()
+	mcpServer, err := server.NewTypeScriptMCPServer()
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Log to stderr at the configured level
+	slog.SetDefault(mcpServer.Config().NewLogger())
+
+	// Remove scratch files left behind by servers that didn't shut down cleanly, as
+	// cmd/server does
+	if removed, err := tools.CleanStaleScratch(); err != nil {
+		slog.Warn("Failed to clean stale scratch files", "error", err)
+	} else if removed > 0 {
+		slog.Info("Removed stale scratch directories", "count", removed)
+	}
 	
-	if err := mcpServer.Run(ctx); err != nil {
-		log.Printf("Server error: %v", err)
+	// Cancel on shutdown signals so the scratch files are still removed
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	err = mcpServer.Run(ctx)
+	if removeErr := tools.RemoveScratch(); removeErr != nil {
+		slog.Warn("Failed to remove scratch files", "error", removeErr)
+	}
+	if err != nil {
+		slog.Error("Server error", "error", err)
 		os.Exit(1)
 	}
 }
\ No newline at end of file
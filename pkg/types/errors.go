@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ToolUnavailableError reports that a required external tool (tsc, eslint) could not be run
+type ToolUnavailableError struct {
+	Tool    string
+	Install string
+	Err     error
+}
+
+func (e *ToolUnavailableError) Error() string {
+	return fmt.Sprintf("%s not available: %v", e.Tool, e.Err)
+}
+
+func (e *ToolUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON renders the error as a machine-readable block clients can act on
+func (e *ToolUnavailableError) MarshalJSON() ([]byte, error) {
+	detail := ""
+	if e.Err != nil {
+		detail = e.Err.Error()
+	}
+	return json.Marshal(map[string]string{
+		"error":   "tool_unavailable",
+		"tool":    e.Tool,
+		"install": e.Install,
+		"detail":  detail,
+	})
+}
@@ -1,29 +1,286 @@
 package types
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
+
+// DefaultMaxIssues is the number of diagnostics, lint issues or improvements returned
+// when a request doesn't set MaxIssues
+const DefaultMaxIssues = 200
+
+// DefaultMaxCompletions is the number of completion entries returned when a request
+// doesn't set MaxEntries
+const DefaultMaxCompletions = 50
+
+// ProgressFunc reports that done of total files have been processed
+type ProgressFunc func(done, total int)
 
 // TypeCheckParams represents parameters for TypeScript type checking
 type TypeCheckParams struct {
 	FilePath    string `json:"file_path"`
 	ProjectRoot string `json:"project_root,omitempty"`
+	Build       bool   `json:"build,omitempty"`
+	Incremental bool   `json:"incremental,omitempty"`
+	CodeSnippet string `json:"code_snippet,omitempty"`
+	// Language is the snippet language ("ts", "tsx", "mts", "cts"), used to pick its file extension
+	Language string `json:"language,omitempty"`
+	// AbsolutePaths reports diagnostic file paths as absolute paths instead of
+	// relative to the project root (or the file's directory)
+	AbsolutePaths bool `json:"absolute_paths,omitempty"`
+	// MaxIssues caps the number of errors and warnings returned (DefaultMaxIssues when zero)
+	MaxIssues int `json:"max_issues,omitempty"`
+	// FileContent is the file's content, sent instead of reading FilePath from disk.
+	// FilePath is then only used as the logical name reported in results.
+	FileContent string `json:"file_content,omitempty"`
+	// ContentEncoding is "base64" or "utf8" (the default) for FileContent
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	// FilePaths checks several files in parallel instead of FilePath, one tsc process per file
+	FilePaths []string `json:"file_paths,omitempty"`
+	// Concurrency caps how many FilePaths are checked at once (runtime.NumCPU() when zero)
+	Concurrency int `json:"concurrency,omitempty"`
+	// Progress is called as FilePaths are checked; it is set by the server, not by clients
+	Progress ProgressFunc `json:"-"`
+	// Context kills the tsc processes once it is done, e.g. when the call is cancelled;
+	// it is set by the server, not by clients
+	Context context.Context `json:"-"`
+	// ChangedOnly type checks ProjectRoot but reports only diagnostics in the TypeScript
+	// files git reports as changed since BaseRef
+	ChangedOnly bool `json:"changed_only,omitempty"`
+	// BaseRef is the git ref ChangedOnly compares against (HEAD, i.e. uncommitted changes, when empty)
+	BaseRef string `json:"base_ref,omitempty"`
+	// Format is "json" (the default), or "markdown" or "html" for a human-readable report
+	Format string `json:"format,omitempty"`
+	// FailOn is the severity that makes Passed false: "error" (the default), "warning" or
+	// "any" for any diagnostic at all
+	FailOn string `json:"fail_on,omitempty"`
+	// NodeOptions is added to the server's node_options for this check, e.g.
+	// "--max-old-space-size=8192" for projects that run out of heap
+	NodeOptions string `json:"node_options,omitempty"`
+	// Env sets extra environment variables for the tsc process
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// TypeCheckDiffParams represents parameters for type checking against a baseline,
+// reporting only errors that the baseline doesn't have
+type TypeCheckDiffParams struct {
+	FilePath    string `json:"file_path,omitempty"`
+	ProjectRoot string `json:"project_root,omitempty"`
+	// Baseline lists previously reported errors, such as the errors of an earlier type-check
+	Baseline []TypeScriptError `json:"baseline,omitempty"`
+	// BaselineRef is a git ref (e.g. "main" or "HEAD~1") to type check for the baseline
+	// when Baseline is empty
+	BaselineRef string `json:"baseline_ref,omitempty"`
+	// MaxIssues caps the number of new errors returned (DefaultMaxIssues when zero)
+	MaxIssues int `json:"max_issues,omitempty"`
+	// Format is "json" (the default), or "markdown" or "html" for a human-readable report
+	Format string `json:"format,omitempty"`
 }
 
 // GetTypesParams represents parameters for getting type information
 type GetTypesParams struct {
 	FilePath   string `json:"file_path"`
 	SymbolName string `json:"symbol_name,omitempty"`
+	// FileContent is the file's content, sent instead of reading FilePath from disk.
+	// FilePath is then only used as the logical name reported in results.
+	FileContent string `json:"file_content,omitempty"`
+	// ContentEncoding is "base64" or "utf8" (the default) for FileContent
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	// Line and Column (1-based) query the type at a cursor position instead of a
+	// symbol's declaration. Column defaults to the start of the line.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// GetTypesBatchParams represents parameters for getting the types of several symbols
+// declared in one file
+type GetTypesBatchParams struct {
+	FilePath    string   `json:"file_path"`
+	SymbolNames []string `json:"symbol_names"`
+	// FileContent is the file's content, sent instead of reading FilePath from disk.
+	// FilePath is then only used as the logical name reported in results.
+	FileContent string `json:"file_content,omitempty"`
+	// ContentEncoding is "base64" or "utf8" (the default) for FileContent
+	ContentEncoding string `json:"content_encoding,omitempty"`
+}
+
+// ResolveModuleParams represents parameters for resolving an import specifier
+type ResolveModuleParams struct {
+	// Specifier is the import to resolve, such as "react" or "./utils"
+	Specifier string `json:"specifier"`
+	// FilePath is the file the import is resolved from
+	FilePath string `json:"file_path"`
+}
+
+// ResolveModuleResult represents where an import specifier resolves to
+type ResolveModuleResult struct {
+	Specifier    string `json:"specifier"`
+	ResolvedPath string `json:"resolved_path"`
+	// DeclarationPath is the file declaring the module's types: a .d.ts file or the
+	// TypeScript source itself, empty for JavaScript without declarations
+	DeclarationPath string `json:"declaration_path,omitempty"`
+	Extension       string `json:"extension"`
+	// IsExternal is true for modules resolved from node_modules
+	IsExternal     bool   `json:"is_external"`
+	PackageName    string `json:"package_name,omitempty"`
+	PackageVersion string `json:"package_version,omitempty"`
+	// TypesPackage is the DefinitelyTyped package, such as "@types/react", that the
+	// declarations come from
+	TypesPackage string `json:"types_package,omitempty"`
+}
+
+// SymbolPositionParams represents parameters for navigating from a symbol or a
+// line/column position, as used by find-references and go-to-definition
+type SymbolPositionParams struct {
+	FilePath   string `json:"file_path"`
+	SymbolName string `json:"symbol_name,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	// FileContent is the file's content, sent instead of reading FilePath from disk.
+	// FilePath is then only used as the logical name reported in results.
+	FileContent string `json:"file_content,omitempty"`
+	// ContentEncoding is "base64" or "utf8" (the default) for FileContent
+	ContentEncoding string `json:"content_encoding,omitempty"`
+}
+
+// RenameSymbolParams represents parameters for renaming a symbol across a project
+type RenameSymbolParams struct {
+	FilePath   string `json:"file_path"`
+	SymbolName string `json:"symbol_name,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	NewName    string `json:"new_name"`
+	// Apply writes the edits to disk instead of only returning them
+	Apply bool `json:"apply,omitempty"`
+	// AllowedRoots are the resolved directories Apply may write to (anywhere when empty)
+	AllowedRoots []string `json:"-"`
+}
+
+// CompleteParams represents parameters for code completion at a position
+type CompleteParams struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	// Prefix keeps only entries whose names start with it, ignoring case
+	Prefix string `json:"prefix,omitempty"`
+	// MaxEntries caps the number of entries returned (DefaultMaxCompletions when zero)
+	MaxEntries  int    `json:"max_entries,omitempty"`
+	CodeSnippet string `json:"code_snippet,omitempty"`
+	// Language is the snippet language ("ts", "tsx", "mts", "cts"), used to pick its file extension
+	Language string `json:"language,omitempty"`
+	// FileContent is the file's content, sent instead of reading FilePath from disk.
+	// FilePath is then only used as the logical name reported in results.
+	FileContent string `json:"file_content,omitempty"`
+	// ContentEncoding is "base64" or "utf8" (the default) for FileContent
+	ContentEncoding string `json:"content_encoding,omitempty"`
 }
 
 // LintCheckParams represents parameters for ESLint checking
 type LintCheckParams struct {
-	FilePath string   `json:"file_path"`
-	Rules    []string `json:"rules,omitempty"`
+	FilePath   string   `json:"file_path"`
+	Rules      []string `json:"rules,omitempty"`
+	ConfigPath string   `json:"config_path,omitempty"`
+	NoEslintrc bool     `json:"no_eslintrc,omitempty"`
+	IgnorePath string   `json:"ignore_path,omitempty"`
+	// AbsolutePaths reports issue file paths as absolute paths instead of relative to the file's directory
+	AbsolutePaths bool `json:"absolute_paths,omitempty"`
+	// MaxIssues caps the number of issues returned (DefaultMaxIssues when zero)
+	MaxIssues int `json:"max_issues,omitempty"`
+	// IncludeRuleDocs adds each rule's documentation URL and description to its issues
+	IncludeRuleDocs bool `json:"include_rule_docs,omitempty"`
+	// FileContent is the file's content, sent instead of reading FilePath from disk.
+	// FilePath is then only used as the logical name reported in results.
+	FileContent string `json:"file_content,omitempty"`
+	// ContentEncoding is "base64" or "utf8" (the default) for FileContent
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	// FilePaths lints several files in parallel instead of FilePath, one ESLint process per file
+	FilePaths []string `json:"file_paths,omitempty"`
+	// Concurrency caps how many FilePaths are linted at once (runtime.NumCPU() when zero)
+	Concurrency int `json:"concurrency,omitempty"`
+	// Progress is called as FilePaths are linted; it is set by the server, not by clients
+	Progress ProgressFunc `json:"-"`
+	// Context kills the eslint processes once it is done, e.g. when the call is
+	// cancelled; it is set by the server, not by clients
+	Context context.Context `json:"-"`
+	// ProjectRoot is the git checkout ChangedOnly looks for changes in
+	ProjectRoot string `json:"project_root,omitempty"`
+	// ChangedOnly lints the TypeScript files under ProjectRoot that git reports as changed since BaseRef
+	ChangedOnly bool `json:"changed_only,omitempty"`
+	// BaseRef is the git ref ChangedOnly compares against (HEAD, i.e. uncommitted changes, when empty)
+	BaseRef string `json:"base_ref,omitempty"`
+	// Format is "json" (the default), or "markdown" or "html" for a human-readable report
+	Format string `json:"format,omitempty"`
+	// FailOn is the severity that makes Passed false: "error" (the default), "warning" or
+	// "any" for any diagnostic at all
+	FailOn string `json:"fail_on,omitempty"`
+	// MaxWarnings makes Passed false when there are more warnings than it, like ESLint's
+	// --max-warnings; 0 allows none, and unset or negative means no limit
+	MaxWarnings *int `json:"max_warnings,omitempty"`
+	// QuietOnly reports errors only, like ESLint's --quiet
+	QuietOnly bool `json:"quiet_only,omitempty"`
 }
 
 // SuggestImprovementsParams represents parameters for code improvement suggestions
 type SuggestImprovementsParams struct {
-	CodeSnippet string `json:"code_snippet"`
-	Context     string `json:"context,omitempty"`
+	CodeSnippet string   `json:"code_snippet"`
+	Context     string   `json:"context,omitempty"`
+	MinPriority string   `json:"min_priority,omitempty"`
+	// MinConfidence drops improvements whose Confidence is below it, from 0 to 1
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	Types       []string `json:"types,omitempty"`
+	ProjectRoot string   `json:"project_root,omitempty"`
+	Include     []string `json:"include,omitempty"`
+	Exclude     []string `json:"exclude,omitempty"`
+	// OptionalChecks enables opt-in analyses such as "type_predicates"
+	OptionalChecks []string `json:"optional_checks,omitempty"`
+	// EnabledChecks runs only the named checks instead of the default set
+	EnabledChecks []string `json:"enabled_checks,omitempty"`
+	// DisabledChecks skips the named checks
+	DisabledChecks []string `json:"disabled_checks,omitempty"`
+	// LargeArrayThreshold is the element count above which "large_data" flags inline arrays
+	LargeArrayThreshold int `json:"large_array_threshold,omitempty"`
+	// ComplexityThreshold is the estimated cyclomatic complexity above which "complexity"
+	// flags a function (10 when zero)
+	ComplexityThreshold int `json:"complexity_threshold,omitempty"`
+	// NamingRules maps declaration kinds (interface, type, enum, class, function, const,
+	// variable, private_member) to the regex their names must match for
+	// "naming_conventions", on top of the server's rules; an empty pattern drops a rule
+	NamingRules map[string]string `json:"naming_rules,omitempty"`
+	// ReturnTypeScope is which functions "return_types" requires a return type on:
+	// "exported" (the default) or "all"
+	ReturnTypeScope string `json:"return_type_scope,omitempty"`
+	// MaxFunctionLines is the number of non-blank lines above which "function_length"
+	// flags a function (50 when zero)
+	MaxFunctionLines int `json:"max_function_lines,omitempty"`
+	// MaxParams is the parameter count above which "parameter_count" flags a function
+	// (4 when zero)
+	MaxParams int `json:"max_params,omitempty"`
+	// AllowedNumbers are numeric literals "magic_numbers" accepts besides 0, 1 and -1
+	AllowedNumbers []float64 `json:"allowed_numbers,omitempty"`
+	// AllowConsole disables console checks when true; when explicitly false,
+	// console.error and console.warn are flagged as well
+	AllowConsole *bool `json:"allow_console,omitempty"`
+	// Language is the source language of the snippet ("ts", "tsx", "mts" or "cts", or "js",
+	// "jsx", "mjs" or "cjs" to skip the TypeScript-only checks); JSX is also auto-detected
+	Language string `json:"language,omitempty"`
+	// MaxIssues caps the number of improvements returned (DefaultMaxIssues when zero)
+	MaxIssues int `json:"max_issues,omitempty"`
+	// SortByPriority lists high-priority improvements first (then by type); defaults to true
+	SortByPriority *bool `json:"sort_by_priority,omitempty"`
+	// IgnoreRules suppresses improvements by type (e.g. "code_cleanliness"), ID or matched
+	// guideline rule, in addition to those listed in the project's .tsassistant-ignore file
+	IgnoreRules []string `json:"ignore_rules,omitempty"`
+	// Progress is called as project files are analyzed; it is set by the server, not by clients
+	Progress ProgressFunc `json:"-"`
+	// ChangedOnly analyzes only the project files git reports as changed since BaseRef
+	ChangedOnly bool `json:"changed_only,omitempty"`
+	// BaseRef is the git ref ChangedOnly compares against (HEAD, i.e. uncommitted changes, when empty)
+	BaseRef string `json:"base_ref,omitempty"`
+	// ChangedFiles are the absolute paths ChangedOnly restricts analysis to; it is set by
+	// the server, not by clients
+	ChangedFiles []string `json:"-"`
+	// Format is "json" (the default), or "markdown" or "html" for a human-readable report
+	Format string `json:"format,omitempty"`
 }
 
 // LoadGuidelinesParams represents parameters for loading coding guidelines
@@ -32,12 +289,147 @@ type LoadGuidelinesParams struct {
 	GuidelineType string `json:"guideline_type,omitempty"`
 }
 
+// ValidateGuidelinesParams represents parameters for validating a guideline file without loading it
+type ValidateGuidelinesParams struct {
+	GuidelinePath string `json:"guideline_path"`
+	GuidelineType string `json:"guideline_type,omitempty"`
+}
+
+// RunTestsParams represents parameters for running a project's jest or vitest suite
+type RunTestsParams struct {
+	ProjectRoot string `json:"project_root,omitempty"`
+	// FilePath limits the run to a single test file
+	FilePath string `json:"file_path,omitempty"`
+	// TestNamePattern only runs tests whose name matches this regular expression
+	TestNamePattern string `json:"test_name_pattern,omitempty"`
+	// Runner forces "jest" or "vitest" instead of detecting it from package.json
+	Runner string `json:"runner,omitempty"`
+}
+
+// CheckDependenciesParams represents parameters for checking a project's npm dependencies
+type CheckDependenciesParams struct {
+	ProjectRoot string `json:"project_root"`
+	// Audit also runs npm audit and reports known vulnerabilities
+	Audit bool `json:"audit,omitempty"`
+}
+
+// ImportGraphParams represents parameters for building a project's module dependency graph
+type ImportGraphParams struct {
+	ProjectRoot string   `json:"project_root"`
+	Include     []string `json:"include,omitempty"`
+	Exclude     []string `json:"exclude,omitempty"`
+}
+
+// ApplyImprovementParams represents parameters for applying a suggested improvement to a file
+type ApplyImprovementParams struct {
+	FilePath string `json:"file_path"`
+	// ImprovementID applies the improvement with this ID from a fresh analysis of the file
+	// instead of an explicit Before/After pair
+	ImprovementID string `json:"improvement_id,omitempty"`
+	Before        string `json:"before,omitempty"`
+	After         string `json:"after,omitempty"`
+	// Line picks which occurrence of Before to replace when it appears more than once
+	Line int `json:"line,omitempty"`
+	// Backup keeps a copy of the original file next to it with a .bak extension
+	Backup bool `json:"backup,omitempty"`
+}
+
+// ReviewParams represents parameters for a combined type-check, lint and improvement review
+type ReviewParams struct {
+	FilePath string `json:"file_path,omitempty"`
+	// FilePaths reviews several files instead of FilePath and aggregates the results
+	FilePaths []string `json:"file_paths,omitempty"`
+	// Glob selects the files to review under ProjectRoot, e.g. "src/**/*.ts"
+	Glob string `json:"glob,omitempty"`
+	// ProjectRoot is the directory Glob is matched against (the server's project root when empty)
+	ProjectRoot string `json:"project_root,omitempty"`
+	// Concurrency caps how many files are reviewed at once (runtime.NumCPU() when zero)
+	Concurrency int `json:"concurrency,omitempty"`
+	// Format is "json" (the default), or "markdown" or "html" for a human-readable report
+	Format string `json:"format,omitempty"`
+}
+
+// DoctorParams represents parameters for the environment diagnostic
+type DoctorParams struct {
+	// ProjectRoot is where the tsconfig.json to verify lives (the server's project root when empty)
+	ProjectRoot string `json:"project_root,omitempty"`
+}
+
+// InitTSConfigParams represents parameters for generating a recommended tsconfig.json
+type InitTSConfigParams struct {
+	// ProjectRoot is the project to inspect and write to (the server's project root when empty)
+	ProjectRoot string `json:"project_root,omitempty"`
+	// Force overwrites an existing tsconfig
+	Force bool `json:"force,omitempty"`
+	// DryRun returns the recommended config without writing it
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// AuditTSConfigParams represents parameters for auditing a tsconfig's strictness
+type AuditTSConfigParams struct {
+	// ProjectRoot is where the tsconfig lives (the server's project root when empty)
+	ProjectRoot string `json:"project_root,omitempty"`
+	// ConfigPath audits this tsconfig file instead of the one in ProjectRoot
+	ConfigPath string `json:"config_path,omitempty"`
+}
+
+// EstimateSizeParams represents parameters for estimating code size
+type EstimateSizeParams struct {
+	FilePath    string `json:"file_path,omitempty"`
+	CodeSnippet string `json:"code_snippet,omitempty"`
+}
+
+// TranspileParams represents parameters for emitting JavaScript with the TypeScript compiler
+type TranspileParams struct {
+	FilePath       string `json:"file_path"`
+	OutDir         string `json:"out_dir,omitempty"`
+	Target         string `json:"target,omitempty"`
+	Module         string `json:"module,omitempty"`
+	IncludeContent bool   `json:"include_content,omitempty"`
+}
+
 // TypeCheckResult represents the result of TypeScript type checking
 type TypeCheckResult struct {
-	Success     bool               `json:"success"`
-	Errors      []TypeScriptError  `json:"errors,omitempty"`
-	Warnings    []TypeScriptError  `json:"warnings,omitempty"`
-	CompileTime string             `json:"compile_time,omitempty"`
+	Success          bool              `json:"success"`
+	// Passed reports whether the diagnostics stay below the FailOn threshold, unlike
+	// Success, which only reflects tsc's exit code
+	Passed bool `json:"passed"`
+	Errors           []TypeScriptError `json:"errors,omitempty"`
+	Warnings         []TypeScriptError `json:"warnings,omitempty"`
+	CompileTime      string            `json:"compile_time,omitempty"`
+	Mode             string            `json:"mode,omitempty"`
+	OutdatedProjects []string          `json:"outdated_projects,omitempty"`
+	Binary           string            `json:"binary,omitempty"`
+	ExitCode         int               `json:"exit_code,omitempty"`
+	RawOutput        string            `json:"raw_output,omitempty"`
+	// ChangedFiles lists the files diagnostics were restricted to by ChangedOnly
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// IncrementalCacheUsed reports whether a previous .tsbuildinfo was reused
+	IncrementalCacheUsed bool `json:"incremental_cache_used,omitempty"`
+	// CountsByCode maps each diagnostic code (e.g. "TS2322") to how often it was reported
+	CountsByCode map[string]int `json:"counts_by_code,omitempty"`
+	// Total is the number of errors and warnings reported, including any dropped by MaxIssues
+	Total int `json:"total"`
+	// ErrorCount and WarningCount split Total by severity
+	ErrorCount   int `json:"error_count"`
+	WarningCount int `json:"warning_count"`
+	// Truncated is set when Errors and Warnings were cut down to MaxIssues entries
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TypeCheckDiffResult represents the errors introduced since a baseline
+type TypeCheckDiffResult struct {
+	// Success is set when no errors were introduced
+	Success     bool              `json:"success"`
+	NewErrors   []TypeScriptError `json:"new_errors"`
+	BaselineRef string            `json:"baseline_ref,omitempty"`
+	// BaselineErrors and CurrentErrors count the errors before and after the change
+	BaselineErrors int `json:"baseline_errors"`
+	CurrentErrors  int `json:"current_errors"`
+	// FixedErrors counts baseline errors that are no longer reported
+	FixedErrors int    `json:"fixed_errors"`
+	Truncated   bool   `json:"truncated,omitempty"`
+	Summary     string `json:"summary"`
 }
 
 // TypeScriptError represents a TypeScript compiler error or warning
@@ -48,6 +440,9 @@ type TypeScriptError struct {
 	Message  string `json:"message"`
 	Code     string `json:"code,omitempty"`
 	Severity string `json:"severity"`
+	// Details holds the continuation lines tsc prints below the message, such as
+	// "Type 'A' is not assignable to type 'B'" elaborations
+	Details []string `json:"details,omitempty"`
 }
 
 // TypeInfo represents type information for a symbol
@@ -56,8 +451,143 @@ type TypeInfo struct {
 	Type         string            `json:"type"`
 	Kind         string            `json:"kind"`
 	Documentation string           `json:"documentation,omitempty"`
+	// QuickInfo is the hover text an editor shows for the symbol
+	QuickInfo string `json:"quick_info,omitempty"`
 	Location     *SourceLocation   `json:"location,omitempty"`
 	Properties   []PropertyInfo    `json:"properties,omitempty"`
+	// Error explains why a symbol in a get-types-batch request couldn't be resolved
+	Error string `json:"error,omitempty"`
+}
+
+// LocationsResult represents the locations found for a symbol, sorted by file and line
+type LocationsResult struct {
+	SymbolName string           `json:"symbol_name"`
+	Locations  []SourceLocation `json:"locations"`
+	Total      int              `json:"total"`
+}
+
+// RenameResult represents the edits that rename a symbol
+type RenameResult struct {
+	SymbolName string     `json:"symbol_name"`
+	NewName    string     `json:"new_name"`
+	Edits      []TextEdit `json:"edits"`
+	Files      []string   `json:"files"`
+	Applied    bool       `json:"applied"`
+	Summary    string     `json:"summary"`
+}
+
+// TextEdit represents replacing a range of a file with new text. Lines and columns are
+// 1-based, with columns counted in UTF-16 code units as reported by TypeScript.
+type TextEdit struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"end_line"`
+	EndColumn int    `json:"end_column"`
+	NewText   string `json:"new_text"`
+}
+
+// CompletionResult represents the completion entries at a position
+type CompletionResult struct {
+	Entries []CompletionEntry `json:"entries"`
+	// Total is the number of entries matching the prefix before MaxEntries was applied
+	Total              int  `json:"total"`
+	Truncated          bool `json:"truncated,omitempty"`
+	IsMemberCompletion bool `json:"is_member_completion"`
+}
+
+// CompletionEntry represents a single completion suggestion
+type CompletionEntry struct {
+	Label         string `json:"label"`
+	Kind          string `json:"kind"`
+	Detail        string `json:"detail,omitempty"`
+	Documentation string `json:"documentation,omitempty"`
+	// InsertText is the text to insert when it differs from Label
+	InsertText string `json:"insert_text,omitempty"`
+	// Source is the module an auto-import completion comes from
+	Source string `json:"source,omitempty"`
+}
+
+// TSConfig represents a recommended tsconfig.json
+type TSConfig struct {
+	CompilerOptions TSCompilerOptions `json:"compilerOptions"`
+	Include         []string          `json:"include,omitempty"`
+	Exclude         []string          `json:"exclude,omitempty"`
+	// Detected lists the project traits the recommendation is based on
+	Detected []string `json:"-"`
+}
+
+// TSCompilerOptions represents the compilerOptions of a recommended tsconfig.json
+type TSCompilerOptions struct {
+	Target                           string   `json:"target"`
+	Module                           string   `json:"module"`
+	ModuleResolution                 string   `json:"moduleResolution"`
+	Lib                              []string `json:"lib,omitempty"`
+	JSX                              string   `json:"jsx,omitempty"`
+	Types                            []string `json:"types,omitempty"`
+	Strict                           bool     `json:"strict"`
+	NoUncheckedIndexedAccess         bool     `json:"noUncheckedIndexedAccess"`
+	NoImplicitOverride               bool     `json:"noImplicitOverride"`
+	NoFallthroughCasesInSwitch       bool     `json:"noFallthroughCasesInSwitch"`
+	ForceConsistentCasingInFileNames bool     `json:"forceConsistentCasingInFileNames"`
+	EsModuleInterop                  bool     `json:"esModuleInterop"`
+	IsolatedModules                  bool     `json:"isolatedModules"`
+	ResolveJSONModule                bool     `json:"resolveJsonModule"`
+	SkipLibCheck                     bool     `json:"skipLibCheck"`
+	NoEmit                           bool     `json:"noEmit,omitempty"`
+	Declaration                      bool     `json:"declaration,omitempty"`
+	RootDir                          string   `json:"rootDir,omitempty"`
+	OutDir                           string   `json:"outDir,omitempty"`
+}
+
+// InitTSConfigResult represents the outcome of generating a tsconfig.json
+type InitTSConfigResult struct {
+	Path     string   `json:"path"`
+	Written  bool     `json:"written"`
+	Detected []string `json:"detected"`
+	Content  string   `json:"content"`
+	Summary  string   `json:"summary"`
+}
+
+// ResolvedTSConfig represents a tsconfig with its extends chain merged in
+type ResolvedTSConfig struct {
+	Path string `json:"path"`
+	// Extends lists the inherited config files, nearest first
+	Extends         []string               `json:"extends,omitempty"`
+	CompilerOptions map[string]interface{} `json:"compilerOptions"`
+	Files           []string               `json:"files,omitempty"`
+	Include         []string               `json:"include,omitempty"`
+	Exclude         []string               `json:"exclude,omitempty"`
+}
+
+// TSConfigAudit represents the strictness audit of a tsconfig
+type TSConfigAudit struct {
+	ConfigPath string   `json:"config_path"`
+	Extends    []string `json:"extends,omitempty"`
+	// Score is the percentage of audited flags that are enabled
+	Score           int                      `json:"score"`
+	Flags           []StrictnessFlag         `json:"flags"`
+	Recommendations []TSConfigRecommendation `json:"recommendations"`
+	Summary         string                   `json:"summary"`
+}
+
+// StrictnessFlag represents the effective value of a compiler strictness flag
+type StrictnessFlag struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// Source is "explicit" when set in the config (or a config it extends), "strict" when
+	// implied by the strict flag and "default" otherwise
+	Source string `json:"source"`
+}
+
+// TSConfigRecommendation represents a suggested change to a tsconfig
+type TSConfigRecommendation struct {
+	Flag     string `json:"flag"`
+	Change   string `json:"change"`
+	Priority string `json:"priority"`
+	// Risk describes how much existing code is likely to break when the flag is enabled
+	Risk   string `json:"risk"`
+	Impact string `json:"impact"`
 }
 
 // SourceLocation represents a location in source code
@@ -77,25 +607,120 @@ type PropertyInfo struct {
 
 // LintResult represents the result of ESLint checking
 type LintResult struct {
-	Success  bool        `json:"success"`
-	Issues   []LintIssue `json:"issues,omitempty"`
-	Fixable  int         `json:"fixable_count"`
-	Summary  string      `json:"summary"`
+	Success bool        `json:"success"`
+	Issues  []LintIssue `json:"issues,omitempty"`
+	Fixable int         `json:"fixable_count"`
+	Summary string      `json:"summary"`
+	Binary  string      `json:"binary,omitempty"`
+	// ConfigFormat is "flat" for eslint.config.* configuration or "eslintrc" for the legacy format
+	ConfigFormat string `json:"config_format,omitempty"`
+	// Truncated is set when Issues was cut down to MaxIssues entries
+	Truncated   bool `json:"truncated,omitempty"`
+	TotalIssues int  `json:"total_issues"`
+	// ChangedFiles lists the files linted because of ChangedOnly
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// ErrorCount and WarningCount count every issue, including any dropped by MaxIssues
+	ErrorCount   int `json:"error_count"`
+	WarningCount int `json:"warning_count"`
+	// Passed reports whether the issues stay below the FailOn threshold and MaxWarnings,
+	// unlike Success, which only reflects ESLint's exit code
+	Passed bool `json:"passed"`
+	// WarningsExceeded is set when WarningCount is above MaxWarnings
+	WarningsExceeded bool `json:"warnings_exceeded,omitempty"`
+}
+
+// ListESLintRulesParams represents parameters for listing the ESLint rules configured
+// for a file
+type ListESLintRulesParams struct {
+	FilePath string `json:"file_path"`
+}
+
+// ESLintRulesResult represents the ESLint rules configured for a file, grouped by plugin
+type ESLintRulesResult struct {
+	File     string            `json:"file"`
+	Plugins  []ESLintRuleGroup `json:"plugins"`
+	Total    int               `json:"total"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+	Off      int               `json:"off"`
+	Summary  string            `json:"summary"`
+}
+
+// ESLintRuleGroup represents the configured rules of one plugin, sorted by name
+type ESLintRuleGroup struct {
+	// Plugin is the rule prefix, such as "@typescript-eslint", or "core" for ESLint's own rules
+	Plugin string       `json:"plugin"`
+	Rules  []RuleStatus `json:"rules"`
+}
+
+// RuleStatus represents a configured ESLint rule
+type RuleStatus struct {
+	Name string `json:"name"`
+	// Severity is "off", "warn" or "error"
+	Severity    string        `json:"severity"`
+	Options     []interface{} `json:"options,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Description string        `json:"description,omitempty"`
+}
+
+// StatsParams represents parameters for the server usage statistics
+type StatsParams struct {
+	// Reset clears the counters after reporting them
+	Reset bool `json:"reset,omitempty"`
+}
+
+// StatsResult represents the server's usage since it started or was last reset
+type StatsResult struct {
+	// Since is when counting started, in RFC 3339 format
+	Since         string      `json:"since"`
+	Tools         []ToolStats `json:"tools"`
+	TotalCalls    int         `json:"total_calls"`
+	TotalFailures int         `json:"total_failures"`
+	TotalIssues   int         `json:"total_issues"`
+	// CacheHits and CacheMisses count incremental type checks that did and didn't reuse
+	// a previous .tsbuildinfo, and CacheHitRate is the share of hits from 0 to 1
+	CacheHits    int     `json:"cache_hits"`
+	CacheMisses  int     `json:"cache_misses"`
+	CacheHitRate float64 `json:"cache_hit_rate"`
+	Reset        bool    `json:"reset,omitempty"`
+}
+
+// ToolStats represents the usage of one tool
+type ToolStats struct {
+	Name  string `json:"name"`
+	Calls int    `json:"calls"`
+	// Failures counts calls that returned an error
+	Failures int `json:"failures"`
+	// IssuesFound counts the type errors, lint issues and improvements the tool reported
+	IssuesFound    int    `json:"issues_found"`
+	AverageLatency string `json:"average_latency,omitempty"`
 }
 
 // LintIssue represents an ESLint issue
 type LintIssue struct {
-	File     string `json:"file"`
-	Line     int    `json:"line"`
-	Column   int    `json:"column"`
-	Message  string `json:"message"`
-	Rule     string `json:"rule"`
-	Severity string `json:"severity"`
-	Fixable  bool   `json:"fixable"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Message  string   `json:"message"`
+	Rule     string   `json:"rule"`
+	Severity string   `json:"severity"`
+	Fixable  bool     `json:"fixable"`
+	Fix      *FixInfo `json:"fix,omitempty"`
+	// RuleURL and RuleDescription document the rule when IncludeRuleDocs is set
+	RuleURL         string `json:"rule_url,omitempty"`
+	RuleDescription string `json:"rule_description,omitempty"`
+}
+
+// FixInfo represents an automatic fix as a byte range to replace and its replacement text
+type FixInfo struct {
+	Range []int  `json:"range"`
+	Text  string `json:"text"`
 }
 
 // Improvement represents a code improvement suggestion
 type Improvement struct {
+	// ID identifies the improvement across repeated analyses of the same code
+	ID           string `json:"id"`
 	Type         string `json:"type"`
 	Description  string `json:"description"`
 	Before       string `json:"before,omitempty"`
@@ -103,41 +728,220 @@ type Improvement struct {
 	Reasoning    string `json:"reasoning"`
 	Priority     string `json:"priority"`
 	GuidelineRef string `json:"guideline_ref,omitempty"`
+	MatchedRule  string `json:"matched_rule,omitempty"`
+	Line         int    `json:"line,omitempty"`
+	Column       int    `json:"column,omitempty"`
+	Occurrences  int    `json:"occurrences,omitempty"`
+	Diff         string `json:"diff,omitempty"`
+	// Confidence is how likely the heuristic match behind the improvement is right, from
+	// 0 to 1
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // ImprovementResult represents the result of improvement suggestions
 type ImprovementResult struct {
+	Improvements []Improvement      `json:"improvements"`
+	Summary      string             `json:"summary"`
+	AppliedRules []string           `json:"applied_rules,omitempty"`
+	Files        []FileImprovements `json:"files,omitempty"`
+	Truncated    bool               `json:"truncated,omitempty"`
+	Note         string             `json:"note,omitempty"`
+	// TotalIssues counts every improvement found, including any dropped by MaxIssues
+	TotalIssues int `json:"total_issues"`
+	// Score is a 0-100 code health score: 100 minus a weighted penalty per improvement
+	Score int `json:"score"`
+	// Suppressed counts the improvements dropped by IgnoreRules or .tsassistant-ignore
+	Suppressed int `json:"suppressed,omitempty"`
+	// ChangedFiles lists the files analyzed because of ChangedOnly
+	ChangedFiles []string `json:"changed_files,omitempty"`
+}
+
+// ReviewResult combines the type-check, lint and improvement results for a single file
+type ReviewResult struct {
+	FilePath     string             `json:"file_path"`
+	TypeCheck    *TypeCheckResult   `json:"type_check,omitempty"`
+	Lint         *LintResult        `json:"lint,omitempty"`
+	Improvements *ImprovementResult `json:"improvements,omitempty"`
+	// StepErrors maps a step ("type_check", "lint" or "improvements") to the reason it failed
+	StepErrors map[string]string `json:"step_errors,omitempty"`
+	// Score rates the file from 0 to 100, deducting points for each finding. For a
+	// multi-file review it is the average of the per-file scores.
+	Score   int    `json:"score"`
+	Summary string `json:"summary"`
+	// Files holds the per-file reviews of a multi-file review, sorted by path
+	Files []ReviewResult `json:"files,omitempty"`
+	// Totals counts the findings across all files of a multi-file review
+	Totals *ReviewTotals `json:"totals,omitempty"`
+}
+
+// ReviewTotals counts the findings of a multi-file review
+type ReviewTotals struct {
+	Files int `json:"files"`
+	// Errors counts type errors and ESLint errors
+	Errors int `json:"errors"`
+	// Warnings counts type and ESLint warnings
+	Warnings     int `json:"warnings"`
+	Improvements int `json:"improvements"`
+}
+
+// DoctorReport is the result of the environment diagnostic
+type DoctorReport struct {
+	// Healthy is false when any check failed; warnings don't affect it
+	Healthy bool          `json:"healthy"`
+	Checks  []DoctorCheck `json:"checks"`
+	Summary string        `json:"summary"`
+}
+
+// DoctorCheck is the outcome of a single environment check
+type DoctorCheck struct {
+	Name string `json:"name"`
+	// Status is "ok", "warning" or "failed"
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Remediation suggests how to fix a warning or failure
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// FileImprovements represents the improvement suggestions for a single file
+type FileImprovements struct {
+	FilePath     string        `json:"file_path"`
 	Improvements []Improvement `json:"improvements"`
-	Summary      string        `json:"summary"`
-	AppliedRules []string      `json:"applied_rules,omitempty"`
+}
+
+// TranspileResult represents the result of emitting JavaScript with the TypeScript compiler
+type TranspileResult struct {
+	Success      bool              `json:"success"`
+	OutDir       string            `json:"out_dir"`
+	EmittedFiles []EmittedFile     `json:"emitted_files,omitempty"`
+	Errors       []TypeScriptError `json:"errors,omitempty"`
+	CompileTime  string            `json:"compile_time,omitempty"`
+}
+
+// TestResult represents the outcome of a jest or vitest run
+type TestResult struct {
+	Success   bool          `json:"success"`
+	Runner    string        `json:"runner"`
+	Binary    string        `json:"binary,omitempty"`
+	Passed    int           `json:"passed"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Total     int           `json:"total"`
+	Failures  []TestFailure `json:"failures,omitempty"`
+	Summary   string        `json:"summary"`
+	Duration  string        `json:"duration,omitempty"`
+	RawOutput string        `json:"raw_output,omitempty"`
+}
+
+// TestFailure represents a single failing test and where it failed
+type TestFailure struct {
+	File    string `json:"file"`
+	Test    string `json:"test"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// DependencyReport represents the outdated packages and vulnerabilities of a project
+type DependencyReport struct {
+	Outdated        []OutdatedPackage     `json:"outdated"`
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+	Summary         string                `json:"summary"`
+}
+
+// OutdatedPackage represents a dependency with a newer version available
+type OutdatedPackage struct {
+	Name    string `json:"name"`
+	Current string `json:"current,omitempty"`
+	Wanted  string `json:"wanted"`
+	Latest  string `json:"latest"`
+	// Type is the package.json section the dependency is declared in, e.g. "devDependencies"
+	Type     string `json:"type,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// VulnerabilitySummary represents the result of npm audit
+type VulnerabilitySummary struct {
+	// Counts maps a severity ("low", "moderate", "high", "critical", ...) to the number of vulnerabilities
+	Counts   map[string]int      `json:"counts"`
+	Packages []VulnerablePackage `json:"packages,omitempty"`
+}
+
+// VulnerablePackage represents a package with a known vulnerability
+type VulnerablePackage struct {
+	Name         string `json:"name"`
+	Severity     string `json:"severity"`
+	Range        string `json:"range,omitempty"`
+	FixAvailable bool   `json:"fix_available"`
+}
+
+// ImportGraph represents the module dependency graph of a project. Nodes are file
+// paths relative to the project root.
+type ImportGraph struct {
+	Nodes []string     `json:"nodes"`
+	Edges []ImportEdge `json:"edges"`
+	// Cycles lists groups of modules that import each other, ignoring type-only imports
+	Cycles [][]string `json:"cycles"`
+	// External lists the package specifiers imported from outside the project
+	External  []string `json:"external,omitempty"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// ImportEdge represents an import of one project module by another
+type ImportEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	TypeOnly bool   `json:"type_only,omitempty"`
+}
+
+// EmittedFile represents a file written by the TypeScript compiler
+type EmittedFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+}
+
+// SizeEstimate represents the estimated size of a file or code snippet
+type SizeEstimate struct {
+	Bytes        int `json:"bytes"`
+	Lines        int `json:"lines"`
+	ApproxTokens int `json:"approx_tokens"`
+}
+
+// IgnoredResult is returned instead of running a tool on a path excluded by the server configuration
+type IgnoredResult struct {
+	FilePath string `json:"file_path"`
+	Ignored  bool   `json:"ignored"`
+	Reason   string `json:"reason"`
 }
 
 // Guideline represents a coding guideline
 type Guideline struct {
-	ID          string            `json:"id"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Category    string            `json:"category"`
-	Priority    string            `json:"priority"`
-	Examples    []GuidelineExample `json:"examples,omitempty"`
-	Rules       []string          `json:"rules,omitempty"`
+	ID          string             `json:"id" yaml:"id"`
+	Title       string             `json:"title" yaml:"title"`
+	Description string             `json:"description" yaml:"description"`
+	Category    string             `json:"category" yaml:"category"`
+	Priority    string             `json:"priority" yaml:"priority"`
+	Examples    []GuidelineExample `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Rules       []string           `json:"rules,omitempty" yaml:"rules,omitempty"`
+	Patterns    []string           `json:"patterns,omitempty" yaml:"patterns,omitempty"`
 }
 
 // GuidelineExample represents an example in a guideline
 type GuidelineExample struct {
-	Title       string `json:"title"`
-	Good        string `json:"good,omitempty"`
-	Bad         string `json:"bad,omitempty"`
-	Explanation string `json:"explanation"`
+	Title       string `json:"title" yaml:"title"`
+	Good        string `json:"good,omitempty" yaml:"good,omitempty"`
+	Bad         string `json:"bad,omitempty" yaml:"bad,omitempty"`
+	Explanation string `json:"explanation" yaml:"explanation"`
+	Language    string `json:"language,omitempty" yaml:"language,omitempty"`
 }
 
 // GuidelineSet represents a collection of guidelines
 type GuidelineSet struct {
-	Name        string      `json:"name"`
-	Version     string      `json:"version"`
-	Description string      `json:"description"`
-	Guidelines  []Guideline `json:"guidelines"`
-	LoadedAt    string      `json:"loaded_at"`
+	Name        string      `json:"name" yaml:"name"`
+	Version     string      `json:"version" yaml:"version"`
+	Description string      `json:"description" yaml:"description"`
+	Guidelines  []Guideline `json:"guidelines" yaml:"guidelines"`
+	LoadedAt    string      `json:"loaded_at" yaml:"loaded_at"`
+	Format      string      `json:"format,omitempty" yaml:"-"`
 }
 
 // String methods for better logging